@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/vadgun/gotrelloclone/board-service/infra/logger"
 	"github.com/vadgun/gotrelloclone/board-service/infra/metrics"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
 	"github.com/vadgun/gotrelloclone/board-service/services"
+	"github.com/vadgun/gotrelloclone/common/etag"
 	"go.uber.org/zap"
 )
 
@@ -44,10 +48,11 @@ func (h *BoardHandler) CreateBoard(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, gin.H{"board": board})
 }
 
+// GetBoards devuelve los tableros del usuario, los archivados solo con ?include_archived=true
 func (h *BoardHandler) GetBoards(ctx *gin.Context) {
 	userID, _ := ctx.Get("userID")
 
-	boards, err := h.service.GetBoardsByUser(userID.(string))
+	boards, err := h.service.GetBoardsByUser(userID.(string), ctx.Query("include_archived") == "true")
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los tableros"})
 		return
@@ -64,18 +69,85 @@ func (h *BoardHandler) GetBoardByID(ctx *gin.Context) {
 		return
 	}
 
+	etag.Set(ctx, tasks.Version)
 	ctx.JSON(http.StatusOK, tasks)
 }
 
 func (h *BoardHandler) DeleteBoardByID(ctx *gin.Context) {
 	boardID := ctx.Param("boardID")
-	err := h.service.DeleteBoardByID(boardID)
+	userID, _ := ctx.Get("userID")
+	board, err := h.service.DeleteBoardByID(boardID, userID.(string))
+	if errors.Is(err, repositories.ErrBoardNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo eliminar el tablero"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, "")
+	// Las tareas se envían a la papelera en task-service, su conteo aparece en trashed_tasks de GET /boards/trash
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tablero enviado a la papelera", "board": board})
+}
+
+// ArchiveBoard oculta el tablero de GET /boards y lo deja en solo lectura
+func (h *BoardHandler) ArchiveBoard(ctx *gin.Context) {
+	h.archiveBoard(ctx, true)
+}
+
+func (h *BoardHandler) UnarchiveBoard(ctx *gin.Context) {
+	h.archiveBoard(ctx, false)
+}
+
+func (h *BoardHandler) archiveBoard(ctx *gin.Context, archived bool) {
+	userID, _ := ctx.Get("userID")
+
+	board, err := h.service.ArchiveBoard(ctx.Param("boardID"), userID.(string), archived)
+	if errors.Is(err, repositories.ErrBoardNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo archivar el tablero"})
+		return
+	}
+
+	etag.Set(ctx, board.Version)
+	ctx.JSON(http.StatusOK, gin.H{"board": board})
+}
+
+// GetTrash lista los tableros del usuario que están en la papelera
+func (h *BoardHandler) GetTrash(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	boards, err := h.service.GetTrash(userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo obtener la papelera"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"boards": boards})
+}
+
+// RestoreBoard saca el tablero de la papelera junto con las tareas que se eliminaron con él
+func (h *BoardHandler) RestoreBoard(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	board, restoredTasks, err := h.service.RestoreBoard(ctx.Param("boardID"), userID.(string))
+	switch {
+	case errors.Is(err, repositories.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado en la papelera"})
+		return
+	case errors.Is(err, services.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "Solo el owner puede restaurar el tablero"})
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo restaurar el tablero"})
+		return
+	}
+
+	etag.Set(ctx, board.Version)
+	ctx.JSON(http.StatusOK, gin.H{"board": board, "restored_tasks": restoredTasks})
 }
 
 func (h *BoardHandler) UpdateBoardByID(ctx *gin.Context) {
@@ -89,13 +161,69 @@ func (h *BoardHandler) UpdateBoardByID(ctx *gin.Context) {
 		return
 	}
 
-	err := h.service.UpdateBoardByID(boardID, request.Name)
+	// Solo se guarda si nadie cambió el tablero desde la versión que editó el cliente
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	board, err := h.service.UpdateBoardByID(boardID, request.Name, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		current, err := h.service.GetBoardByID(boardID)
+		if err != nil {
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+			return
+		}
+		etag.Set(ctx, current.Version)
+		ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "El tablero fue modificado por otro usuario", "board": current})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar el tablero"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, "")
+	etag.Set(ctx, board.Version)
+	ctx.JSON(http.StatusOK, gin.H{"board": board})
+}
+
+func (h *BoardHandler) GetWorkflow(ctx *gin.Context) {
+	workflow, err := h.service.GetWorkflow(ctx.Param("boardID"))
+	if err != nil {
+		respondWorkflowError(ctx, err, "No se pudo obtener el flujo del tablero")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"workflow": workflow})
+}
+
+func (h *BoardHandler) UpdateWorkflow(ctx *gin.Context) {
+	var workflow models.Workflow
+	if err := ctx.ShouldBindJSON(&workflow); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.service.UpdateWorkflow(ctx.Param("boardID"), &workflow)
+	if err != nil {
+		respondWorkflowError(ctx, err, "No se pudo actualizar el flujo del tablero")
+		return
+	}
+
+	logger.Log.Info("Actualizando flujo del tablero", zap.String("endpoint", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()))
+
+	ctx.JSON(http.StatusOK, gin.H{"workflow": updated})
+}
+
+func respondWorkflowError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+	case errors.Is(err, services.ErrInvalidWorkflow):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
 }
 
 func (h *BoardHandler) GetAllBoards(ctx *gin.Context) {
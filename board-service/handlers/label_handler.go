@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+type LabelHandler struct {
+	service *services.LabelService
+}
+
+func NewLabelHandler(service *services.LabelService) *LabelHandler {
+	return &LabelHandler{service}
+}
+
+type labelRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Color string `json:"color" binding:"required"`
+}
+
+func (h *LabelHandler) GetLabels(ctx *gin.Context) {
+	labels, err := h.service.GetLabels(ctx.Param("boardID"))
+	if err != nil {
+		respondLabelError(ctx, err, "No se pudieron obtener las etiquetas")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"labels": labels})
+}
+
+func (h *LabelHandler) CreateLabel(ctx *gin.Context) {
+	var request labelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := h.service.CreateLabel(ctx.Param("boardID"), request.Name, request.Color)
+	if err != nil {
+		respondLabelError(ctx, err, "No se pudo crear la etiqueta")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"label": label})
+}
+
+func (h *LabelHandler) UpdateLabel(ctx *gin.Context) {
+	var request labelRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	label, err := h.service.UpdateLabel(ctx.Param("boardID"), ctx.Param("labelID"), request.Name, request.Color)
+	if err != nil {
+		respondLabelError(ctx, err, "No se pudo actualizar la etiqueta")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"label": label})
+}
+
+// DeleteLabel elimina la etiqueta del tablero, las tareas que la tenían la pierden
+func (h *LabelHandler) DeleteLabel(ctx *gin.Context) {
+	if err := h.service.DeleteLabel(ctx.Param("boardID"), ctx.Param("labelID")); err != nil {
+		respondLabelError(ctx, err, "No se pudo eliminar la etiqueta")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Etiqueta eliminada"})
+}
+
+func respondLabelError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+	case errors.Is(err, repositories.ErrLabelNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Etiqueta no encontrada"})
+	case errors.Is(err, services.ErrInvalidLabel):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+type ListHandler struct {
+	service *services.ListService
+}
+
+func NewListHandler(service *services.ListService) *ListHandler {
+	return &ListHandler{service}
+}
+
+func (h *ListHandler) CreateList(ctx *gin.Context) {
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.service.CreateList(ctx.Param("boardID"), request.Name)
+	if err != nil {
+		respondListError(ctx, err, "No se pudo crear la lista")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"list": list})
+}
+
+// GetLists devuelve las listas del tablero en orden, las archivadas solo con ?include_archived=true
+func (h *ListHandler) GetLists(ctx *gin.Context) {
+	lists, err := h.service.GetLists(ctx.Param("boardID"), ctx.Query("include_archived") == "true")
+	if err != nil {
+		respondListError(ctx, err, "No se pudieron obtener las listas")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"lists": lists})
+}
+
+func (h *ListHandler) RenameList(ctx *gin.Context) {
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.service.RenameList(ctx.Param("boardID"), ctx.Param("listID"), request.Name)
+	if err != nil {
+		respondListError(ctx, err, "No se pudo renombrar la lista")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"list": list})
+}
+
+// MoveList reordena la lista indicando la lista que queda antes y/o después de ella
+func (h *ListHandler) MoveList(ctx *gin.Context) {
+	var request struct {
+		BeforeID string `json:"before_id"`
+		AfterID  string `json:"after_id"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	list, err := h.service.MoveList(ctx.Param("boardID"), ctx.Param("listID"), request.BeforeID, request.AfterID)
+	if err != nil {
+		respondListError(ctx, err, "No se pudo mover la lista")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"list": list})
+}
+
+func (h *ListHandler) ArchiveList(ctx *gin.Context) {
+	h.archiveList(ctx, true)
+}
+
+func (h *ListHandler) UnarchiveList(ctx *gin.Context) {
+	h.archiveList(ctx, false)
+}
+
+func (h *ListHandler) archiveList(ctx *gin.Context, archived bool) {
+	list, err := h.service.ArchiveList(ctx.Param("boardID"), ctx.Param("listID"), archived)
+	if err != nil {
+		respondListError(ctx, err, "No se pudo archivar la lista")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"list": list})
+}
+
+func respondListError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+	case errors.Is(err, repositories.ErrListNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Lista no encontrada"})
+	case errors.Is(err, services.ErrInvalidNeighbor):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNeighborConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Las listas cambiaron de posición, vuelve a cargar el tablero"})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+	"go.uber.org/zap"
+)
+
+type MemberHandler struct {
+	service *services.BoardService
+}
+
+func NewMemberHandler(service *services.BoardService) *MemberHandler {
+	return &MemberHandler{service}
+}
+
+// GetMembers devuelve los miembros del tablero con su rol
+func (h *MemberHandler) GetMembers(ctx *gin.Context) {
+	board := ctx.MustGet("board").(*models.Board)
+	ctx.JSON(http.StatusOK, gin.H{"owner_id": board.OwnerID, "members": board.Members})
+}
+
+// InviteMember invita a un usuario al tablero, pasa a ser miembro cuando acepta la invitación
+func (h *MemberHandler) InviteMember(ctx *gin.Context) {
+	var request struct {
+		UserID string           `json:"user_id" binding:"required"`
+		Role   models.BoardRole `json:"role" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	board := ctx.MustGet("board").(*models.Board)
+
+	invitation, err := h.service.InviteMember(board, userID.(string), request.UserID, request.Role)
+	if err != nil {
+		respondMemberError(ctx, err, "No se pudo invitar al usuario")
+		return
+	}
+
+	logger.Log.Info("Invitando usuario al tablero", zap.String("endpoint", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()))
+
+	ctx.JSON(http.StatusCreated, gin.H{"invitation": invitation})
+}
+
+// GetInvitations devuelve las invitaciones pendientes del usuario autenticado
+func (h *MemberHandler) GetInvitations(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	invitations, err := h.service.GetInvitations(userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener las invitaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// AcceptInvitation agrega al usuario autenticado al tablero con el rol de su invitación
+func (h *MemberHandler) AcceptInvitation(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	board, err := h.service.AcceptInvitation(ctx.Param("boardID"), userID.(string))
+	if err != nil {
+		respondMemberError(ctx, err, "No se pudo aceptar la invitación")
+		return
+	}
+
+	logger.Log.Info("Invitación al tablero aceptada", zap.String("endpoint", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()))
+
+	ctx.JSON(http.StatusOK, gin.H{"members": board.Members})
+}
+
+// RemoveInvitation rechaza la invitación del usuario autenticado o, para un admin, revoca la de otro usuario
+func (h *MemberHandler) RemoveInvitation(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	if err := h.service.RemoveInvitation(ctx.Param("boardID"), userID.(string), ctx.Param("userID")); err != nil {
+		respondMemberError(ctx, err, "No se pudo descartar la invitación")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Invitación descartada"})
+}
+
+// UpdateMemberRole cambia el rol de un miembro
+func (h *MemberHandler) UpdateMemberRole(ctx *gin.Context) {
+	var request struct {
+		Role models.BoardRole `json:"role" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	board := ctx.MustGet("board").(*models.Board)
+
+	board, err := h.service.UpdateMemberRole(board, userID.(string), ctx.Param("userID"), request.Role)
+	if err != nil {
+		respondMemberError(ctx, err, "No se pudo cambiar el rol del miembro")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"members": board.Members})
+}
+
+// RemoveMember quita a un miembro, también sirve para que un miembro salga del tablero
+func (h *MemberHandler) RemoveMember(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+	board := ctx.MustGet("board").(*models.Board)
+
+	board, err := h.service.RemoveMember(board, userID.(string), ctx.Param("userID"))
+	if err != nil {
+		respondMemberError(ctx, err, "No se pudo quitar el miembro")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"members": board.Members})
+}
+
+// TransferOwnership pasa el tablero a otro miembro
+func (h *MemberHandler) TransferOwnership(ctx *gin.Context) {
+	var request struct {
+		UserID string `json:"user_id" binding:"required"`
+	}
+
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, _ := ctx.Get("userID")
+	board := ctx.MustGet("board").(*models.Board)
+
+	board, err := h.service.TransferOwnership(board, userID.(string), request.UserID)
+	if err != nil {
+		respondMemberError(ctx, err, "No se pudo transferir el tablero")
+		return
+	}
+
+	logger.Log.Info("Transfiriendo tablero", zap.String("endpoint", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()))
+
+	ctx.JSON(http.StatusOK, gin.H{"owner_id": board.OwnerID, "members": board.Members})
+}
+
+func respondMemberError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repositories.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+	case errors.Is(err, repositories.ErrMemberNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Miembro no encontrado"})
+	case errors.Is(err, services.ErrUserNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El usuario no existe"})
+	case errors.Is(err, repositories.ErrInvitationNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Invitación no encontrada"})
+	case errors.Is(err, repositories.ErrMemberExists), errors.Is(err, repositories.ErrInvitationExists):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidRole):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrNotBoardOwner), errors.Is(err, services.ErrOwnerMember):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
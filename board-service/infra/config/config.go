@@ -15,6 +15,12 @@ import (
 var DB *mongo.Database
 var JWTSecret string
 
+// Papelera de tableros
+var (
+	TrashRetention     time.Duration // Tiempo que un tablero permanece en la papelera antes de eliminarse definitivamente
+	TrashPurgeInterval time.Duration // Cada cuánto se buscan tableros con la retención cumplida
+)
+
 type JWTClaims struct {
 	jwt.RegisteredClaims
 	UserID   string `json:"user_id"`
@@ -33,6 +39,9 @@ func InitConfig() {
 		log.Fatal("Faltan variables de entorno necesarias")
 	}
 
+	TrashRetention = durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	TrashPurgeInterval = durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+
 	// Configurar conexión a MongoDB
 	clientOptions := options.Client().ApplyURI(mongoURI)
 	client, err := mongo.Connect(context.TODO(), clientOptions)
@@ -52,3 +61,12 @@ func InitConfig() {
 	DB = client.Database(dbName)
 	fmt.Println("✅ Conectado a MongoDB desde Board-service:", dbName)
 }
+
+// durationEnv obtiene una duración (por ejemplo "720h") de las variables de entorno o el valor por defecto
+func durationEnv(name string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package kafka
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+)
+
+// trashedTasksEvent es el contenido de trashed-board-tasks, task-service informa cuántas tareas están en la papelera
+// con el tablero. Version es la del trashed-board que lo originó.
+type trashedTasksEvent struct {
+	BoardID string `json:"board_id"`
+	Version int64  `json:"version"`
+	Tasks   int64  `json:"tasks"`
+}
+
+// StartConsumer inicia el consumidor de Kafka en board-service, escuchando eventos de user para validar las invitaciones
+// y de task para conocer cuántas tareas se enviaron a la papelera con cada tablero.
+// Con auto.offset.reset earliest el grupo nuevo lee también los usuarios registrados antes de que existiera el consumidor.
+func StartConsumer(userRepo *repositories.UserRepository, boardRepo *repositories.BoardRepository) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": "kafka:9092",
+		"group.id":          "board-service",
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		log.Fatalf("❌ Error creando consumidor: %v", err)
+	}
+	defer c.Close()
+
+	err = c.SubscribeTopics([]string{"user-events", "task-events"}, nil)
+	if err != nil {
+		log.Fatalf("❌ Error suscribiéndose a Kafka: %v", err)
+	}
+
+	log.Println("📩 Escuchando eventos de user-service y task-service para board-service en Kafka...")
+
+	// Loop infinito para escuchar eventos
+	for {
+		msg, err := c.ReadMessage(-1)
+		if err != nil {
+			log.Printf("⚠️ Error al recibir mensaje: %v\n", err)
+			continue
+		}
+
+		// Los demás eventos de task-events no le interesan a board-service
+		if string(msg.Key) != "new-user" && string(msg.Key) != "trashed-board-tasks" {
+			continue
+		}
+
+		log.Printf("📨 Evento recibido | Topic: %s | Message: %s| Key: %s\n", *msg.TopicPartition.Topic, string(msg.Value), string(msg.Key))
+		switch string(msg.Key) {
+		case "new-user":
+			var user models.User
+			if err := json.Unmarshal(msg.Value, &user); err != nil {
+				log.Printf("⚠️ Error parseando JSON de usuario: %v\n", err)
+				continue
+			}
+
+			if err := userRepo.SaveUser(&user); err != nil {
+				log.Printf("⚠️ Error guardando usuario en board-service: %v\n", err)
+			} else {
+				log.Printf("✅ Usuario almacenado en board-service: %v\n", user.ID)
+			}
+		case "trashed-board-tasks":
+			var event trashedTasksEvent
+			if err := json.Unmarshal(msg.Value, &event); err != nil {
+				log.Printf("⚠️ Error parseando JSON de tareas en la papelera: %v\n", err)
+				continue
+			}
+
+			// Si el tablero ya se restauró o se purgó el conteo se descarta
+			err := boardRepo.SetTrashedTasks(event.BoardID, event.Version, event.Tasks)
+			switch {
+			case errors.Is(err, repositories.ErrBoardNotFound):
+				log.Printf("⏭️ Conteo de tareas descartado, el board %s ya no está en la papelera en la versión %d\n", event.BoardID, event.Version)
+			case err != nil:
+				log.Printf("⚠️ Error guardando las tareas en la papelera del board %s: %v\n", event.BoardID, err)
+			default:
+				log.Printf("✅ Tareas en la papelera del board %s: %d\n", event.BoardID, event.Tasks)
+			}
+		}
+	}
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/vadgun/gotrelloclone/board-service/handlers"
 	"github.com/vadgun/gotrelloclone/board-service/infra/config"
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
 	"github.com/vadgun/gotrelloclone/board-service/infra/logger"
 	"github.com/vadgun/gotrelloclone/board-service/infra/metrics"
 	"github.com/vadgun/gotrelloclone/board-service/repositories"
 	"github.com/vadgun/gotrelloclone/board-service/routes"
 	"github.com/vadgun/gotrelloclone/board-service/services"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -20,8 +23,50 @@ func main() {
 	metrics.InitMetrics()
 
 	boardRepo := repositories.NewBoardRepository()
-	boardService := services.NewBoardService(boardRepo)
+	listRepo := repositories.NewListRepository()
+	if err := listRepo.EnsureIndexes(); err != nil {
+		logger.Log.Error("No se pudieron crear los índices de listas", zap.Error(err))
+	}
+	labelRepo := repositories.NewLabelRepository()
+	if err := labelRepo.EnsureIndexes(); err != nil {
+		logger.Log.Error("No se pudieron crear los índices de etiquetas", zap.Error(err))
+	}
+
+	listService := services.NewListService(listRepo, boardRepo)
+	listHandler := handlers.NewListHandler(listService)
+	labelService := services.NewLabelService(labelRepo, boardRepo)
+	labelHandler := handlers.NewLabelHandler(labelService)
+	userRepo := repositories.NewUserRepository()
+	boardService := services.NewBoardService(boardRepo, listService, labelService, userRepo)
 	boardHandler := handlers.NewBoardHandler(boardService)
+	memberHandler := handlers.NewMemberHandler(boardService)
+
+	// Proyección de usuarios para validar a quién se invita a un tablero y conteo de tareas enviadas a la papelera
+	go kafka.StartConsumer(userRepo, boardRepo)
+
+	// Los tableros anteriores a los miembros tienen únicamente a su owner
+	if migrated, err := boardService.MigrateDefaultMembers(); err != nil {
+		logger.Log.Error("No se pudo registrar al owner como miembro de los tableros", zap.Error(err))
+	} else if migrated > 0 {
+		logger.Log.Info("Owner registrado como miembro de tableros existentes", zap.Int64("boards", migrated))
+	}
+	go func() {
+		if synced, err := boardService.SyncMembers(); err != nil {
+			logger.Log.Error("No se pudo publicar la membresía de los tableros", zap.Int("boards", synced), zap.Error(err))
+		} else {
+			logger.Log.Info("Membresía de tableros publicada", zap.Int("boards", synced))
+		}
+	}()
+
+	// Los tableros anteriores a los flujos personalizados usan el flujo por defecto
+	if migrated, err := boardService.MigrateDefaultWorkflow(); err != nil {
+		logger.Log.Error("No se pudo asignar el flujo por defecto a los tableros", zap.Error(err))
+	} else if migrated > 0 {
+		logger.Log.Info("Flujo por defecto asignado a tableros existentes", zap.Int64("boards", migrated))
+	}
+
+	// Los tableros en la papelera se eliminan definitivamente al cumplirse la retención
+	go boardService.StartTrashPurge(context.Background(), config.TrashRetention, config.TrashPurgeInterval)
 
 	gin.SetMode(gin.ReleaseMode)
 
@@ -30,13 +75,15 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"POST", "OPTIONS", "GET", "PUT", "PATCH", "DELETE"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	routes.SetupBoardRoutes(router, boardHandler)
+	routes.SetupBoardRoutes(router, boardHandler, memberHandler, boardService)
+	routes.SetupListRoutes(router, listHandler, boardService)
+	routes.SetupLabelRoutes(router, labelHandler, boardService)
 
 	router.GET("/metrics", gin.WrapH(metrics.MetricsHandler()))
 
@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+// BoardRoleRequired verifica que el usuario autenticado sea miembro del tablero de la ruta (:boardID)
+// con al menos el rol indicado. El tablero queda en el contexto como "board" para los handlers.
+func BoardRoleRequired(service *services.BoardService, role models.BoardRole) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		userID, _ := ctx.Get("userID")
+		id, _ := userID.(string)
+
+		board, err := service.Authorize(ctx.Param("boardID"), id, role)
+		switch {
+		case errors.Is(err, repositories.ErrBoardNotFound):
+			ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+			ctx.Abort()
+			return
+		case errors.Is(err, services.ErrForbidden):
+			ctx.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos sobre el tablero"})
+			ctx.Abort()
+			return
+		case err != nil:
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo validar el acceso al tablero"})
+			ctx.Abort()
+			return
+		}
+
+		ctx.Set("board", board)
+		ctx.Next()
+	}
+}
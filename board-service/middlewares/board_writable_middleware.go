@@ -0,0 +1,22 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+// BoardWritable rechaza con 409 las modificaciones de un tablero archivado.
+// Se usa después de BoardRoleRequired, que deja el tablero en el contexto.
+func BoardWritable() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if board := ctx.MustGet("board").(*models.Board); board.Archived {
+			ctx.JSON(http.StatusConflict, gin.H{"error": services.ErrBoardArchived.Error()})
+			ctx.Abort()
+			return
+		}
+		ctx.Next()
+	}
+}
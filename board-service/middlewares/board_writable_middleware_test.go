@@ -0,0 +1,49 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/board-service/middlewares"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+// setupRouter deja el tablero en el contexto como lo hace BoardRoleRequired antes de BoardWritable
+func setupRouter(board *models.Board) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.PUT("/boards/:boardID", func(ctx *gin.Context) {
+		ctx.Set("board", board)
+		ctx.Next()
+	}, middlewares.BoardWritable(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"message": "Tablero actualizado"})
+	})
+	return r
+}
+
+func TestBoardWritable_ArchivedBoard(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "/boards/board", nil)
+	resp := httptest.NewRecorder()
+
+	setupRouter(&models.Board{Archived: true}).ServeHTTP(resp, req)
+
+	// El handler no se ejecuta, el tablero archivado solo se consulta
+	assert.Equal(t, http.StatusConflict, resp.Code)
+	assert.Contains(t, resp.Body.String(), services.ErrBoardArchived.Error())
+	assert.NotContains(t, resp.Body.String(), "Tablero actualizado")
+}
+
+func TestBoardWritable_ActiveBoard(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPut, "/boards/board", nil)
+	resp := httptest.NewRecorder()
+
+	setupRouter(&models.Board{}).ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Contains(t, resp.Body.String(), "Tablero actualizado")
+}
@@ -12,4 +12,18 @@ type Board struct {
 	OwnerID   string             `json:"owner_id" bson:"owner_id"`
 	OwnerName string             `json:"owner_name" bson:"owner_name"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	Workflow  *Workflow          `json:"workflow" bson:"workflow,omitempty"`
+	Members   []BoardMember      `json:"members" bson:"members"`
+	// Invitations son los usuarios invitados que aún no aceptan, no tienen acceso al tablero
+	Invitations []BoardInvitation `json:"invitations,omitempty" bson:"invitations,omitempty"`
+	// MembersVersion aumenta con cada cambio de miembros, los consumidores de board-events descartan versiones viejas
+	MembersVersion int64 `json:"members_version" bson:"members_version"`
+	// Version aumenta con cada cambio del tablero, se expone como ETag para detectar ediciones simultáneas
+	Version int64 `json:"version" bson:"version"`
+	// Archived oculta el tablero de GET /boards, se puede consultar pero no modificar
+	Archived bool `json:"archived" bson:"archived"`
+	// DeletedAt indica que el tablero está en la papelera, se elimina definitivamente al cumplirse la retención
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	// TrashedTasks es cuántas tareas están en la papelera junto con el tablero, vacío hasta que task-service lo informa
+	TrashedTasks *int64 `json:"trashed_tasks,omitempty" bson:"trashed_tasks,omitempty"`
 }
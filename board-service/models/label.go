@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Label es una etiqueta del tablero, sus tareas pueden tener varias
+type Label struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	BoardID   string             `bson:"board_id" json:"board_id"`
+	Name      string             `bson:"name" json:"name"`
+	Color     string             `bson:"color" json:"color"` // Hexadecimal en minúsculas, por ejemplo #61bd4f
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	// Deleted solo viaja en el evento deleted-label, las etiquetas eliminadas no se guardan
+	Deleted bool `bson:"-" json:"deleted,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// List es una columna del tablero, las listas se ordenan por Position de menor a mayor
+type List struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	BoardID   string             `bson:"board_id" json:"board_id"`
+	Name      string             `bson:"name" json:"name"`
+	Position  float64            `bson:"position" json:"position"`
+	Archived  bool               `bson:"archived" json:"archived"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
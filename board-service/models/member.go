@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// BoardRole es el rol de un miembro dentro del tablero
+type BoardRole string
+
+const (
+	// RoleOwner puede todo, incluido definir el flujo, eliminar el tablero y transferirlo. Solo hay uno por tablero
+	RoleOwner BoardRole = "owner"
+	// RoleAdmin administra el tablero: nombre y miembros
+	RoleAdmin BoardRole = "admin"
+	// RoleEditor trabaja en el tablero: listas y tareas
+	RoleEditor BoardRole = "editor"
+	// RoleViewer solo puede consultar el tablero y sus tareas
+	RoleViewer BoardRole = "viewer"
+)
+
+// BoardMember es un usuario con acceso al tablero
+type BoardMember struct {
+	UserID  string    `json:"user_id" bson:"user_id"`
+	Role    BoardRole `json:"role" bson:"role"`
+	AddedAt time.Time `json:"added_at" bson:"added_at"`
+}
+
+// BoardInvitation es una invitación pendiente, el usuario pasa a ser miembro cuando la acepta
+type BoardInvitation struct {
+	UserID    string    `json:"user_id" bson:"user_id"`
+	Role      BoardRole `json:"role" bson:"role"`
+	InvitedBy string    `json:"invited_by" bson:"invited_by"`
+	InvitedAt time.Time `json:"invited_at" bson:"invited_at"`
+}
@@ -0,0 +1,8 @@
+package models
+
+// User es la copia local de los usuarios de user-service, permite validar a quién se invita a un tablero
+type User struct {
+	ID    string `bson:"_id" json:"id"`
+	Name  string `bson:"name" json:"name"`
+	Email string `bson:"email" json:"email"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// WorkflowStatus es un estado por el que pasan las tareas del tablero
+type WorkflowStatus struct {
+	Key  string `json:"key" bson:"key"`
+	Name string `json:"name" bson:"name"`
+}
+
+// Workflow define los estados de las tareas del tablero y a cuáles se puede pasar desde cada uno.
+// Las tareas nuevas empiezan en el primer estado; un estado sin transiciones es final.
+type Workflow struct {
+	Statuses    []WorkflowStatus    `json:"statuses" bson:"statuses"`
+	Transitions map[string][]string `json:"transitions" bson:"transitions"`
+	UpdatedAt   time.Time           `json:"updated_at" bson:"updated_at"`
+}
+
+// DefaultWorkflow es el flujo de los tableros que no definieron uno propio: los tres estados
+// originales con cualquier transición permitida entre ellos
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: "TODO", Name: "Por hacer"},
+			{Key: "IN_PROGRESS", Name: "En progreso"},
+			{Key: "DONE", Name: "Hecho"},
+		},
+		Transitions: map[string][]string{
+			"TODO":        {"IN_PROGRESS", "DONE"},
+			"IN_PROGRESS": {"TODO", "DONE"},
+			"DONE":        {"TODO", "IN_PROGRESS"},
+		},
+	}
+}
@@ -11,6 +11,14 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrBoardNotFound indica que el tablero no existe
+	ErrBoardNotFound = errors.New("tablero no encontrado")
+	// ErrVersionConflict indica que el tablero cambió desde la versión que editó el cliente
+	ErrVersionConflict = errors.New("el tablero fue modificado por otro usuario")
 )
 
 type BoardRepository struct {
@@ -31,12 +39,18 @@ func (r *BoardRepository) CreateBoard(board *models.Board) (any, error) {
 	return id.InsertedID, err
 }
 
-func (r *BoardRepository) GetBoardsByUser(userID string) ([]models.Board, error) {
+// GetBoardsByUser obtiene los tableros donde el usuario es miembro, los archivados solo si includeArchived es true
+func (r *BoardRepository) GetBoardsByUser(userID string, includeArchived bool) ([]models.Board, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	// El owner también está en members, se conserva owner_id para los tableros aún sin migrar
 	var boards []models.Board
-	cursor, err := r.collection.Find(ctx, bson.M{"owner_id": userID})
+	filter := bson.M{"$or": bson.A{bson.M{"members.user_id": userID}, bson.M{"owner_id": userID}}, "deleted_at": nil}
+	if !includeArchived {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -57,11 +71,12 @@ func (r *BoardRepository) GetBoardByID(boardID string) (*models.Board, error) {
 		return nil, errs
 	}
 
+	// Los tableros en la papelera no existen para el resto de operaciones
 	var board models.Board
-	err := r.collection.FindOne(ctx, bson.M{"_id": boardObjectID}).Decode(&board)
+	err := r.collection.FindOne(ctx, bson.M{"_id": boardObjectID, "deleted_at": nil}).Decode(&board)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("tablero no encontrado")
+			return nil, ErrBoardNotFound
 		}
 		return nil, err
 	}
@@ -69,7 +84,8 @@ func (r *BoardRepository) GetBoardByID(boardID string) (*models.Board, error) {
 	return &board, nil
 }
 
-func (r *BoardRepository) DeleteBoardByID(boardID string) error {
+// UpdateBoardByID cambia el nombre solo si el tablero sigue en la versión que editó el cliente
+func (r *BoardRepository) UpdateBoardByID(boardID string, newBoardName string, version int64) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -78,41 +94,93 @@ func (r *BoardRepository) DeleteBoardByID(boardID string) error {
 		return errs
 	}
 
-	mongoResult, err := r.collection.DeleteOne(ctx, bson.M{"_id": boardObjectID})
+	// Un tablero en la papelera no se edita, el handler lo distingue de un conflicto de versión al releerlo
+	filter := bson.M{"_id": boardObjectID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"name": newBoardName}, "$inc": bson.M{"version": 1}}
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
 
-	if mongoResult.DeletedCount == 0 {
-		return errors.New("tablero no encontrado")
+	if mongoResult.MatchedCount == 0 {
+		return ErrVersionConflict
 	}
 
 	return nil
 }
 
-func (r *BoardRepository) UpdateBoardByID(boardID string, newBoardName string) error {
+// SetBoardArchived archiva o desarchiva el tablero y devuelve el tablero actualizado
+func (r *BoardRepository) SetBoardArchived(boardID string, archived bool) (*models.Board, error) {
+	update := bson.M{"$set": bson.M{"archived": archived}, "$inc": bson.M{"version": 1}}
+	return r.updateAndGet(boardID, bson.M{"deleted_at": nil}, update)
+}
+
+// updateAndGet aplica update si el tablero cumple filter y devuelve el tablero actualizado, si no devuelve ErrBoardNotFound
+func (r *BoardRepository) updateAndGet(boardID string, filter, update bson.M) (*models.Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	boardObjectID, err := primitive.ObjectIDFromHex(boardID)
+	if err != nil {
+		return nil, ErrBoardNotFound
+	}
+	filter["_id"] = boardObjectID
+
+	var board models.Board
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&board)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBoardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// versionFilter compara la versión, los tableros anteriores al versionado no tienen el campo y cuentan como versión 0
+func versionFilter(version int64) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// UpdateBoardWorkflow reemplaza el flujo de estados del tablero
+func (r *BoardRepository) UpdateBoardWorkflow(boardID string, workflow *models.Workflow) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	boardObjectID, errs := primitive.ObjectIDFromHex(boardID)
 	if errs != nil {
-		return errs
+		return ErrBoardNotFound
 	}
 
-	filter := bson.M{"_id": boardObjectID}
-	update := bson.M{"$set": bson.M{"name": newBoardName}}
-	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	update := bson.M{"$set": bson.M{"workflow": workflow}, "$inc": bson.M{"version": 1}}
+	mongoResult, err := r.collection.UpdateOne(ctx, bson.M{"_id": boardObjectID, "deleted_at": nil}, update)
 	if err != nil {
 		return err
 	}
 
 	if mongoResult.MatchedCount == 0 {
-		return errors.New("tablero no encontrado")
+		return ErrBoardNotFound
 	}
 
 	return nil
 }
 
+// SetDefaultWorkflow asigna el flujo por defecto a los tableros creados antes de que existieran los flujos
+func (r *BoardRepository) SetDefaultWorkflow(workflow *models.Workflow) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoResult, err := r.collection.UpdateMany(ctx, bson.M{"workflow": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"workflow": workflow}})
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
+
 func (r *BoardRepository) GetAllBoards() ([]models.Board, error) {
 	var boards []models.Board
 
@@ -135,3 +203,20 @@ func (r *BoardRepository) GetAllBoards() ([]models.Board, error) {
 
 	return boards, nil
 }
+
+// GetActiveBoards obtiene todos los tableros que no están en la papelera
+func (r *BoardRepository) GetActiveBoards() ([]models.Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	boards := []models.Board{}
+	if err := cursor.All(ctx, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
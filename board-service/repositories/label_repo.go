@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/config"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLabelNotFound indica que la etiqueta no existe en el tablero
+var ErrLabelNotFound = errors.New("etiqueta no encontrada")
+
+type LabelRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLabelRepository() *LabelRepository {
+	return &LabelRepository{
+		collection: config.DB.Collection("labels"),
+	}
+}
+
+// EnsureIndexes crea el índice usado para obtener las etiquetas de un tablero
+func (r *LabelRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "created_at", Value: 1}},
+	})
+	return err
+}
+
+func (r *LabelRepository) CreateLabel(label *models.Label) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.InsertOne(ctx, label)
+	if err != nil {
+		return err
+	}
+	label.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetLabelsByBoard obtiene las etiquetas del tablero en el orden en que se crearon
+func (r *LabelRepository) GetLabelsByBoard(boardID string) ([]models.Label, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"board_id": boardID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	labels := []models.Label{}
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+func (r *LabelRepository) GetLabelByID(boardID string, labelID primitive.ObjectID) (*models.Label, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var label models.Label
+	err := r.collection.FindOne(ctx, bson.M{"_id": labelID, "board_id": boardID}).Decode(&label)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrLabelNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &label, nil
+}
+
+// UpdateLabel cambia el nombre y el color de la etiqueta
+func (r *LabelRepository) UpdateLabel(boardID string, labelID primitive.ObjectID, name, color string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"name": name, "color": color, "updated_at": time.Now()}}
+	mongoResult, err := r.collection.UpdateOne(ctx, bson.M{"_id": labelID, "board_id": boardID}, update)
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+func (r *LabelRepository) DeleteLabel(boardID string, labelID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoResult, err := r.collection.DeleteOne(ctx, bson.M{"_id": labelID, "board_id": boardID})
+	if err != nil {
+		return err
+	}
+	if mongoResult.DeletedCount == 0 {
+		return ErrLabelNotFound
+	}
+	return nil
+}
+
+func (r *LabelRepository) DeleteLabelsByBoard(boardID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"board_id": boardID})
+	return err
+}
@@ -0,0 +1,162 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/config"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrListNotFound indica que la lista no existe en el tablero
+var ErrListNotFound = errors.New("lista no encontrada")
+
+type ListRepository struct {
+	collection *mongo.Collection
+}
+
+func NewListRepository() *ListRepository {
+	return &ListRepository{
+		collection: config.DB.Collection("lists"),
+	}
+}
+
+// EnsureIndexes crea el índice usado para obtener las listas de un tablero en orden
+func (r *ListRepository) EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "position", Value: 1}},
+	})
+	return err
+}
+
+func (r *ListRepository) CreateList(list *models.List) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.InsertOne(ctx, list)
+	if err != nil {
+		return err
+	}
+	list.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetListsByBoard obtiene las listas del tablero ordenadas por posición
+func (r *ListRepository) GetListsByBoard(boardID string, includeArchived bool) ([]models.List, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"board_id": boardID}
+	if !includeArchived {
+		filter["archived"] = false
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	lists := []models.List{}
+	if err := cursor.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+func (r *ListRepository) GetListByID(boardID, listID string) (*models.List, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	listObjectID, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return nil, ErrListNotFound
+	}
+
+	var list models.List
+	err = r.collection.FindOne(ctx, bson.M{"_id": listObjectID, "board_id": boardID}).Decode(&list)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrListNotFound
+		}
+		return nil, err
+	}
+	return &list, nil
+}
+
+// GetNeighborList obtiene la lista más cercana a position hacia la derecha (next) o hacia la izquierda,
+// sin contar la lista excludeID. Devuelve nil si no hay ninguna.
+func (r *ListRepository) GetNeighborList(boardID string, position float64, next bool, excludeID primitive.ObjectID) (*models.List, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	operator, order := "$lt", -1
+	if next {
+		operator, order = "$gt", 1
+	}
+	filter := bson.M{"board_id": boardID, "archived": false, "position": bson.M{operator: position}, "_id": bson.M{"$ne": excludeID}}
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "position", Value: order}})
+
+	var list models.List
+	err := r.collection.FindOne(ctx, filter, findOptions).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// UpdateList actualiza los campos indicados de la lista
+func (r *ListRepository) UpdateList(boardID string, listID primitive.ObjectID, fields bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	fields["updated_at"] = time.Now()
+	mongoResult, err := r.collection.UpdateOne(ctx, bson.M{"_id": listID, "board_id": boardID}, bson.M{"$set": fields})
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return ErrListNotFound
+	}
+	return nil
+}
+
+// SetPositions reasigna las posiciones de varias listas en una sola operación
+func (r *ListRepository) SetPositions(positions map[primitive.ObjectID]float64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	updates := make([]mongo.WriteModel, 0, len(positions))
+	for id, position := range positions {
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"position": position, "updated_at": time.Now()}}))
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	_, err := r.collection.BulkWrite(ctx, updates)
+	return err
+}
+
+func (r *ListRepository) DeleteListsByBoard(boardID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"board_id": boardID})
+	return err
+}
@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrMemberNotFound indica que el usuario no es miembro del tablero o que su rol cambió mientras se validaba
+	ErrMemberNotFound = errors.New("miembro no encontrado")
+	// ErrMemberExists indica que el usuario ya es miembro del tablero
+	ErrMemberExists = errors.New("el usuario ya es miembro del tablero")
+	// ErrInvitationNotFound indica que el usuario no tiene una invitación pendiente al tablero
+	ErrInvitationNotFound = errors.New("invitación no encontrada")
+	// ErrInvitationExists indica que el usuario ya tiene una invitación pendiente o ya es miembro del tablero
+	ErrInvitationExists = errors.New("el usuario ya tiene una invitación pendiente al tablero")
+)
+
+// Los cambios de miembros se filtran por el estado que se validó en el servicio (rol actual, owner actual),
+// así dos cambios simultáneos no pueden dejar el tablero en un estado que ninguno autorizó
+
+// AddInvitation registra la invitación si el usuario aún no es miembro ni está invitado
+func (r *BoardRepository) AddInvitation(boardID string, invitation models.BoardInvitation) error {
+	filter := bson.M{
+		"members.user_id":     bson.M{"$ne": invitation.UserID},
+		"invitations.user_id": bson.M{"$ne": invitation.UserID},
+	}
+	update := bson.M{"$push": bson.M{"invitations": invitation}}
+	return r.updateInvitations(boardID, filter, update, ErrInvitationExists)
+}
+
+// RemoveInvitation descarta la invitación pendiente del usuario
+func (r *BoardRepository) RemoveInvitation(boardID, userID string) error {
+	filter := bson.M{"invitations.user_id": userID}
+	update := bson.M{"$pull": bson.M{"invitations": bson.M{"user_id": userID}}}
+	return r.updateInvitations(boardID, filter, update, ErrInvitationNotFound)
+}
+
+// AcceptInvitation convierte la invitación en miembro con el rol invitado en la misma operación,
+// si el rol de la invitación cambió mientras se validaba no se aplica
+func (r *BoardRepository) AcceptInvitation(boardID string, invitation models.BoardInvitation) error {
+	filter := bson.M{
+		"members.user_id": bson.M{"$ne": invitation.UserID},
+		"invitations":     bson.M{"$elemMatch": bson.M{"user_id": invitation.UserID, "role": invitation.Role}},
+	}
+	member := models.BoardMember{UserID: invitation.UserID, Role: invitation.Role, AddedAt: time.Now()}
+	update := bson.M{
+		"$push": bson.M{"members": member},
+		"$pull": bson.M{"invitations": bson.M{"user_id": invitation.UserID}},
+	}
+	return r.updateMembers(boardID, filter, update, nil, ErrInvitationNotFound)
+}
+
+// GetBoardsInvitingUser obtiene los tableros con una invitación pendiente para el usuario
+func (r *BoardRepository) GetBoardsInvitingUser(userID string) ([]models.Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var boards []models.Board
+	cursor, err := r.collection.Find(ctx, bson.M{"invitations.user_id": userID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cursor.All(ctx, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// UpdateMemberRole cambia el rol del miembro si sigue teniendo el rol from
+func (r *BoardRepository) UpdateMemberRole(boardID, userID string, from, role models.BoardRole) error {
+	filter := bson.M{"members": bson.M{"$elemMatch": bson.M{"user_id": userID, "role": from}}}
+	update := bson.M{"$set": bson.M{"members.$.role": role}}
+	return r.updateMembers(boardID, filter, update, nil, ErrMemberNotFound)
+}
+
+// RemoveMember quita al miembro si sigue teniendo el rol from, el owner no se puede quitar
+func (r *BoardRepository) RemoveMember(boardID, userID string, from models.BoardRole) error {
+	filter := bson.M{
+		"owner_id": bson.M{"$ne": userID},
+		"members":  bson.M{"$elemMatch": bson.M{"user_id": userID, "role": from}},
+	}
+	update := bson.M{"$pull": bson.M{"members": bson.M{"user_id": userID}}}
+	return r.updateMembers(boardID, filter, update, nil, ErrMemberNotFound)
+}
+
+// TransferOwnership pasa el tablero de ownerID a newOwnerID, que debe ser miembro. El owner anterior queda como admin
+func (r *BoardRepository) TransferOwnership(boardID, ownerID, newOwnerID string) error {
+	filter := bson.M{"owner_id": ownerID, "members.user_id": newOwnerID}
+	update := bson.M{"$set": bson.M{
+		"owner_id":                    newOwnerID,
+		"members.$[newOwner].role":    models.RoleOwner,
+		"members.$[formerOwner].role": models.RoleAdmin,
+	}}
+	arrayFilters := options.ArrayFilters{Filters: []any{
+		bson.M{"newOwner.user_id": newOwnerID},
+		bson.M{"formerOwner.user_id": ownerID},
+	}}
+	return r.updateMembers(boardID, filter, update, &arrayFilters, ErrMemberNotFound)
+}
+
+// updateMembers aplica el cambio de miembros e incrementa members_version y version en la misma operación,
+// los tableros en la papelera no cambian
+func (r *BoardRepository) updateMembers(boardID string, filter, update bson.M, arrayFilters *options.ArrayFilters, notMatched error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	boardObjectID, errs := primitive.ObjectIDFromHex(boardID)
+	if errs != nil {
+		return ErrBoardNotFound
+	}
+
+	filter["_id"] = boardObjectID
+	filter["deleted_at"] = nil
+	update["$inc"] = bson.M{"members_version": 1, "version": 1}
+
+	updateOptions := options.Update()
+	if arrayFilters != nil {
+		updateOptions.SetArrayFilters(*arrayFilters)
+	}
+
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update, updateOptions)
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return notMatched
+	}
+	return nil
+}
+
+// updateInvitations aplica el cambio de invitaciones, la membresía no cambia así que solo se incrementa version
+func (r *BoardRepository) updateInvitations(boardID string, filter, update bson.M, notMatched error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	boardObjectID, errs := primitive.ObjectIDFromHex(boardID)
+	if errs != nil {
+		return ErrBoardNotFound
+	}
+
+	filter["_id"] = boardObjectID
+	filter["deleted_at"] = nil
+	update["$inc"] = bson.M{"version": 1}
+
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return notMatched
+	}
+	return nil
+}
+
+// SetDefaultMembers registra al owner como miembro de los tableros creados antes de que existieran los miembros
+func (r *BoardRepository) SetDefaultMembers() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"members": bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"members": bson.A{bson.M{
+			"user_id":  "$owner_id",
+			"role":     models.RoleOwner,
+			"added_at": "$created_at",
+		}},
+		"members_version": 1,
+	}}}}
+
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// trashedFilter selecciona los tableros que están en la papelera
+var trashedFilter = bson.M{"$ne": nil}
+
+// TrashBoard envía el tablero a la papelera y devuelve el tablero actualizado
+func (r *BoardRepository) TrashBoard(boardID string, deletedAt time.Time) (*models.Board, error) {
+	update := bson.M{"$set": bson.M{"deleted_at": deletedAt}, "$inc": bson.M{"version": 1}}
+	return r.updateAndGet(boardID, bson.M{"deleted_at": nil}, update)
+}
+
+// RestoreBoard saca el tablero de la papelera y devuelve el tablero actualizado
+func (r *BoardRepository) RestoreBoard(boardID string) (*models.Board, error) {
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "trashed_tasks": ""}, "$inc": bson.M{"version": 1}}
+	return r.updateAndGet(boardID, bson.M{"deleted_at": trashedFilter}, update)
+}
+
+// SetTrashedTasks guarda cuántas tareas envió task-service a la papelera con el tablero.
+// Solo se aplica si el tablero sigue en la papelera en la versión del trashed-board que lo originó.
+func (r *BoardRepository) SetTrashedTasks(boardID string, version, tasks int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	boardObjectID, err := primitive.ObjectIDFromHex(boardID)
+	if err != nil {
+		return ErrBoardNotFound
+	}
+
+	filter := bson.M{"_id": boardObjectID, "version": version, "deleted_at": trashedFilter}
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"trashed_tasks": tasks}})
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return ErrBoardNotFound
+	}
+	return nil
+}
+
+// GetTrashedBoardByID obtiene un tablero de la papelera
+func (r *BoardRepository) GetTrashedBoardByID(boardID string) (*models.Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	boardObjectID, err := primitive.ObjectIDFromHex(boardID)
+	if err != nil {
+		return nil, ErrBoardNotFound
+	}
+
+	var board models.Board
+	err = r.collection.FindOne(ctx, bson.M{"_id": boardObjectID, "deleted_at": trashedFilter}).Decode(&board)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrBoardNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+// GetTrashedBoards obtiene los tableros en la papelera del owner, los eliminados más recientemente primero
+func (r *BoardRepository) GetTrashedBoards(ownerID string) ([]models.Board, error) {
+	return r.findTrashed(bson.M{"owner_id": ownerID, "deleted_at": trashedFilter})
+}
+
+// GetExpiredBoards obtiene los tableros que están en la papelera desde antes de cutoff
+func (r *BoardRepository) GetExpiredBoards(cutoff time.Time) ([]models.Board, error) {
+	return r.findTrashed(bson.M{"deleted_at": bson.M{"$lte": cutoff}})
+}
+
+func (r *BoardRepository) findTrashed(filter bson.M) ([]models.Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	boards := []models.Board{}
+	if err := cursor.All(ctx, &boards); err != nil {
+		return nil, err
+	}
+	return boards, nil
+}
+
+// PurgeBoard elimina definitivamente el tablero si sigue en la papelera desde antes de cutoff.
+// Devuelve false si otra réplica ya lo eliminó o si se restauró mientras tanto.
+func (r *BoardRepository) PurgeBoard(boardID primitive.ObjectID, cutoff time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	mongoResult, err := r.collection.DeleteOne(ctx, bson.M{"_id": boardID, "deleted_at": bson.M{"$lte": cutoff}})
+	if err != nil {
+		return false, err
+	}
+	return mongoResult.DeletedCount == 1, nil
+}
@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/config"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserRepository guarda la proyección de usuarios que llega de user-events
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		collection: config.DB.Collection("users"),
+	}
+}
+
+// SaveUser guarda o reemplaza el usuario, un evento repetido no falla
+func (r *UserRepository) SaveUser(user *models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.ID}, user, options.Replace().SetUpsert(true))
+	return err
+}
+
+// UserExists indica si el usuario está registrado en user-service
+func (r *UserRepository) UserExists(userID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": userID}, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
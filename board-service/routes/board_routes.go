@@ -4,21 +4,53 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/vadgun/gotrelloclone/board-service/handlers"
 	"github.com/vadgun/gotrelloclone/board-service/middlewares"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
 )
 
-func SetupBoardRoutes(router *gin.Engine, handler *handlers.BoardHandler) {
+func SetupBoardRoutes(router *gin.Engine, handler *handlers.BoardHandler, memberHandler *handlers.MemberHandler, service *services.BoardService) {
 	boardGroup := router.Group("/boards")
 
 	boardGroup.Use(middlewares.AuthMiddleware())
 
+	viewer := middlewares.BoardRoleRequired(service, models.RoleViewer)
+	admin := middlewares.BoardRoleRequired(service, models.RoleAdmin)
+	owner := middlewares.BoardRoleRequired(service, models.RoleOwner)
+	writable := middlewares.BoardWritable()
+
 	boardGroup.POST("", handler.CreateBoard)
+	//Tableros donde el usuario es miembro
 	boardGroup.GET("", handler.GetBoards)
-	boardGroup.GET("/:boardID", handler.GetBoardByID)
-	//Eliminar un Board por id y que este avise a Kafka para eliminarlo de task-service
-	boardGroup.DELETE("/:boardID", handler.DeleteBoardByID)
+	boardGroup.GET("/:boardID", viewer, handler.GetBoardByID)
+	//Eliminar un Board por id lo envía a la papelera y avisa a Kafka para que task-service haga lo mismo con sus tareas
+	boardGroup.DELETE("/:boardID", owner, handler.DeleteBoardByID)
+
+	//Papelera del usuario, el tablero ya no es visible para el middleware así que el servicio valida al owner
+	boardGroup.GET("/trash", handler.GetTrash)
+	boardGroup.POST("/:boardID/restore", handler.RestoreBoard)
 
 	//Modificar el nombre de un board
-	boardGroup.PUT("/:boardID", handler.UpdateBoardByID)
+	boardGroup.PUT("/:boardID", admin, writable, handler.UpdateBoardByID)
+
+	//Archivar o desarchivar el board, archivado no aparece en GET /boards y solo se puede consultar
+	boardGroup.PUT("/:boardID/archive", admin, handler.ArchiveBoard)
+	boardGroup.PUT("/:boardID/unarchive", admin, handler.UnarchiveBoard)
+
+	//Estados y transiciones de las tareas del board, solo el owner los define
+	boardGroup.GET("/:boardID/workflow", viewer, handler.GetWorkflow)
+	boardGroup.PUT("/:boardID/workflow", owner, writable, handler.UpdateWorkflow)
+
+	//Miembros del board, los admins invitan y cualquier miembro puede salir quitándose a sí mismo
+	boardGroup.GET("/:boardID/members", viewer, memberHandler.GetMembers)
+	boardGroup.POST("/:boardID/members", admin, memberHandler.InviteMember)
+	boardGroup.PUT("/:boardID/members/:userID", admin, memberHandler.UpdateMemberRole)
+	boardGroup.DELETE("/:boardID/members/:userID", viewer, memberHandler.RemoveMember)
+	boardGroup.POST("/:boardID/transfer", owner, memberHandler.TransferOwnership)
+
+	//Invitaciones, el invitado aún no es miembro así que el servicio valida quién puede aceptarlas o descartarlas
+	boardGroup.GET("/invitations", memberHandler.GetInvitations)
+	boardGroup.POST("/:boardID/invitations/accept", memberHandler.AcceptInvitation)
+	boardGroup.DELETE("/:boardID/invitations/:userID", memberHandler.RemoveInvitation)
 
 	//Endpoint para el admin ver los boards
 	adminGroup := router.Group("/admin")
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/handlers"
+	"github.com/vadgun/gotrelloclone/board-service/middlewares"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func SetupLabelRoutes(router *gin.Engine, handler *handlers.LabelHandler, boardService *services.BoardService) {
+	labelGroup := router.Group("/boards/:boardID/labels")
+
+	labelGroup.Use(middlewares.AuthMiddleware())
+
+	viewer := middlewares.BoardRoleRequired(boardService, models.RoleViewer)
+	editor := middlewares.BoardRoleRequired(boardService, models.RoleEditor)
+	// Las etiquetas de un board archivado solo se pueden consultar
+	writable := middlewares.BoardWritable()
+
+	labelGroup.GET("", viewer, handler.GetLabels)
+	labelGroup.POST("", editor, writable, handler.CreateLabel)
+	labelGroup.PUT("/:labelID", editor, writable, handler.UpdateLabel)
+
+	//Eliminar la etiqueta la quita de todas las tareas del board
+	labelGroup.DELETE("/:labelID", editor, writable, handler.DeleteLabel)
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/board-service/handlers"
+	"github.com/vadgun/gotrelloclone/board-service/middlewares"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func SetupListRoutes(router *gin.Engine, handler *handlers.ListHandler, boardService *services.BoardService) {
+	listGroup := router.Group("/boards/:boardID/lists")
+
+	listGroup.Use(middlewares.AuthMiddleware())
+
+	viewer := middlewares.BoardRoleRequired(boardService, models.RoleViewer)
+	editor := middlewares.BoardRoleRequired(boardService, models.RoleEditor)
+	// Las listas de un board archivado solo se pueden consultar
+	writable := middlewares.BoardWritable()
+
+	listGroup.POST("", editor, writable, handler.CreateList)
+	listGroup.GET("", viewer, handler.GetLists)
+	listGroup.PUT("/:listID", editor, writable, handler.RenameList)
+
+	//Reordenar la lista entre sus vecinas
+	listGroup.PUT("/:listID/move", editor, writable, handler.MoveList)
+
+	//Archivar o restaurar la lista, las tareas conservan su lista
+	listGroup.PUT("/:listID/archive", editor, writable, handler.ArchiveList)
+	listGroup.PUT("/:listID/unarchive", editor, writable, handler.UnarchiveList)
+}
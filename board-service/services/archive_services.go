@@ -0,0 +1,43 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+)
+
+// Keys con las que se publica en board-events el archivado de un tablero
+const (
+	BoardArchivedEvent   = "archived-board"
+	BoardUnarchivedEvent = "unarchived-board"
+)
+
+// ErrBoardArchived indica que el tablero está archivado y solo se puede consultar
+var ErrBoardArchived = errors.New("el tablero está archivado, desarchívalo para modificarlo")
+
+// ArchiveEvent es el payload de archived-board y unarchived-board. Version permite a los consumidores
+// descartar un evento que llegue después de otro más reciente.
+type ArchiveEvent struct {
+	ID       string `json:"id"`
+	ActorID  string `json:"actor_id"`
+	Version  int64  `json:"version"`
+	Archived bool   `json:"archived"`
+}
+
+// ArchiveBoard archiva o desarchiva el tablero, task-service deja sus tareas en solo lectura mientras está archivado
+func (s *BoardService) ArchiveBoard(boardID, actorID string, archived bool) (*models.Board, error) {
+	board, err := s.repo.SetBoardArchived(boardID, archived)
+	if err != nil {
+		return nil, err
+	}
+
+	key := BoardArchivedEvent
+	if !archived {
+		key = BoardUnarchivedEvent
+	}
+	eventJSON, _ := json.Marshal(ArchiveEvent{ID: board.ID.Hex(), ActorID: actorID, Version: board.Version, Archived: board.Archived})
+	go kafka.ProduceMessage("", string(eventJSON), "board-events", key)
+	return board, nil
+}
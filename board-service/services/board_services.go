@@ -11,19 +11,29 @@ import (
 )
 
 type BoardService struct {
-	repo *repositories.BoardRepository
+	repo   *repositories.BoardRepository
+	lists  *ListService
+	labels *LabelService
+	users  *repositories.UserRepository
 }
 
-func NewBoardService(repo *repositories.BoardRepository) *BoardService {
-	return &BoardService{repo}
+func NewBoardService(repo *repositories.BoardRepository, lists *ListService, labels *LabelService, users *repositories.UserRepository) *BoardService {
+	return &BoardService{repo: repo, lists: lists, labels: labels, users: users}
 }
 
 func (s *BoardService) CreateBoard(name, ownerID, ownerName string) (*models.Board, error) {
+	now := time.Now()
 	board := &models.Board{
 		Name:      name,
 		OwnerID:   ownerID,
 		OwnerName: ownerName,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		Workflow:  models.DefaultWorkflow(),
+		Members: []models.BoardMember{
+			{UserID: ownerID, Role: models.RoleOwner, AddedAt: now},
+		},
+		MembersVersion: 1,
+		Version:        1,
 	}
 
 	id, err := s.repo.CreateBoard(board)
@@ -33,40 +43,45 @@ func (s *BoardService) CreateBoard(name, ownerID, ownerName string) (*models.Boa
 
 	board.ID = id.(primitive.ObjectID)
 
-	var kafkaBoard struct {
-		ID string `json:"id" bson:"_id"`
-	}
-
-	kafkaBoard.ID = board.ID.Hex()
-
-	jsonID, _ := json.Marshal(kafkaBoard)
+	// Los miembros viajan en el evento para que notification-service sepa a quién avisar de las tareas del tablero
+	// y task-service autorice las operaciones; el flujo para que task-service valide los estados de sus tareas
+	jsonID, _ := json.Marshal(NewBoardEvent(board, true))
 	go kafka.ProduceMessage("", string(jsonID), "board-events", "new-board")
 
+	// Cada tablero nace con sus columnas por defecto
+	if err := s.lists.CreateDefaultLists(board.ID.Hex()); err != nil {
+		return nil, err
+	}
+
 	return board, nil
 }
 
-func (s *BoardService) GetBoardsByUser(userID string) ([]models.Board, error) {
-	return s.repo.GetBoardsByUser(userID)
+func (s *BoardService) GetBoardsByUser(userID string, includeArchived bool) ([]models.Board, error) {
+	return s.repo.GetBoardsByUser(userID, includeArchived)
 }
 
 func (s *BoardService) GetBoardByID(boardID string) (*models.Board, error) {
 	return s.repo.GetBoardByID(boardID)
 }
 
-func (s *BoardService) DeleteBoardByID(boardID string) error {
-
-	var kafkaBoard struct {
-		ID string `json:"id" bson:"_id"`
+// DeleteBoardByID envía el tablero a la papelera y lo devuelve, task-service envía sus tareas a la papelera al recibir
+// trashed-board y responde con cuántas fueron, el conteo se consulta después en GET /boards/trash
+func (s *BoardService) DeleteBoardByID(boardID, actorID string) (*models.Board, error) {
+	board, err := s.repo.TrashBoard(boardID, time.Now())
+	if err != nil {
+		return nil, err
 	}
-	kafkaBoard.ID = boardID
 
-	jsonID, _ := json.Marshal(kafkaBoard)
-	go kafka.ProduceMessage("", string(jsonID), "board-events", "drop-board")
-	return s.repo.DeleteBoardByID(boardID)
+	publishTrash(BoardTrashedEvent, board, actorID)
+	return board, nil
 }
 
-func (s *BoardService) UpdateBoardByID(boardID, newBoardName string) error {
-	return s.repo.UpdateBoardByID(boardID, newBoardName)
+// UpdateBoardByID renombra el tablero si sigue en la versión indicada, si no devuelve repositories.ErrVersionConflict
+func (s *BoardService) UpdateBoardByID(boardID, newBoardName string, version int64) (*models.Board, error) {
+	if err := s.repo.UpdateBoardByID(boardID, newBoardName, version); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBoardByID(boardID)
 }
 
 func (s *BoardService) GetAllBoards() ([]models.Board, error) {
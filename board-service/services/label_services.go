@@ -0,0 +1,143 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Keys con las que se publican los cambios de etiquetas en board-events
+const (
+	LabelCreatedEvent = "new-label"
+	LabelUpdatedEvent = "updated-label"
+	LabelDeletedEvent = "deleted-label"
+)
+
+// MaxLabelName es el largo máximo del nombre de una etiqueta
+const MaxLabelName = 50
+
+// ErrInvalidLabel indica un nombre vacío o demasiado largo, o un color que no es hexadecimal
+var ErrInvalidLabel = errors.New("la etiqueta necesita un nombre de hasta 50 caracteres y un color hexadecimal como #61bd4f")
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelService struct {
+	repo      *repositories.LabelRepository
+	boardRepo *repositories.BoardRepository
+}
+
+func NewLabelService(repo *repositories.LabelRepository, boardRepo *repositories.BoardRepository) *LabelService {
+	return &LabelService{repo: repo, boardRepo: boardRepo}
+}
+
+// ValidateLabel limpia el nombre y normaliza el color a minúsculas, devuelve ErrInvalidLabel si no son válidos
+func ValidateLabel(name, color string) (string, string, error) {
+	name, color = strings.TrimSpace(name), strings.TrimSpace(color)
+	if name == "" || utf8.RuneCountInString(name) > MaxLabelName || !labelColorPattern.MatchString(color) {
+		return "", "", ErrInvalidLabel
+	}
+	return name, strings.ToLower(color), nil
+}
+
+// boardExists devuelve repositories.ErrBoardNotFound si el tablero no existe
+func (s *LabelService) boardExists(boardID string) error {
+	if !primitive.IsValidObjectID(boardID) {
+		return repositories.ErrBoardNotFound
+	}
+	_, err := s.boardRepo.GetBoardByID(boardID)
+	return err
+}
+
+func (s *LabelService) GetLabels(boardID string) ([]models.Label, error) {
+	if err := s.boardExists(boardID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLabelsByBoard(boardID)
+}
+
+func (s *LabelService) CreateLabel(boardID, name, color string) (*models.Label, error) {
+	name, color, err := ValidateLabel(name, color)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.boardExists(boardID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	label := &models.Label{
+		BoardID:   boardID,
+		Name:      name,
+		Color:     color,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateLabel(label); err != nil {
+		return nil, err
+	}
+
+	publishLabel(LabelCreatedEvent, label)
+	return label, nil
+}
+
+func (s *LabelService) UpdateLabel(boardID, labelID, name, color string) (*models.Label, error) {
+	name, color, err := ValidateLabel(name, color)
+	if err != nil {
+		return nil, err
+	}
+	id, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return nil, repositories.ErrLabelNotFound
+	}
+
+	if err := s.repo.UpdateLabel(boardID, id, name, color); err != nil {
+		return nil, err
+	}
+	label, err := s.repo.GetLabelByID(boardID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	publishLabel(LabelUpdatedEvent, label)
+	return label, nil
+}
+
+// DeleteLabel elimina la etiqueta, task-service la quita de todas las tareas al recibir deleted-label
+func (s *LabelService) DeleteLabel(boardID, labelID string) error {
+	id, err := primitive.ObjectIDFromHex(labelID)
+	if err != nil {
+		return repositories.ErrLabelNotFound
+	}
+
+	label, err := s.repo.GetLabelByID(boardID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteLabel(boardID, id); err != nil {
+		return err
+	}
+
+	// updated_at marca el momento de la eliminación para que task-service descarte eventos anteriores
+	label.UpdatedAt, label.Deleted = time.Now(), true
+	publishLabel(LabelDeletedEvent, label)
+	return nil
+}
+
+// DeleteLabelsByBoard elimina las etiquetas del tablero eliminado, task-service las borra al recibir drop-board
+func (s *LabelService) DeleteLabelsByBoard(boardID string) error {
+	return s.repo.DeleteLabelsByBoard(boardID)
+}
+
+// publishLabel envía la etiqueta completa a board-events para que task-service mantenga su copia
+func publishLabel(key string, label *models.Label) {
+	labelJSON, _ := json.Marshal(label)
+	go kafka.ProduceMessage("", string(labelJSON), "board-events", key)
+}
@@ -0,0 +1,26 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func TestValidateLabel(t *testing.T) {
+	name, color, err := services.ValidateLabel("  Urgente ", "#EB5A46")
+	assert.NoError(t, err)
+	assert.Equal(t, "Urgente", name)
+	assert.Equal(t, "#eb5a46", color)
+
+	for _, invalid := range [][2]string{
+		{"", "#eb5a46"},
+		{strings.Repeat("a", 51), "#eb5a46"},
+		{"Urgente", "red"},
+		{"Urgente", "#eb5a4"},
+	} {
+		_, _, err := services.ValidateLabel(invalid[0], invalid[1])
+		assert.ErrorIs(t, err, services.ErrInvalidLabel, invalid)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Keys con las que se publican los cambios de listas en board-events
+const (
+	ListCreatedEvent = "new-list"
+	ListUpdatedEvent = "updated-list"
+)
+
+// DefaultLists son las listas con las que nace cada tablero
+var DefaultLists = []string{"Por hacer", "En progreso", "Hecho"}
+
+var (
+	// ErrInvalidNeighbor indica que una lista se indicó como vecina de sí misma
+	ErrInvalidNeighbor = errors.New("una lista no puede ser su propia vecina")
+	// ErrNeighborConflict indica que las listas vecinas ya no están en el orden que vio el cliente
+	ErrNeighborConflict = errors.New("las listas vecinas cambiaron de posición")
+)
+
+type ListService struct {
+	repo      *repositories.ListRepository
+	boardRepo *repositories.BoardRepository
+}
+
+func NewListService(repo *repositories.ListRepository, boardRepo *repositories.BoardRepository) *ListService {
+	return &ListService{repo: repo, boardRepo: boardRepo}
+}
+
+// boardExists devuelve repositories.ErrBoardNotFound si el tablero no existe
+func (s *ListService) boardExists(boardID string) error {
+	if !primitive.IsValidObjectID(boardID) {
+		return repositories.ErrBoardNotFound
+	}
+	_, err := s.boardRepo.GetBoardByID(boardID)
+	return err
+}
+
+// CreateList agrega la lista al final del tablero
+func (s *ListService) CreateList(boardID, name string) (*models.List, error) {
+	if err := s.boardExists(boardID); err != nil {
+		return nil, err
+	}
+	return s.createList(boardID, name)
+}
+
+// CreateDefaultLists crea las listas iniciales de un tablero nuevo
+func (s *ListService) CreateDefaultLists(boardID string) error {
+	for _, name := range DefaultLists {
+		if _, err := s.createList(boardID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *ListService) createList(boardID, name string) (*models.List, error) {
+	last, err := s.repo.GetNeighborList(boardID, math.Inf(1), false, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastPosition *float64
+	if last != nil {
+		lastPosition = &last.Position
+	}
+	position, _ := PositionBetween(lastPosition, nil)
+
+	now := time.Now()
+	list := &models.List{
+		BoardID:   boardID,
+		Name:      name,
+		Position:  position,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateList(list); err != nil {
+		return nil, err
+	}
+
+	publishList(ListCreatedEvent, list)
+	return list, nil
+}
+
+func (s *ListService) GetLists(boardID string, includeArchived bool) ([]models.List, error) {
+	if err := s.boardExists(boardID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetListsByBoard(boardID, includeArchived)
+}
+
+func (s *ListService) RenameList(boardID, listID, name string) (*models.List, error) {
+	return s.updateList(boardID, listID, bson.M{"name": name})
+}
+
+// ArchiveList oculta o restaura la lista, al restaurarla conserva su posición anterior
+func (s *ListService) ArchiveList(boardID, listID string, archived bool) (*models.List, error) {
+	return s.updateList(boardID, listID, bson.M{"archived": archived})
+}
+
+// MoveList coloca la lista entre beforeID (queda a su izquierda) y afterID (queda a su derecha).
+// Basta con indicar uno de los dos; sin ninguno la lista pasa al final. Solo se modifica la posición
+// de la lista movida, por lo que dos movimientos simultáneos no se pisan entre sí.
+func (s *ListService) MoveList(boardID, listID, beforeID, afterID string) (*models.List, error) {
+	list, err := s.repo.GetListByID(boardID, listID)
+	if err != nil {
+		return nil, err
+	}
+	if beforeID == listID || afterID == listID {
+		return nil, ErrInvalidNeighbor
+	}
+
+	position, err := s.resolvePosition(boardID, list.ID, beforeID, afterID, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.updateList(boardID, listID, bson.M{"position": position})
+}
+
+func (s *ListService) resolvePosition(boardID string, listID primitive.ObjectID, beforeID, afterID string, canRebalance bool) (float64, error) {
+	var before, after *models.List
+	var err error
+
+	if beforeID != "" {
+		if before, err = s.repo.GetListByID(boardID, beforeID); err != nil {
+			return 0, err
+		}
+	}
+	if afterID != "" {
+		if after, err = s.repo.GetListByID(boardID, afterID); err != nil {
+			return 0, err
+		}
+	}
+
+	// Completar el vecino que falta con el que está actualmente junto al indicado
+	switch {
+	case before != nil && after == nil:
+		after, err = s.repo.GetNeighborList(boardID, before.Position, true, listID)
+	case before == nil && after != nil:
+		before, err = s.repo.GetNeighborList(boardID, after.Position, false, listID)
+	case before == nil && after == nil:
+		before, err = s.repo.GetNeighborList(boardID, math.Inf(1), false, listID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var beforePosition, afterPosition *float64
+	if before != nil {
+		beforePosition = &before.Position
+	}
+	if after != nil {
+		afterPosition = &after.Position
+	}
+	if beforePosition != nil && afterPosition != nil && *beforePosition >= *afterPosition {
+		return 0, ErrNeighborConflict
+	}
+
+	position, ok := PositionBetween(beforePosition, afterPosition)
+	if ok {
+		return position, nil
+	}
+	if !canRebalance {
+		return 0, ErrNeighborConflict
+	}
+
+	// Ya no hay espacio entre los vecinos, se reasignan todas las posiciones y se calcula de nuevo
+	if err := s.rebalance(boardID); err != nil {
+		return 0, err
+	}
+	return s.resolvePosition(boardID, listID, beforeID, afterID, false)
+}
+
+// rebalance reasigna posiciones separadas por PositionStep conservando el orden actual
+func (s *ListService) rebalance(boardID string) error {
+	lists, err := s.repo.GetListsByBoard(boardID, true)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	positions := make(map[primitive.ObjectID]float64, len(lists))
+	for i := range lists {
+		lists[i].Position = float64(i+1) * PositionStep
+		lists[i].UpdatedAt = now
+		positions[lists[i].ID] = lists[i].Position
+	}
+	if err := s.repo.SetPositions(positions); err != nil {
+		return err
+	}
+
+	for i := range lists {
+		publishList(ListUpdatedEvent, &lists[i])
+	}
+	return nil
+}
+
+func (s *ListService) updateList(boardID, listID string, fields bson.M) (*models.List, error) {
+	id, err := primitive.ObjectIDFromHex(listID)
+	if err != nil {
+		return nil, repositories.ErrListNotFound
+	}
+	if err := s.repo.UpdateList(boardID, id, fields); err != nil {
+		return nil, err
+	}
+
+	list, err := s.repo.GetListByID(boardID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	publishList(ListUpdatedEvent, list)
+	return list, nil
+}
+
+// DeleteListsByBoard elimina las listas del tablero eliminado, task-service las borra al recibir drop-board
+func (s *ListService) DeleteListsByBoard(boardID string) error {
+	return s.repo.DeleteListsByBoard(boardID)
+}
+
+// publishList envía la lista completa a board-events para que task-service mantenga su copia
+func publishList(key string, list *models.List) {
+	listJSON, _ := json.Marshal(list)
+	go kafka.ProduceMessage("", string(listJSON), "board-events", key)
+}
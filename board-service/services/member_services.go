@@ -0,0 +1,329 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MembersUpdatedEvent es la key con la que se publica en board-events la membresía completa del tablero
+const MembersUpdatedEvent = "updated-members"
+
+var (
+	// ErrForbidden indica que el rol del usuario en el tablero no alcanza para la operación
+	ErrForbidden = errors.New("no tienes permisos sobre el tablero")
+	// ErrInvalidRole indica un rol inexistente o que no se puede asignar directamente (owner)
+	ErrInvalidRole = errors.New("rol inválido, usa admin, editor o viewer")
+	// ErrUserNotFound indica que el usuario invitado no está registrado en user-service
+	ErrUserNotFound = errors.New("el usuario no existe")
+	// ErrOwnerMember indica que el owner no puede salir del tablero sin transferirlo antes
+	ErrOwnerMember = errors.New("el owner no puede salir del tablero, primero transfiérelo a otro miembro")
+)
+
+// roleRank ordena los roles de menor a mayor permiso, un rol desconocido vale 0
+var roleRank = map[models.BoardRole]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+	models.RoleOwner:  4,
+}
+
+// BoardEvent es el payload de new-board y updated-members: el tablero con su membresía completa.
+// Los consumidores aplican un updated-members solo si su members_version es mayor a la que ya tienen.
+type BoardEvent struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	OwnerID        string               `json:"owner_id"`
+	Workflow       *models.Workflow     `json:"workflow,omitempty"`
+	Members        []models.BoardMember `json:"members"`
+	MemberIDs      []string             `json:"member_ids"`
+	MembersVersion int64                `json:"members_version"`
+}
+
+// RoleAllows indica si role tiene al menos los permisos de min
+func RoleAllows(role, min models.BoardRole) bool {
+	return roleRank[role] > 0 && roleRank[role] >= roleRank[min]
+}
+
+// MemberRole devuelve el rol del usuario en el tablero, vacío si no es miembro
+func MemberRole(board *models.Board, userID string) models.BoardRole {
+	if board.OwnerID == userID {
+		return models.RoleOwner
+	}
+	for _, member := range board.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// CanManageMember indica si un admin u owner puede asignar role a un miembro con rol target (vacío si aún no es miembro).
+// Solo se gestionan miembros con menos permisos que el actor y no se puede asignar un rol mayor al propio.
+func CanManageMember(actor, target, role models.BoardRole) bool {
+	if !RoleAllows(actor, models.RoleAdmin) || target == models.RoleOwner || role == models.RoleOwner {
+		return false
+	}
+	return roleRank[actor] > roleRank[target] && roleRank[role] <= roleRank[actor]
+}
+
+// Authorize obtiene el tablero verificando que el usuario sea miembro con al menos el rol min
+func (s *BoardService) Authorize(boardID, userID string, min models.BoardRole) (*models.Board, error) {
+	if !primitive.IsValidObjectID(boardID) {
+		return nil, repositories.ErrBoardNotFound
+	}
+
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !RoleAllows(MemberRole(board, userID), min) {
+		return nil, ErrForbidden
+	}
+	return board, nil
+}
+
+// ValidateInvitation verifica que actorID pueda invitar a userID con el rol indicado
+func ValidateInvitation(board *models.Board, actorID, userID string, role models.BoardRole) error {
+	if roleRank[role] == 0 || role == models.RoleOwner {
+		return ErrInvalidRole
+	}
+	if MemberRole(board, userID) != "" {
+		return repositories.ErrMemberExists
+	}
+	if PendingInvitation(board, userID) != nil {
+		return repositories.ErrInvitationExists
+	}
+	if !CanManageMember(MemberRole(board, actorID), "", role) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// PendingInvitation devuelve la invitación pendiente del usuario, nil si no tiene
+func PendingInvitation(board *models.Board, userID string) *models.BoardInvitation {
+	for i := range board.Invitations {
+		if board.Invitations[i].UserID == userID {
+			return &board.Invitations[i]
+		}
+	}
+	return nil
+}
+
+// InviteMember invita al usuario al tablero con el rol indicado, no es miembro hasta que acepta
+func (s *BoardService) InviteMember(board *models.Board, actorID, userID string, role models.BoardRole) (*models.BoardInvitation, error) {
+	if err := ValidateInvitation(board, actorID, userID, role); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.users.UserExists(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	invitation := models.BoardInvitation{UserID: userID, Role: role, InvitedBy: actorID, InvitedAt: time.Now()}
+	if err := s.repo.AddInvitation(board.ID.Hex(), invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// AcceptInvitation agrega al usuario invitado como miembro con el rol de la invitación y publica la membresía
+func (s *BoardService) AcceptInvitation(boardID, userID string) (*models.Board, error) {
+	if !primitive.IsValidObjectID(boardID) {
+		return nil, repositories.ErrBoardNotFound
+	}
+
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := PendingInvitation(board, userID)
+	if invitation == nil {
+		return nil, repositories.ErrInvitationNotFound
+	}
+
+	if err := s.repo.AcceptInvitation(boardID, *invitation); err != nil {
+		return nil, err
+	}
+	return s.publishMembers(boardID)
+}
+
+// RemoveInvitation descarta una invitación pendiente: el invitado la rechaza o un admin la revoca
+func (s *BoardService) RemoveInvitation(boardID, actorID, userID string) error {
+	if !primitive.IsValidObjectID(boardID) {
+		return repositories.ErrBoardNotFound
+	}
+
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return err
+	}
+
+	// Quien no es el invitado ni miembro del tablero no sabe si la invitación existe
+	if actorID != userID && MemberRole(board, actorID) == "" {
+		return repositories.ErrBoardNotFound
+	}
+	if PendingInvitation(board, userID) == nil {
+		return repositories.ErrInvitationNotFound
+	}
+	if actorID != userID && !RoleAllows(MemberRole(board, actorID), models.RoleAdmin) {
+		return ErrForbidden
+	}
+
+	return s.repo.RemoveInvitation(boardID, userID)
+}
+
+// UserInvitation es una invitación pendiente del usuario junto con el tablero al que lo invitaron
+type UserInvitation struct {
+	BoardID   string           `json:"board_id"`
+	BoardName string           `json:"board_name"`
+	Role      models.BoardRole `json:"role"`
+	InvitedBy string           `json:"invited_by"`
+	InvitedAt time.Time        `json:"invited_at"`
+}
+
+// GetInvitations devuelve las invitaciones pendientes del usuario
+func (s *BoardService) GetInvitations(userID string) ([]UserInvitation, error) {
+	boards, err := s.repo.GetBoardsInvitingUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]UserInvitation, 0, len(boards))
+	for i := range boards {
+		if invitation := PendingInvitation(&boards[i], userID); invitation != nil {
+			invitations = append(invitations, UserInvitation{
+				BoardID:   boards[i].ID.Hex(),
+				BoardName: boards[i].Name,
+				Role:      invitation.Role,
+				InvitedBy: invitation.InvitedBy,
+				InvitedAt: invitation.InvitedAt,
+			})
+		}
+	}
+	return invitations, nil
+}
+
+// UpdateMemberRole cambia el rol de un miembro, el owner solo cambia transfiriendo el tablero
+func (s *BoardService) UpdateMemberRole(board *models.Board, actorID, userID string, role models.BoardRole) (*models.Board, error) {
+	if roleRank[role] == 0 || role == models.RoleOwner {
+		return nil, ErrInvalidRole
+	}
+
+	target := MemberRole(board, userID)
+	if target == "" {
+		return nil, repositories.ErrMemberNotFound
+	}
+	if !CanManageMember(MemberRole(board, actorID), target, role) {
+		return nil, ErrForbidden
+	}
+
+	if err := s.repo.UpdateMemberRole(board.ID.Hex(), userID, target, role); err != nil {
+		return nil, err
+	}
+	return s.publishMembers(board.ID.Hex())
+}
+
+// RemoveMember quita a un miembro del tablero, cualquier miembro excepto el owner puede salir por sí mismo
+func (s *BoardService) RemoveMember(board *models.Board, actorID, userID string) (*models.Board, error) {
+	target := MemberRole(board, userID)
+	if target == "" {
+		return nil, repositories.ErrMemberNotFound
+	}
+	if target == models.RoleOwner {
+		return nil, ErrOwnerMember
+	}
+	if actorID != userID && !CanManageMember(MemberRole(board, actorID), target, target) {
+		return nil, ErrForbidden
+	}
+
+	if err := s.repo.RemoveMember(board.ID.Hex(), userID, target); err != nil {
+		return nil, err
+	}
+	return s.publishMembers(board.ID.Hex())
+}
+
+// TransferOwnership pasa el tablero a otro miembro, el owner anterior queda como admin
+func (s *BoardService) TransferOwnership(board *models.Board, actorID, userID string) (*models.Board, error) {
+	if board.OwnerID != actorID {
+		return nil, ErrNotBoardOwner
+	}
+	if MemberRole(board, userID) == "" {
+		return nil, repositories.ErrMemberNotFound
+	}
+	if userID == actorID {
+		return board, nil
+	}
+
+	if err := s.repo.TransferOwnership(board.ID.Hex(), actorID, userID); err != nil {
+		return nil, err
+	}
+	return s.publishMembers(board.ID.Hex())
+}
+
+// MigrateDefaultMembers registra al owner como miembro de los tableros que aún no tienen miembros
+func (s *BoardService) MigrateDefaultMembers() (int64, error) {
+	return s.repo.SetDefaultMembers()
+}
+
+// SyncMembers publica la membresía de todos los tableros para que las proyecciones de los demás servicios
+// se completen, por ejemplo con los tableros creados antes de que existieran los miembros.
+// Los consumidores ignoran las versiones que ya tienen, así que es seguro repetirlo en cada arranque.
+// Los tableros en la papelera no se publican, las proyecciones los conservan hasta recibir restored-board o drop-board.
+func (s *BoardService) SyncMembers() (int, error) {
+	boards, err := s.repo.GetActiveBoards()
+	if err != nil {
+		return 0, err
+	}
+
+	// Se publican uno por uno, ProduceMessage abre un productor por mensaje
+	for i := range boards {
+		eventJSON, _ := json.Marshal(NewBoardEvent(&boards[i], false))
+		if err := kafka.ProduceMessage("", string(eventJSON), "board-events", MembersUpdatedEvent); err != nil {
+			return i, err
+		}
+	}
+	return len(boards), nil
+}
+
+// publishMembers lee el tablero ya actualizado y publica su membresía completa
+func (s *BoardService) publishMembers(boardID string) (*models.Board, error) {
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	eventJSON, _ := json.Marshal(NewBoardEvent(board, false))
+	go kafka.ProduceMessage("", string(eventJSON), "board-events", MembersUpdatedEvent)
+	return board, nil
+}
+
+// NewBoardEvent arma el payload del tablero para board-events, el flujo solo viaja si withWorkflow es true
+func NewBoardEvent(board *models.Board, withWorkflow bool) BoardEvent {
+	event := BoardEvent{
+		ID:             board.ID.Hex(),
+		Name:           board.Name,
+		OwnerID:        board.OwnerID,
+		Members:        board.Members,
+		MemberIDs:      make([]string, 0, len(board.Members)),
+		MembersVersion: board.MembersVersion,
+	}
+	if withWorkflow {
+		event.Workflow = board.Workflow
+	}
+	for _, member := range board.Members {
+		event.MemberIDs = append(event.MemberIDs, member.UserID)
+	}
+	return event
+}
@@ -0,0 +1,68 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/repositories"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func TestMemberRole(t *testing.T) {
+	board := &models.Board{
+		OwnerID: "owner",
+		Members: []models.BoardMember{
+			{UserID: "owner", Role: models.RoleOwner},
+			{UserID: "editor", Role: models.RoleEditor},
+		},
+	}
+
+	assert.Equal(t, models.RoleOwner, services.MemberRole(board, "owner"))
+	assert.Equal(t, models.RoleEditor, services.MemberRole(board, "editor"))
+	assert.Empty(t, services.MemberRole(board, "stranger"))
+
+	assert.True(t, services.RoleAllows(models.RoleEditor, models.RoleViewer))
+	assert.False(t, services.RoleAllows(models.RoleViewer, models.RoleEditor))
+	assert.False(t, services.RoleAllows("", models.RoleViewer))
+}
+
+func TestCanManageMember(t *testing.T) {
+	// Un admin invita editores y admins pero no gestiona a otros admins
+	assert.True(t, services.CanManageMember(models.RoleAdmin, "", models.RoleEditor))
+	assert.True(t, services.CanManageMember(models.RoleAdmin, models.RoleViewer, models.RoleAdmin))
+	assert.False(t, services.CanManageMember(models.RoleAdmin, models.RoleAdmin, models.RoleViewer))
+
+	// El owner gestiona a todos, pero su rol solo cambia transfiriendo el tablero
+	assert.True(t, services.CanManageMember(models.RoleOwner, models.RoleAdmin, models.RoleViewer))
+	assert.False(t, services.CanManageMember(models.RoleOwner, "", models.RoleOwner))
+
+	assert.False(t, services.CanManageMember(models.RoleEditor, "", models.RoleViewer))
+}
+
+func TestValidateInvitation(t *testing.T) {
+	board := &models.Board{
+		OwnerID: "owner",
+		Members: []models.BoardMember{
+			{UserID: "owner", Role: models.RoleOwner},
+			{UserID: "admin", Role: models.RoleAdmin},
+			{UserID: "editor", Role: models.RoleEditor},
+		},
+		Invitations: []models.BoardInvitation{{UserID: "invited", Role: models.RoleViewer}},
+	}
+
+	assert.NoError(t, services.ValidateInvitation(board, "admin", "newcomer", models.RoleEditor))
+	assert.ErrorIs(t, services.ValidateInvitation(board, "admin", "newcomer", models.RoleOwner), services.ErrInvalidRole)
+	assert.ErrorIs(t, services.ValidateInvitation(board, "admin", "editor", models.RoleViewer), repositories.ErrMemberExists)
+	assert.ErrorIs(t, services.ValidateInvitation(board, "admin", "invited", models.RoleEditor), repositories.ErrInvitationExists)
+	assert.ErrorIs(t, services.ValidateInvitation(board, "editor", "newcomer", models.RoleViewer), services.ErrForbidden)
+}
+
+func TestPendingInvitation(t *testing.T) {
+	board := &models.Board{Invitations: []models.BoardInvitation{{UserID: "invited", Role: models.RoleEditor}}}
+
+	invitation := services.PendingInvitation(board, "invited")
+	assert.NotNil(t, invitation)
+	assert.Equal(t, models.RoleEditor, invitation.Role)
+	assert.Nil(t, services.PendingInvitation(board, "stranger"))
+}
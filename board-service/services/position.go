@@ -0,0 +1,25 @@
+package services
+
+// PositionStep es la separación entre posiciones al agregar al final o al reordenar todas
+const PositionStep = 1024.0
+
+// minPositionGap es el espacio mínimo entre vecinos, por debajo se reordena todo para no perder precisión
+const minPositionGap = 1e-9
+
+// PositionBetween calcula una posición entre before y after (cualquiera puede ser nil para los extremos).
+// Devuelve false si ya no hay espacio suficiente entre ambos y hay que reasignar las posiciones.
+func PositionBetween(before, after *float64) (float64, bool) {
+	switch {
+	case before == nil && after == nil:
+		return PositionStep, true
+	case before == nil:
+		return *after - PositionStep, true
+	case after == nil:
+		return *before + PositionStep, true
+	}
+
+	if *after-*before < minPositionGap {
+		return 0, false
+	}
+	return *before + (*after-*before)/2, true
+}
@@ -0,0 +1,33 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func TestPositionBetween(t *testing.T) {
+	first, last := 1024.0, 2048.0
+
+	position, ok := services.PositionBetween(nil, nil)
+	assert.True(t, ok)
+	assert.Equal(t, services.PositionStep, position)
+
+	position, _ = services.PositionBetween(&first, &last)
+	assert.Equal(t, 1536.0, position)
+
+	position, _ = services.PositionBetween(nil, &first)
+	assert.Less(t, position, first)
+
+	position, _ = services.PositionBetween(&last, nil)
+	assert.Greater(t, position, last)
+}
+
+func TestPositionBetween_NoGapLeft(t *testing.T) {
+	before := 1024.0
+	after := before + 1e-12
+
+	_, ok := services.PositionBetween(&before, &after)
+	assert.False(t, ok)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"go.uber.org/zap"
+)
+
+// Keys con las que se publica en board-events el ciclo de vida de un tablero eliminado
+const (
+	BoardTrashedEvent  = "trashed-board"
+	BoardRestoredEvent = "restored-board"
+	BoardDroppedEvent  = "drop-board"
+)
+
+// TrashEvent es el payload de trashed-board, restored-board y drop-board. Version permite a los consumidores
+// descartar un trashed-board que llegue después del restored-board que lo deshace.
+type TrashEvent struct {
+	ID        string     `json:"id"`
+	ActorID   string     `json:"actor_id"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// GetTrash devuelve los tableros del owner que están en la papelera
+func (s *BoardService) GetTrash(ownerID string) ([]models.Board, error) {
+	return s.repo.GetTrashedBoards(ownerID)
+}
+
+// RestoreBoard saca el tablero de la papelera, solo su owner puede restaurarlo.
+// task-service restaura las tareas que se eliminaron junto con el tablero al recibir restored-board,
+// también devuelve cuántas son, nil si task-service aún no había informado cuántas envió a la papelera.
+func (s *BoardService) RestoreBoard(boardID, userID string) (*models.Board, *int64, error) {
+	trashed, err := s.repo.GetTrashedBoardByID(boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if MemberRole(trashed, userID) != models.RoleOwner {
+		return nil, nil, ErrForbidden
+	}
+
+	board, err := s.repo.RestoreBoard(boardID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publishTrash(BoardRestoredEvent, board, userID)
+	return board, trashed.TrashedTasks, nil
+}
+
+// PurgeTrash elimina definitivamente los tableros que llevan en la papelera más de retention junto con sus listas.
+// Si varias réplicas purgan a la vez, solo la que elimina el tablero publica drop-board.
+func (s *BoardService) PurgeTrash(retention time.Duration) (int, error) {
+	cutoff := time.Now().Add(-retention)
+	boards, err := s.repo.GetExpiredBoards(cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range boards {
+		deleted, err := s.repo.PurgeBoard(boards[i].ID, cutoff)
+		if err != nil {
+			return purged, err
+		}
+		if !deleted {
+			continue
+		}
+		purged++
+
+		if err := s.lists.DeleteListsByBoard(boards[i].ID.Hex()); err != nil {
+			return purged, err
+		}
+		if err := s.labels.DeleteLabelsByBoard(boards[i].ID.Hex()); err != nil {
+			return purged, err
+		}
+		eventJSON, _ := json.Marshal(TrashEvent{ID: boards[i].ID.Hex(), Version: boards[i].Version})
+		if err := kafka.ProduceMessage("", string(eventJSON), "board-events", BoardDroppedEvent); err != nil {
+			return purged, err
+		}
+	}
+	return purged, nil
+}
+
+// StartTrashPurge purga la papelera cada interval hasta que se cancele ctx
+func (s *BoardService) StartTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeTrash(retention)
+			if err != nil {
+				logger.Log.Error("No se pudo purgar la papelera de tableros", zap.Int("boards", purged), zap.Error(err))
+			} else if purged > 0 {
+				logger.Log.Info("Tableros eliminados definitivamente de la papelera", zap.Int("boards", purged))
+			}
+		}
+	}
+}
+
+// publishTrash publica el cambio de papelera del tablero en board-events
+func publishTrash(key string, board *models.Board, actorID string) {
+	eventJSON, _ := json.Marshal(TrashEvent{ID: board.ID.Hex(), ActorID: actorID, Version: board.Version, DeletedAt: board.DeletedAt})
+	go kafka.ProduceMessage("", string(eventJSON), "board-events", key)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/board-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+)
+
+// WorkflowUpdatedEvent es la key con la que se publica en board-events el flujo de un tablero
+const WorkflowUpdatedEvent = "updated-workflow"
+
+var (
+	// ErrInvalidWorkflow indica que el flujo enviado no es válido, el detalle va en el mensaje del error
+	ErrInvalidWorkflow = errors.New("flujo de trabajo inválido")
+	// ErrNotBoardOwner indica que solo el owner del tablero puede hacer el cambio
+	ErrNotBoardOwner = errors.New("solo el owner del tablero puede transferirlo")
+)
+
+// WorkflowEvent es el payload de updated-workflow
+type WorkflowEvent struct {
+	BoardID  string           `json:"board_id"`
+	Workflow *models.Workflow `json:"workflow"`
+}
+
+// GetWorkflow devuelve el flujo del tablero, el flujo por defecto si aún no tiene uno propio
+func (s *BoardService) GetWorkflow(boardID string) (*models.Workflow, error) {
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.Workflow == nil {
+		return models.DefaultWorkflow(), nil
+	}
+	return board.Workflow, nil
+}
+
+// UpdateWorkflow reemplaza el flujo del tablero y lo publica para que task-service lo aplique.
+// Las tareas que queden en un estado eliminado pueden pasar a cualquier estado del flujo nuevo.
+func (s *BoardService) UpdateWorkflow(boardID string, workflow *models.Workflow) (*models.Workflow, error) {
+	if err := ValidateWorkflow(workflow); err != nil {
+		return nil, err
+	}
+	if workflow.Transitions == nil {
+		workflow.Transitions = map[string][]string{}
+	}
+	workflow.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateBoardWorkflow(boardID, workflow); err != nil {
+		return nil, err
+	}
+
+	publishWorkflow(boardID, workflow)
+	return workflow, nil
+}
+
+// ValidateWorkflow verifica que el flujo tenga estados con key y nombre, sin keys repetidas,
+// y que las transiciones solo usen estados del flujo
+func ValidateWorkflow(workflow *models.Workflow) error {
+	if workflow == nil || len(workflow.Statuses) == 0 {
+		return fmt.Errorf("%w: debe tener al menos un estado", ErrInvalidWorkflow)
+	}
+
+	keys := make(map[string]bool, len(workflow.Statuses))
+	for _, status := range workflow.Statuses {
+		if strings.TrimSpace(status.Key) == "" || strings.TrimSpace(status.Name) == "" {
+			return fmt.Errorf("%w: cada estado necesita key y nombre", ErrInvalidWorkflow)
+		}
+		if keys[status.Key] {
+			return fmt.Errorf("%w: el estado %s está repetido", ErrInvalidWorkflow, status.Key)
+		}
+		keys[status.Key] = true
+	}
+
+	for from, targets := range workflow.Transitions {
+		if !keys[from] {
+			return fmt.Errorf("%w: la transición parte de un estado inexistente: %s", ErrInvalidWorkflow, from)
+		}
+		for _, to := range targets {
+			if !keys[to] {
+				return fmt.Errorf("%w: la transición %s → %s usa un estado inexistente", ErrInvalidWorkflow, from, to)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MigrateDefaultWorkflow asigna el flujo por defecto a los tableros que aún no tienen uno
+func (s *BoardService) MigrateDefaultWorkflow() (int64, error) {
+	return s.repo.SetDefaultWorkflow(models.DefaultWorkflow())
+}
+
+func publishWorkflow(boardID string, workflow *models.Workflow) {
+	eventJSON, _ := json.Marshal(WorkflowEvent{BoardID: boardID, Workflow: workflow})
+	go kafka.ProduceMessage("", string(eventJSON), "board-events", WorkflowUpdatedEvent)
+}
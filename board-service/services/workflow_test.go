@@ -0,0 +1,37 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/board-service/models"
+	"github.com/vadgun/gotrelloclone/board-service/services"
+)
+
+func TestValidateWorkflow(t *testing.T) {
+	assert.NoError(t, services.ValidateWorkflow(models.DefaultWorkflow()))
+
+	workflow := &models.Workflow{
+		Statuses: []models.WorkflowStatus{
+			{Key: "BACKLOG", Name: "Backlog"},
+			{Key: "REVIEW", Name: "Review"},
+		},
+		Transitions: map[string][]string{"BACKLOG": {"REVIEW"}},
+	}
+	assert.NoError(t, services.ValidateWorkflow(workflow))
+
+	workflow.Transitions["REVIEW"] = []string{"QA"}
+	assert.ErrorIs(t, services.ValidateWorkflow(workflow), services.ErrInvalidWorkflow)
+}
+
+func TestValidateWorkflow_RejectsDuplicatedStatus(t *testing.T) {
+	workflow := &models.Workflow{
+		Statuses: []models.WorkflowStatus{
+			{Key: "DONE", Name: "Hecho"},
+			{Key: "DONE", Name: "Terminado"},
+		},
+	}
+
+	assert.ErrorIs(t, services.ValidateWorkflow(workflow), services.ErrInvalidWorkflow)
+	assert.ErrorIs(t, services.ValidateWorkflow(&models.Workflow{}), services.ErrInvalidWorkflow)
+}
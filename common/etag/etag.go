@@ -0,0 +1,34 @@
+// Package etag expone la versión de los recursos como ETag y valida el header If-Match de las ediciones,
+// lo comparten board-service y task-service para responder igual a los conflictos de versión
+package etag
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Set expone la versión del recurso en el header ETag
+func Set(ctx *gin.Context, version int64) {
+	ctx.Header("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// IfMatchVersion obtiene la versión del header If-Match que el cliente editó.
+// Si falta o no es válida responde 428 o 400 y devuelve false.
+func IfMatchVersion(ctx *gin.Context) (int64, bool) {
+	ifMatch := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if ifMatch == "" {
+		ctx.JSON(http.StatusPreconditionRequired, gin.H{"error": "Se requiere el header If-Match con el ETag de la versión editada"})
+		return 0, false
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil || version < 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "If-Match inválido"})
+		return 0, false
+	}
+	return version, true
+}
@@ -0,0 +1,62 @@
+package etag_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/common/etag"
+)
+
+// ifMatchContext arma un contexto de gin con el header If-Match indicado, vacío si no se envía
+func ifMatchContext(ifMatch string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+	if ifMatch != "" {
+		ctx.Request.Header.Set("If-Match", ifMatch)
+	}
+	return ctx, recorder
+}
+
+func TestSet(t *testing.T) {
+	ctx, recorder := ifMatchContext("")
+
+	etag.Set(ctx, 7)
+
+	assert.Equal(t, `"7"`, recorder.Header().Get("ETag"))
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	for _, ifMatch := range []string{`"3"`, `W/"3"`, "3"} {
+		ctx, _ := ifMatchContext(ifMatch)
+
+		version, ok := etag.IfMatchVersion(ctx)
+
+		assert.True(t, ok, ifMatch)
+		assert.Equal(t, int64(3), version, ifMatch)
+	}
+}
+
+func TestIfMatchVersion_Missing(t *testing.T) {
+	ctx, recorder := ifMatchContext("")
+
+	_, ok := etag.IfMatchVersion(ctx)
+
+	assert.False(t, ok)
+	assert.Equal(t, http.StatusPreconditionRequired, recorder.Code)
+}
+
+func TestIfMatchVersion_Invalid(t *testing.T) {
+	for _, ifMatch := range []string{`"abc"`, `"-1"`} {
+		ctx, recorder := ifMatchContext(ifMatch)
+
+		_, ok := etag.IfMatchVersion(ctx)
+
+		assert.False(t, ok, ifMatch)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, ifMatch)
+	}
+}
@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -11,16 +13,18 @@ import (
 	"github.com/vadgun/gotrelloclone/notification-service/infra/metrics"
 	"github.com/vadgun/gotrelloclone/notification-service/models"
 	"github.com/vadgun/gotrelloclone/notification-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
 
 type NotificationHandler struct {
 	service          *services.NotificationService
 	webSocketService *services.WebSocketService
+	sseService       *services.SSEService
 }
 
-func NewNotificationHandler(service *services.NotificationService, wsService *services.WebSocketService) *NotificationHandler {
-	return &NotificationHandler{service: service, webSocketService: wsService}
+func NewNotificationHandler(service *services.NotificationService, wsService *services.WebSocketService, sseService *services.SSEService) *NotificationHandler {
+	return &NotificationHandler{service: service, webSocketService: wsService, sseService: sseService}
 }
 
 func (h *NotificationHandler) SendNotification(ctx *gin.Context) {
@@ -40,8 +44,8 @@ func (h *NotificationHandler) SendNotification(ctx *gin.Context) {
 		return
 	}
 
-	// Enviar notificación a WebSocket service
-	h.service.SendNotificationWebSocket(notification.Message)
+	// Enviar notificación en tiempo real por WebSocket y SSE
+	h.service.PublishNotification(&notification)
 
 	// Creando un log personalizado cuando se crea una notificacion
 	logger.Log.Info("Creando Notificacion", zap.String("endpoint", ctx.Request.URL.Path), zap.String("ip", ctx.ClientIP()))
@@ -49,26 +53,262 @@ func (h *NotificationHandler) SendNotification(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Notificación enviada"})
 }
 
-// Método para manejar conexiones WebSocket service
+// GetNotifications lista el inbox del usuario autenticado con paginación por cursor
+func (h *NotificationHandler) GetNotifications(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	limit, _ := strconv.ParseInt(ctx.Query("limit"), 10, 64)
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	unreadOnly := ctx.Query("unread") == "true"
+
+	notifications, nextCursor, err := h.service.GetNotifications(ctx, userID.(string), ctx.Query("cursor"), limit, unreadOnly)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "Cursor inválido"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener las notificaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"notifications": notifications, "next_cursor": nextCursor})
+}
+
+// GetUnreadCount devuelve el número de notificaciones sin leer, usado por el badge de la campana
+func (h *NotificationHandler) GetUnreadCount(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	count, err := h.service.CountUnread(ctx, userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo obtener el conteo de notificaciones"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"unread": count})
+}
+
+// MarkAsRead marca una notificación como leída
+func (h *NotificationHandler) MarkAsRead(ctx *gin.Context) {
+	h.markAsRead(ctx, []string{ctx.Param("notificationID")})
+}
+
+// MarkManyAsRead marca como leídas varias notificaciones
+func (h *NotificationHandler) MarkManyAsRead(ctx *gin.Context) {
+	var request struct {
+		IDs []string `json:"ids" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	h.markAsRead(ctx, request.IDs)
+}
+
+func (h *NotificationHandler) markAsRead(ctx *gin.Context, ids []string) {
+	userID, _ := ctx.Get("userID")
+
+	updated, err := h.service.MarkAsRead(ctx, userID.(string), ids)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationID) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID de notificación inválido"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron marcar las notificaciones como leídas"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// MarkAllAsRead marca como leídas todas las notificaciones del usuario
+func (h *NotificationHandler) MarkAllAsRead(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	updated, err := h.service.MarkAllAsRead(ctx, userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron marcar las notificaciones como leídas"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// DeleteNotification elimina una notificación del usuario
+func (h *NotificationHandler) DeleteNotification(ctx *gin.Context) {
+	h.deleteNotifications(ctx, []string{ctx.Param("notificationID")})
+}
+
+// DeleteNotifications elimina varias notificaciones del usuario
+func (h *NotificationHandler) DeleteNotifications(ctx *gin.Context) {
+	var request struct {
+		IDs []string `json:"ids" binding:"required,min=1"`
+	}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	h.deleteNotifications(ctx, request.IDs)
+}
+
+func (h *NotificationHandler) deleteNotifications(ctx *gin.Context, ids []string) {
+	userID, _ := ctx.Get("userID")
+
+	deleted, err := h.service.DeleteNotifications(ctx, userID.(string), ids)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidNotificationID) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": "ID de notificación inválido"})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron eliminar las notificaciones"})
+		return
+	}
+
+	if deleted == 0 {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Notificación no encontrada"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// GetPreferences devuelve las preferencias de notificación del usuario, las de por defecto si nunca las guardó
+func (h *NotificationHandler) GetPreferences(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	preferences, err := h.service.GetPreferences(ctx, userID.(string))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener las preferencias"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// UpdatePreferences reemplaza las preferencias del usuario, los campos omitidos toman su valor por defecto
+func (h *NotificationHandler) UpdatePreferences(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	preferences := models.DefaultPreferences(userID.(string))
+	if err := ctx.ShouldBindJSON(&preferences); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	preferences.UserID = userID.(string)
+
+	if err := h.service.UpdatePreferences(ctx, &preferences); err != nil {
+		if errors.Is(err, services.ErrInvalidPreferences) {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron guardar las preferencias"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// Método para manejar conexiones WebSocket service del usuario autenticado.
+// Al reconectar el cliente puede enviar `last_id` (última notificación vista) o `since` (RFC3339)
+// para recibir primero las notificaciones que se emitieron mientras estuvo desconectado.
 func (h *NotificationHandler) HandleConnections(c *gin.Context) {
-	h.webSocketService.HandleConnections(c.Writer, c.Request)
+	userID, _ := c.Get("userID")
+	replay, ok := h.replayFromRequest(c, userID.(string), c.Query("last_id"))
+	if !ok {
+		return
+	}
+
+	h.webSocketService.HandleConnections(c.Writer, c.Request, userID.(string), c.GetTime("tokenExpiresAt"), replay)
 }
 
-func (h *NotificationHandler) HandleKafkaMessage(notification models.Notification) error {
-	// var notification models.Notification
-	// if err := json.Unmarshal(message.Value, &notification); err != nil {
-	// 	return fmt.Errorf("error al deserializar el mensaje: %v", err)
-	// }
+// StreamNotifications entrega las mismas notificaciones que /ws por Server-Sent Events.
+// El navegador reenvía el header Last-Event-ID al reconectar, en la primera conexión se acepta `last_id` o `since`.
+func (h *NotificationHandler) StreamNotifications(c *gin.Context) {
+	userID, _ := c.Get("userID")
 
-	// notification.CreatedAt = time.Now()
+	lastID := c.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = c.Query("last_id")
+	}
 
-	// Guardar la notificación directamente usando el repositorio
-	if err := h.service.CreateNotification(context.Background(), &notification); err != nil {
-		return fmt.Errorf("error al guardar la notificación: %v", err)
+	replay, ok := h.replayFromRequest(c, userID.(string), lastID)
+	if !ok {
+		return
 	}
 
-	// Enviar notificación a WebSocket service
-	h.service.SendNotificationWebSocket(notification.Message)
+	h.sseService.HandleStream(c.Writer, c.Request, userID.(string), c.GetTime("tokenExpiresAt"), replay)
+}
+
+// replayFromRequest construye la consulta de notificaciones perdidas a partir del último ID visto
+// o del query param `since`. Responde 400 y devuelve false si los parámetros son inválidos.
+func (h *NotificationHandler) replayFromRequest(c *gin.Context, userID, lastIDParam string) (services.ReplayFunc, bool) {
+	var lastID primitive.ObjectID
+	var since time.Time
+	var err error
+
+	if lastIDParam != "" {
+		lastID, err = primitive.ObjectIDFromHex(lastIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "last_id inválido"})
+			return nil, false
+		}
+	} else if sinceParam := c.Query("since"); sinceParam != "" {
+		since, err = time.Parse(time.RFC3339, sinceParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since inválido, se espera formato RFC3339"})
+			return nil, false
+		}
+	}
+
+	if lastID.IsZero() && since.IsZero() {
+		return nil, true
+	}
+
+	return func() ([]models.Notification, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return h.service.GetMissedNotifications(ctx, userID, lastID, since)
+	}, true
+}
 
+// HandleTaskEvent guarda y envía en tiempo real las notificaciones del evento de tarea recibido desde Kafka
+func (h *NotificationHandler) HandleTaskEvent(key string, event models.TaskEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.service.NotifyTaskEvent(ctx, key, event); err != nil {
+		return fmt.Errorf("error al notificar el evento %s: %v", key, err)
+	}
+	return nil
+}
+
+// HandleBoardEvent mantiene la proyección de tableros con los eventos de board-service
+func (h *NotificationHandler) HandleBoardEvent(key string, board models.Board) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch key {
+	case models.BoardCreatedEvent, models.BoardMembersUpdatedEvent:
+		return h.service.SaveBoard(ctx, &board)
+	case models.BoardDeletedEvent:
+		return h.service.DeleteBoard(ctx, board.ID)
+	}
+	return nil
+}
+
+// HandleUserEvent mantiene la proyección de usuarios con los eventos de user-service
+func (h *NotificationHandler) HandleUserEvent(key string, user models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if key == "new-user" {
+		return h.service.SaveUser(ctx, &user)
+	}
 	return nil
 }
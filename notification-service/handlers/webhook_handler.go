@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+)
+
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// CreateWebhook registra un webhook en el tablero, el secreto de firma solo se devuelve en esta respuesta
+func (h *WebhookHandler) CreateWebhook(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	var webhook models.Webhook
+	if err := ctx.ShouldBindJSON(&webhook); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+	webhook.BoardID = ctx.Param("boardID")
+
+	if err := h.service.CreateWebhook(ctx, userID.(string), &webhook); err != nil {
+		h.respondError(ctx, err, "No se pudo registrar el webhook")
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhooks lista los webhooks del tablero
+func (h *WebhookHandler) GetWebhooks(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	webhooks, err := h.service.GetWebhooks(ctx, userID.(string), ctx.Param("boardID"))
+	if err != nil {
+		h.respondError(ctx, err, "No se pudieron obtener los webhooks")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook elimina el webhook del tablero
+func (h *WebhookHandler) DeleteWebhook(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	err := h.service.DeleteWebhook(ctx, userID.(string), ctx.Param("boardID"), ctx.Param("webhookID"))
+	if err != nil {
+		h.respondError(ctx, err, "No se pudo eliminar el webhook")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook eliminado correctamente"})
+}
+
+// GetDeliveries lista las entregas más recientes del webhook
+func (h *WebhookHandler) GetDeliveries(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	limit, _ := strconv.ParseInt(ctx.Query("limit"), 10, 64)
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	deliveries, err := h.service.GetDeliveries(ctx, userID.(string), ctx.Param("boardID"), ctx.Param("webhookID"), limit)
+	if err != nil {
+		h.respondError(ctx, err, "No se pudieron obtener las entregas")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver vuelve a enviar una entrega, el resultado queda como una entrega nueva en el log
+func (h *WebhookHandler) Redeliver(ctx *gin.Context) {
+	userID, _ := ctx.Get("userID")
+
+	delivery, err := h.service.Redeliver(ctx, userID.(string), ctx.Param("boardID"), ctx.Param("webhookID"), ctx.Param("deliveryID"))
+	if err != nil {
+		h.respondError(ctx, err, "No se pudo reenviar la entrega")
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
+
+func (h *WebhookHandler) respondError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tablero no encontrado"})
+	case errors.Is(err, services.ErrNotBoardMember):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No tienes acceso a este tablero"})
+	case errors.Is(err, services.ErrNotBoardAdmin):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhookNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Webhook no encontrado"})
+	case errors.Is(err, services.ErrDeliveryNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Entrega no encontrada"})
+	case errors.Is(err, services.ErrInvalidEventType), errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrWebhookTargetDenied):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
+
+// HandleKafkaEvent registra las entregas de los webhooks de los tableros afectados por el evento
+func (h *WebhookHandler) HandleKafkaEvent(topic, key string, value []byte) error {
+	var boardIDs []string
+
+	switch topic {
+	case "task-events":
+		var event models.TaskEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		boardIDs = append(boardIDs, event.Task.BoardID)
+		if event.OldBoardID != "" && event.OldBoardID != event.Task.BoardID {
+			boardIDs = append(boardIDs, event.OldBoardID)
+		}
+	case "board-events":
+		var board models.Board
+		if err := json.Unmarshal(value, &board); err != nil {
+			return err
+		}
+		boardIDs = append(boardIDs, board.ID)
+	default:
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return h.service.EnqueueEvent(ctx, key, boardIDs, value)
+}
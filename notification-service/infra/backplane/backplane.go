@@ -0,0 +1,55 @@
+package backplane
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+)
+
+// Backplane reparte las notificaciones salientes entre todas las réplicas de notification-service,
+// cada réplica se suscribe y las entrega a las conexiones que mantiene localmente.
+type Backplane interface {
+	// Publish envía la notificación a todas las réplicas suscritas, incluida la que publica
+	Publish(ctx context.Context, notification models.Notification) error
+	// Subscribe registra handler para recibir las notificaciones publicadas hasta que ctx termine
+	Subscribe(ctx context.Context, handler func(models.Notification)) error
+}
+
+// InMemoryBackplane entrega las notificaciones dentro del mismo proceso, para un solo nodo y pruebas
+type InMemoryBackplane struct {
+	handlers map[int]func(models.Notification)
+	nextID   int
+	mu       sync.RWMutex
+}
+
+func NewInMemoryBackplane() *InMemoryBackplane {
+	return &InMemoryBackplane{handlers: make(map[int]func(models.Notification))}
+}
+
+func (b *InMemoryBackplane) Publish(ctx context.Context, notification models.Notification) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, handler := range b.handlers {
+		handler(notification)
+	}
+	return nil
+}
+
+func (b *InMemoryBackplane) Subscribe(ctx context.Context, handler func(models.Notification)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.handlers, id)
+		b.mu.Unlock()
+	}()
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package backplane_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/backplane"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInMemoryBackplane_DeliversToEveryReplica(t *testing.T) {
+	bp := backplane.NewInMemoryBackplane()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cada handler representa una réplica con sus propias conexiones locales
+	replicaA := make(chan models.Notification, 1)
+	replicaB := make(chan models.Notification, 1)
+	assert.NoError(t, bp.Subscribe(ctx, func(n models.Notification) { replicaA <- n }))
+	assert.NoError(t, bp.Subscribe(ctx, func(n models.Notification) { replicaB <- n }))
+
+	notification := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1", Message: "Nueva tarea creada"}
+	assert.NoError(t, bp.Publish(ctx, notification))
+
+	assert.Equal(t, notification.ID, (<-replicaA).ID)
+	assert.Equal(t, notification.ID, (<-replicaB).ID)
+}
+
+func TestInMemoryBackplane_StopsAfterContextDone(t *testing.T) {
+	bp := backplane.NewInMemoryBackplane()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	received := make(chan models.Notification, 1)
+	assert.NoError(t, bp.Subscribe(ctx, func(n models.Notification) { received <- n }))
+
+	// Al cancelar el contexto la réplica deja de recibir notificaciones
+	cancel()
+	assert.Eventually(t, func() bool {
+		bp.Publish(context.Background(), models.Notification{UserID: "user-1"})
+		select {
+		case <-received:
+			return false
+		default:
+			return true
+		}
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,63 @@
+package backplane
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+)
+
+// RedisBackplane usa Redis pub/sub para que una notificación consumida por cualquier réplica
+// llegue a las conexiones de todas las demás
+type RedisBackplane struct {
+	client  *redis.Client
+	channel string
+}
+
+func NewRedisBackplane(client *redis.Client, channel string) *RedisBackplane {
+	return &RedisBackplane{client: client, channel: channel}
+}
+
+func (b *RedisBackplane) Publish(ctx context.Context, notification models.Notification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+func (b *RedisBackplane) Subscribe(ctx context.Context, handler func(models.Notification)) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+
+	// Esperar la confirmación para no perder lo que se publique justo después de suscribirse
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return err
+	}
+
+	go func() {
+		defer pubsub.Close()
+		// El canal de go-redis se reconecta solo si se pierde la conexión con Redis
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var notification models.Notification
+				if err := json.Unmarshal([]byte(message.Payload), &notification); err != nil {
+					log.Printf("⚠️ Error parseando notificación del backplane: %v\n", err)
+					continue
+				}
+				handler(notification)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -5,24 +5,103 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var DB *mongo.Database
+var JWTSecret string
+
+// AllowedOrigins contiene los orígenes que pueden abrir conexiones WebSocket
+var AllowedOrigins []string
+
+// BackplaneDriver indica cómo se reparten las notificaciones entre réplicas: "memory" (un solo nodo) o "redis"
+var BackplaneDriver string
+
+var RedisClient *redis.Client
+var RedisChannel string
+
+// Parámetros de las conexiones en tiempo real
+var (
+	SendBuffer     int           // Notificaciones pendientes por conexión antes de aplicar OverflowPolicy
+	OverflowPolicy string        // "drop-oldest" descarta la más antigua, "disconnect" cierra la conexión lenta
+	PingInterval   time.Duration // Cada cuánto se envía un ping al cliente WebSocket
+	PongWait       time.Duration // Tiempo máximo sin recibir pong antes de cerrar la conexión
+	WriteWait      time.Duration // Tiempo máximo para escribir un mensaje en la conexión
+)
+
+// Canal de correo, si SMTP_HOST está vacío no se envían emails
+var (
+	SMTPHost       string
+	SMTPPort       string
+	SMTPUser       string // Opcional, MailHog no requiere autenticación
+	SMTPPass       string
+	SMTPFrom       string
+	DigestInterval time.Duration // Cada cuánto se envía el resumen de las notificaciones por email pendientes
+)
+
+type JWTClaims struct {
+	jwt.RegisteredClaims
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
 
 // InitConfig carga las variables de entorno y configura la base de datos
 func InitConfig() {
 	// Obtener valores de entorno
 	mongoURI := os.Getenv("MONGO_URI")
 	dbName := os.Getenv("MONGO_DB_NAME")
+	JWTSecret = os.Getenv("JWT_SECRET")
 
-	if mongoURI == "" || dbName == "" {
+	if mongoURI == "" || dbName == "" || JWTSecret == "" {
 		log.Fatal("Faltan variables de entorno necesarias")
 	}
 
+	// Orígenes permitidos separados por comas, por defecto solo el frontend local
+	AllowedOrigins = []string{"http://localhost:5173"}
+	if origins := os.Getenv("WS_ALLOWED_ORIGINS"); origins != "" {
+		AllowedOrigins = nil
+		for _, origin := range strings.Split(origins, ",") {
+			if origin = strings.TrimSpace(origin); origin != "" {
+				AllowedOrigins = append(AllowedOrigins, origin)
+			}
+		}
+	}
+
+	BackplaneDriver = os.Getenv("NOTIFICATION_BACKPLANE")
+	if BackplaneDriver == "" {
+		BackplaneDriver = "memory"
+	}
+
+	SendBuffer = intEnv("WS_SEND_BUFFER", 64)
+	OverflowPolicy = os.Getenv("WS_OVERFLOW_POLICY")
+	if OverflowPolicy == "" {
+		OverflowPolicy = "drop-oldest"
+	}
+	PingInterval = durationEnv("WS_PING_INTERVAL", 30*time.Second)
+	PongWait = durationEnv("WS_PONG_WAIT", 60*time.Second)
+	WriteWait = durationEnv("WS_WRITE_WAIT", 10*time.Second)
+
+	SMTPHost = os.Getenv("SMTP_HOST")
+	SMTPPort = os.Getenv("SMTP_PORT")
+	if SMTPPort == "" {
+		SMTPPort = "25"
+	}
+	SMTPUser = os.Getenv("SMTP_USER")
+	SMTPPass = os.Getenv("SMTP_PASS")
+	SMTPFrom = os.Getenv("SMTP_FROM")
+	if SMTPFrom == "" {
+		SMTPFrom = "notificaciones@gotrelloclone.local"
+	}
+	DigestInterval = durationEnv("EMAIL_DIGEST_INTERVAL", 24*time.Hour)
+
 	// Configurar conexión a MongoDB
 	clientOptions := options.Client().ApplyURI(mongoURI)
 	client, err := mongo.Connect(context.TODO(), clientOptions)
@@ -42,3 +121,51 @@ func InitConfig() {
 	DB = client.Database(dbName)
 	fmt.Println("✅ Conectado a MongoDB desde notification-service:", dbName)
 }
+
+// InitRedis configura el cliente de Redis usado como backplane entre réplicas
+func InitRedis() {
+	// Obtener valores de entorno
+	redisAddr := os.Getenv("REDIS_ADDR")
+	redisPass := os.Getenv("REDIS_PASS")
+	RedisChannel = os.Getenv("REDIS_CHANNEL")
+
+	if redisAddr == "" {
+		log.Fatal("Falta la variable de entorno REDIS_ADDR")
+	}
+	if RedisChannel == "" {
+		RedisChannel = "notifications"
+	}
+
+	RedisClient = redis.NewClient(&redis.Options{
+		Addr:     redisAddr,
+		Password: redisPass,
+		DB:       0,
+	})
+
+	// Verificar conexión
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := RedisClient.Ping(ctx).Err(); err != nil {
+		log.Fatal("No se pudo conectar a Redis:", err)
+	}
+
+	fmt.Println("✅ Conectado a Redis desde notification-service:", redisAddr)
+}
+
+// intEnv obtiene un entero de las variables de entorno o el valor por defecto
+func intEnv(name string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// durationEnv obtiene una duración (por ejemplo "30s") de las variables de entorno o el valor por defecto
+func durationEnv(name string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
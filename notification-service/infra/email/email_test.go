@@ -0,0 +1,58 @@
+package email_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/email"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+)
+
+func TestRender_UsesTemplateOfEventType(t *testing.T) {
+	templates, err := email.LoadTemplates()
+	assert.NoError(t, err)
+
+	notification := models.Notification{Type: models.TaskAssignedEvent, Message: "Te asignaron la tarea: <Diseño>", CreatedAt: time.Now()}
+	text, html, err := templates.Render(notification.Type, email.TemplateData{UserName: "Ana", Notification: notification})
+
+	assert.NoError(t, err)
+	assert.Contains(t, text, "Hola Ana,")
+	assert.Contains(t, text, "Te asignaron la tarea: <Diseño>")
+	assert.Contains(t, html, "Tienes una tarea asignada")
+	assert.Contains(t, html, "Te asignaron la tarea: &lt;Diseño&gt;")
+}
+
+func TestRender_FallsBackToDefault(t *testing.T) {
+	templates, err := email.LoadTemplates()
+	assert.NoError(t, err)
+
+	text, _, err := templates.Render("unknown", email.TemplateData{Notification: models.Notification{Message: "Hola"}})
+
+	assert.NoError(t, err)
+	assert.Contains(t, text, "Tienes una nueva notificación.")
+}
+
+func TestRender_Digest(t *testing.T) {
+	templates, err := email.LoadTemplates()
+	assert.NoError(t, err)
+
+	notifications := []models.Notification{{Message: "Primera"}, {Message: "Segunda"}}
+	text, _, err := templates.Render(email.DigestTemplate, email.TemplateData{Notifications: notifications})
+
+	assert.NoError(t, err)
+	assert.Contains(t, text, "Tienes 2 notificaciones")
+	assert.Contains(t, text, "- Primera")
+	assert.Contains(t, text, "- Segunda")
+}
+
+func TestBuildMessage(t *testing.T) {
+	body, err := email.BuildMessage("from@test.local", email.Message{To: "to@test.local", Subject: "Tarea asignada", Text: "texto", HTML: "<p>html</p>"})
+
+	assert.NoError(t, err)
+	message := string(body)
+	assert.Contains(t, message, "To: to@test.local\r\n")
+	assert.Contains(t, message, "Content-Type: multipart/alternative")
+	assert.True(t, strings.Index(message, "text/plain") < strings.Index(message, "text/html"))
+}
@@ -0,0 +1,90 @@
+package email
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+)
+
+// Message es un correo con versión en texto plano y HTML
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Sender envía correos, permite reemplazar SMTP en pruebas
+type Sender interface {
+	Send(msg Message) error
+}
+
+// SMTPSender envía los correos a un servidor SMTP, en desarrollo a MailHog
+type SMTPSender struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPSender(host, port, user, pass, from string) *SMTPSender {
+	sender := &SMTPSender{addr: net.JoinHostPort(host, port), from: from}
+	if user != "" {
+		sender.auth = smtp.PlainAuth("", user, pass, host)
+	}
+	return sender
+}
+
+func (s *SMTPSender) Send(msg Message) error {
+	body, err := BuildMessage(s.from, msg)
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, body)
+}
+
+// BuildMessage arma el correo MIME multipart/alternative, los clientes muestran la parte HTML si la soportan
+func BuildMessage(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", writer.Boundary())
+
+	if err := writePart(writer, "text/plain", msg.Text); err != nil {
+		return nil, err
+	}
+	if err := writePart(writer, "text/html", msg.HTML); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writePart(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := quotedprintable.NewWriter(part)
+	if _, err := encoder.Write([]byte(content)); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
@@ -0,0 +1,63 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+)
+
+// defaultTemplate se usa para los tipos de evento que no tienen plantilla propia
+const defaultTemplate = "default"
+
+// DigestTemplate es la plantilla del resumen periódico
+const DigestTemplate = "digest"
+
+//go:embed templates
+var templatesFS embed.FS
+
+// TemplateData son los datos disponibles en las plantillas de correo
+type TemplateData struct {
+	UserName      string
+	Notification  models.Notification   // Notificación de un correo inmediato
+	Notifications []models.Notification // Notificaciones del resumen
+}
+
+// Templates contiene las plantillas HTML y de texto, una por tipo de evento: <tipo>.html y <tipo>.txt
+type Templates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+func LoadTemplates() (*Templates, error) {
+	html, err := htmltemplate.ParseFS(templatesFS, "templates/*.html")
+	if err != nil {
+		return nil, err
+	}
+	text, err := texttemplate.ParseFS(templatesFS, "templates/*.txt")
+	if err != nil {
+		return nil, err
+	}
+	return &Templates{html: html, text: text}, nil
+}
+
+// Render genera las versiones de texto y HTML de la plantilla indicada, o de la de por defecto si no existe
+func (t *Templates) Render(name string, data TemplateData) (string, string, error) {
+	textTemplate := t.text.Lookup(name + ".txt")
+	htmlTemplate := t.html.Lookup(name + ".html")
+	if textTemplate == nil || htmlTemplate == nil {
+		textTemplate = t.text.Lookup(defaultTemplate + ".txt")
+		htmlTemplate = t.html.Lookup(defaultTemplate + ".html")
+	}
+
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return "", "", err
+	}
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return "", "", err
+	}
+	return text.String(), html.String(), nil
+}
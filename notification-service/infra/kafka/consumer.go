@@ -2,24 +2,14 @@ package kafka
 
 import (
 	"encoding/json"
-	"fmt"
 	"log"
-	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/vadgun/gotrelloclone/notification-service/handlers"
 	"github.com/vadgun/gotrelloclone/notification-service/models"
 )
 
-// Task representa el esquema de la tarea recibida mediante el evento de Kafka
-type Task struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	UserID      string `json:"user_id"`
-}
-
-// StartConsumer inicia el consumidor de Kafka en notification-service, escuchando eventos de task.
+// StartConsumer inicia el consumidor de Kafka en notification-service, escuchando eventos de task, board y user.
 func StartConsumer(kafkaHandler *handlers.NotificationHandler) {
 	c, err := kafka.NewConsumer(&kafka.ConfigMap{
 		"bootstrap.servers": "kafka:9092",
@@ -31,62 +21,56 @@ func StartConsumer(kafkaHandler *handlers.NotificationHandler) {
 	}
 	defer c.Close()
 
-	// Suscribirse al tópico
-	topic := "task-events"
-	err = c.SubscribeTopics([]string{topic}, nil)
+	// Suscribirse a los tópicos, board-events y user-events alimentan las proyecciones usadas para elegir
+	// destinatarios y sus emails
+	topics := []string{"task-events", "board-events", "user-events"}
+	err = c.SubscribeTopics(topics, nil)
 	if err != nil {
 		log.Fatalf("❌ Error suscribiéndose a Kafka: %v", err)
 	}
 
-	log.Println("📩 Escuchando eventos de task-service para notification-service en Kafka...")
+	log.Println("📩 Escuchando eventos de task-service, board-service y user-service para notification-service en Kafka...")
 
 	// Loop infinito para escuchar eventos
 	for {
 		msg, err := c.ReadMessage(-1)
 		if err == nil {
+			log.Printf("📨 Evento recibido | Topic: %s | Message: %s| Key: %s\n", *msg.TopicPartition.Topic, string(msg.Value), string([]byte(msg.Key)))
 
-			// Diferenciar eventos por tópico y por key para decidir que hacer en nuestra base de datos de notification-service
+			// Diferenciar eventos por tópico, la key indica el tipo de evento
 			switch *msg.TopicPartition.Topic {
 			case "task-events":
-				log.Printf("📨 Evento recibido | Topic: %s | Message: %s| Key: %s\n", *msg.TopicPartition.Topic, string(msg.Value), string([]byte(msg.Key)))
-				switch string([]byte(msg.Key)) {
-				case "new-task":
-					var task Task
-					if err := json.Unmarshal(msg.Value, &task); err != nil {
-						log.Printf("⚠️ Error parseando JSON de tarea: %v\n", err)
-						continue
-					}
+				var event models.TaskEvent
+				if err := json.Unmarshal(msg.Value, &event); err != nil {
+					log.Printf("⚠️ Error parseando JSON del evento de tarea: %v\n", err)
+					continue
+				}
 
-					// Crear la notificación a partir de la tarea
-					notification := models.Notification{
-						UserID:    task.UserID,
-						Message:   fmt.Sprintf("Nueva tarea creada: %s", task.Title),
-						CreatedAt: time.Now(),
-					}
+				// Guardar las notificaciones de los involucrados y enviarlas en tiempo real
+				if err := kafkaHandler.HandleTaskEvent(string(msg.Key), event); err != nil {
+					log.Printf("⚠️ Error manejando la notificación desde Kafka: %v\n", err)
+				}
 
-					// Guardar la notificación y enviarla a WebSocket
-					if err := kafkaHandler.HandleKafkaMessage(notification); err != nil {
-						log.Printf("⚠️ Error manejando la notificación desde Kafka: %v\n", err)
-					}
+			case "board-events":
+				var board models.Board
+				if err := json.Unmarshal(msg.Value, &board); err != nil {
+					log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
+					continue
+				}
 
-				case "update-task-status":
-					var task Task
-					if err := json.Unmarshal(msg.Value, &task); err != nil {
-						log.Printf("⚠️ Error parseando JSON de tarea: %v\n", err)
-						continue
-					}
+				if err := kafkaHandler.HandleBoardEvent(string(msg.Key), board); err != nil {
+					log.Printf("⚠️ Error actualizando board en notification-service: %v\n", err)
+				}
 
-					// Crear la notificación a partir de la tarea
-					notification := models.Notification{
-						UserID:    task.UserID,
-						Message:   fmt.Sprintf("Estado de la tarea actualizado: %s", task.Title),
-						CreatedAt: time.Now(),
-					}
+			case "user-events":
+				var user models.User
+				if err := json.Unmarshal(msg.Value, &user); err != nil {
+					log.Printf("⚠️ Error parseando JSON de usuario: %v\n", err)
+					continue
+				}
 
-					// Guardar la notificación y enviarla a WebSocket
-					if err := kafkaHandler.HandleKafkaMessage(notification); err != nil {
-						log.Printf("⚠️ Error manejando la notificación: %v\n", err)
-					}
+				if err := kafkaHandler.HandleUserEvent(string(msg.Key), user); err != nil {
+					log.Printf("⚠️ Error guardando usuario en notification-service: %v\n", err)
 				}
 			}
 
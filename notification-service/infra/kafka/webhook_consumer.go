@@ -0,0 +1,41 @@
+package kafka
+
+import (
+	"log"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/vadgun/gotrelloclone/notification-service/handlers"
+)
+
+// StartWebhookConsumer consume task-events y board-events con su propio grupo, así las entregas de webhooks
+// avanzan independientes de las notificaciones a usuarios.
+func StartWebhookConsumer(webhookHandler *handlers.WebhookHandler) {
+	c, err := kafka.NewConsumer(&kafka.ConfigMap{
+		"bootstrap.servers": "kafka:9092",
+		"group.id":          "notification-service-webhooks",
+		"auto.offset.reset": "earliest",
+	})
+	if err != nil {
+		log.Fatalf("❌ Error creando consumidor de webhooks: %v", err)
+	}
+	defer c.Close()
+
+	err = c.SubscribeTopics([]string{"task-events", "board-events"}, nil)
+	if err != nil {
+		log.Fatalf("❌ Error suscribiéndose a Kafka: %v", err)
+	}
+
+	log.Println("🪝 Escuchando eventos de tareas y tableros para los webhooks en Kafka...")
+
+	for {
+		msg, err := c.ReadMessage(-1)
+		if err != nil {
+			log.Printf("⚠️ Error al recibir mensaje: %v\n", err)
+			continue
+		}
+
+		if err := webhookHandler.HandleKafkaEvent(*msg.TopicPartition.Topic, string(msg.Key), msg.Value); err != nil {
+			log.Printf("⚠️ Error registrando entregas de webhooks | Topic: %s | Key: %s | Error: %v\n", *msg.TopicPartition.Topic, string(msg.Key), err)
+		}
+	}
+}
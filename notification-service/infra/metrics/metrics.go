@@ -16,11 +16,28 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	// Conexiones WebSocket y SSE abiertas en esta réplica
+	ConnectedClients = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "notification_connected_clients",
+			Help: "Número de conexiones en tiempo real (WebSocket y SSE) abiertas",
+		},
+	)
+
+	// Notificaciones que no se entregaron en vivo porque el cliente no las consumía a tiempo
+	DroppedMessagesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "notification_dropped_messages_total",
+			Help: "Número total de notificaciones descartadas por clientes lentos",
+		},
+		[]string{"policy"},
+	)
 )
 
 // InitMetrics registra las métricas en Prometheus
 func InitMetrics() {
-	prometheus.MustRegister(HttpRequestsTotal)
+	prometheus.MustRegister(HttpRequestsTotal, ConnectedClients, DroppedMessagesTotal)
 }
 
 // Handler para exponer métricas en /metrics
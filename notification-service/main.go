@@ -1,15 +1,23 @@
 package main
 
 import (
+	"context"
+	"time"
+	_ "time/tzdata" // La imagen de runtime no incluye zoneinfo y las preferencias usan la zona horaria del usuario
+
+	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/vadgun/gotrelloclone/notification-service/handlers"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/backplane"
 	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/email"
 	"github.com/vadgun/gotrelloclone/notification-service/infra/kafka"
 	"github.com/vadgun/gotrelloclone/notification-service/infra/logger"
 	"github.com/vadgun/gotrelloclone/notification-service/infra/metrics"
 	"github.com/vadgun/gotrelloclone/notification-service/repositories"
 	"github.com/vadgun/gotrelloclone/notification-service/routes"
 	"github.com/vadgun/gotrelloclone/notification-service/services"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -18,17 +26,79 @@ func main() {
 	metrics.InitMetrics()
 
 	notificationRepo := repositories.NewNotificationRepository()
-	webSocketService := services.NewWebSocketService()
-	go webSocketService.HandleMessages()
-	notificationService := services.NewNotificationService(notificationRepo, webSocketService)
-	notificationHandler := handlers.NewNotificationHandler(notificationService, webSocketService)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := notificationRepo.EnsureIndexes(ctx); err != nil {
+		logger.Log.Error("No se pudieron crear los índices de notificaciones", zap.Error(err))
+	}
+	cancel()
+	boardRepo := repositories.NewBoardRepository()
+	preferencesRepo := repositories.NewPreferencesRepository()
+	emailQueueRepo := repositories.NewEmailQueueRepository()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	if err := emailQueueRepo.EnsureIndexes(ctx); err != nil {
+		logger.Log.Error("No se pudieron crear los índices de correos pendientes", zap.Error(err))
+	}
+	cancel()
+
+	// Canal de correo, sin SMTP_HOST las notificaciones por email se ignoran
+	emailTemplates, err := email.LoadTemplates()
+	if err != nil {
+		logger.Log.Fatal("No se pudieron cargar las plantillas de correo", zap.Error(err))
+	}
+	var emailSender email.Sender
+	if config.SMTPHost != "" {
+		emailSender = email.NewSMTPSender(config.SMTPHost, config.SMTPPort, config.SMTPUser, config.SMTPPass, config.SMTPFrom)
+	}
+	emailService := services.NewEmailService(emailSender, emailTemplates, repositories.NewUserRepository(), emailQueueRepo)
+	go emailService.StartDigestScheduler(context.Background(), config.DigestInterval)
+
+	// Webhooks de tableros, cada réplica ejecuta el worker de entregas y reintentos
+	webhookRepo := repositories.NewWebhookRepository()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	if err := webhookRepo.EnsureIndexes(ctx); err != nil {
+		logger.Log.Error("No se pudieron crear los índices de webhooks", zap.Error(err))
+	}
+	cancel()
+	webhookService := services.NewWebhookService(webhookRepo, boardRepo)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	go webhookService.RunWorker(context.Background())
+
+	// Cada réplica entrega a sus conexiones locales lo que cualquier réplica publique en el backplane
+	hub := services.NewHub(config.SendBuffer, services.OverflowPolicy(config.OverflowPolicy))
+	go hub.Run()
+	var notificationBackplane backplane.Backplane = backplane.NewInMemoryBackplane()
+	if config.BackplaneDriver == "redis" {
+		config.InitRedis()
+		notificationBackplane = backplane.NewRedisBackplane(config.RedisClient, config.RedisChannel)
+	}
+	if err := notificationBackplane.Subscribe(context.Background(), hub.Publish); err != nil {
+		logger.Log.Fatal("No se pudo suscribir al backplane de notificaciones", zap.Error(err))
+	}
+
+	webSocketService := services.NewWebSocketService(hub)
+	sseService := services.NewSSEService(hub)
+	notificationService := services.NewNotificationService(notificationRepo, boardRepo, preferencesRepo, notificationBackplane, emailService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService, webSocketService, sseService)
 
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+
+	// Activando CORS para que el frontend consulte el inbox de notificaciones
+	router.Use(cors.New(cors.Config{
+		AllowOrigins:     config.AllowedOrigins,
+		AllowMethods:     []string{"POST", "OPTIONS", "GET", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:     []string{"Content-Type", "Authorization"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
+
 	routes.SetupNotificationRoutes(router, notificationHandler)
+	routes.SetupWebhookRoutes(router, webhookHandler)
 	router.GET("/metrics", gin.WrapH(metrics.MetricsHandler()))
 	logger.Log.Info("🚀 notification-service corriendo en http://notification-service:8080")
 	go kafka.StartConsumer(notificationHandler)
+	go kafka.StartWebhookConsumer(webhookHandler)
 	router.Run(":8080")
 	select {}
 }
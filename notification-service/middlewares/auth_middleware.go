@@ -0,0 +1,111 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/gorilla/websocket"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+)
+
+// AuthMiddleware protege rutas verificando el token JWT.
+func AuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// Obtener el header Authorization
+		authHeader := ctx.GetHeader("Authorization")
+		if authHeader == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token requerido"})
+			ctx.Abort()
+			return
+		}
+
+		// Extraer token del header (Formato: "Bearer <token>")
+		tokenString := strings.Split(authHeader, " ")
+		if len(tokenString) != 2 || tokenString[0] != "Bearer" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Formato de token inválido"})
+			ctx.Abort()
+			return
+		}
+
+		// Validar el token
+		claims := &config.JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenString[1], claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(config.JWTSecret), nil
+		})
+
+		if err != nil || !token.Valid {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+			ctx.Abort()
+			return
+		}
+
+		// Guardar los claims en el contexto
+		ctx.Set("userID", claims.UserID)
+		ctx.Next()
+	}
+}
+
+// StreamAuthMiddleware valida el token JWT de las conexiones en tiempo real (upgrade a WebSocket y SSE).
+// Los navegadores no permiten enviar headers con WebSocket ni EventSource, por lo que el token se busca en
+// el header Authorization, en el header Sec-WebSocket-Protocol o en el query param `token`.
+func StreamAuthMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenString, ok := streamToken(ctx)
+		if !ok {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Formato de token inválido"})
+			ctx.Abort()
+			return
+		}
+
+		if tokenString == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token requerido"})
+			ctx.Abort()
+			return
+		}
+
+		// Validar el token
+		claims := &config.JWTClaims{}
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			return []byte(config.JWTSecret), nil
+		})
+
+		if err != nil || !token.Valid || claims.UserID == "" {
+			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido"})
+			ctx.Abort()
+			return
+		}
+
+		// Guardar los claims en el contexto, la expiración se usa para cerrar el socket cuando el token caduque
+		ctx.Set("userID", claims.UserID)
+		if claims.ExpiresAt != nil {
+			ctx.Set("tokenExpiresAt", claims.ExpiresAt.Time)
+		}
+		ctx.Next()
+	}
+}
+
+// streamToken obtiene el token en orden de prioridad: Authorization, Sec-WebSocket-Protocol y query param.
+// Devuelve false si el token viene en un formato inválido.
+func streamToken(ctx *gin.Context) (string, bool) {
+	// Formato: "Bearer <token>"
+	if authHeader := ctx.GetHeader("Authorization"); authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			return "", false
+		}
+		return parts[1], true
+	}
+
+	// Formato: "Sec-WebSocket-Protocol: bearer, <token>"
+	if protocols := websocket.Subprotocols(ctx.Request); len(protocols) > 0 {
+		if len(protocols) != 2 || protocols[0] != services.BearerSubprotocol {
+			return "", false
+		}
+		return protocols[1], true
+	}
+
+	return ctx.Query("token"), true
+}
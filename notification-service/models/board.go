@@ -0,0 +1,24 @@
+package models
+
+const (
+	// RoleOwner y RoleAdmin son los roles de board-service que pueden administrar el tablero, p. ej. sus webhooks
+	RoleOwner = "owner"
+	RoleAdmin = "admin"
+)
+
+// Board es la proyección local de los tableros de board-service, se usa para saber a quién notificar
+type Board struct {
+	ID        string   `bson:"_id" json:"id"`
+	Name      string   `bson:"name" json:"name"`
+	OwnerID   string   `bson:"owner_id" json:"owner_id"`
+	MemberIDs []string `bson:"member_ids" json:"member_ids"`
+	// Members conserva el rol de cada miembro, MemberIDs se mantiene para buscar destinatarios
+	Members []BoardMember `bson:"members" json:"members"`
+	// MembersVersion viene de board-service, permite descartar eventos de miembros atrasados
+	MembersVersion int64 `bson:"members_version" json:"members_version"`
+}
+
+type BoardMember struct {
+	UserID string `bson:"user_id" json:"user_id"`
+	Role   string `bson:"role" json:"role"`
+}
@@ -10,5 +10,9 @@ type Notification struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID    string             `bson:"user_id" json:"user_id" binding:"required"`
 	Message   string             `bson:"message" json:"message" binding:"required"`
+	Type      string             `bson:"type,omitempty" json:"type,omitempty"` // key del evento que originó la notificación
+	TaskID    string             `bson:"task_id,omitempty" json:"task_id,omitempty"`
+	BoardID   string             `bson:"board_id,omitempty" json:"board_id,omitempty"`
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	ReadAt    *time.Time         `bson:"read_at" json:"read_at"` // nil mientras la notificación no ha sido leída
 }
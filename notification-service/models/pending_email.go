@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingEmail es una notificación que espera el siguiente resumen por email del usuario
+type PendingEmail struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID       string             `bson:"user_id" json:"user_id"`
+	Notification Notification       `bson:"notification" json:"notification"`
+	ClaimID      string             `bson:"claim_id,omitempty" json:"-"`   // Réplica que está enviando el resumen
+	ClaimedAt    *time.Time         `bson:"claimed_at,omitempty" json:"-"` // Permite recuperar resúmenes de réplicas caídas
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// NotificationPreferences son las reglas con las que cada usuario decide qué notificaciones recibir y por dónde
+type NotificationPreferences struct {
+	UserID      string          `bson:"_id" json:"user_id"`
+	EventTypes  map[string]bool `bson:"event_types" json:"event_types"`   // key del evento -> recibirlo, las keys ausentes se reciben
+	Channels    Channels        `bson:"channels" json:"channels"`         // Canales por los que se entregan las notificaciones
+	EmailDigest bool            `bson:"email_digest" json:"email_digest"` // Recibir los correos agrupados en un resumen periódico
+	MutedBoards []string        `bson:"muted_boards" json:"muted_boards"` // Tableros de los que no se reciben notificaciones
+	QuietHours  *QuietHours     `bson:"quiet_hours,omitempty" json:"quiet_hours"`
+	UpdatedAt   time.Time       `bson:"updated_at" json:"updated_at"`
+}
+
+type Channels struct {
+	InApp bool `bson:"in_app" json:"in_app"` // Inbox y entrega en tiempo real por WebSocket/SSE
+	Email bool `bson:"email" json:"email"`
+}
+
+// QuietHours es el rango diario (HH:MM) en la zona horaria del usuario en el que no se envían avisos en tiempo real,
+// puede cruzar la medianoche, por ejemplo de 22:00 a 07:00
+type QuietHours struct {
+	Start    string `bson:"start" json:"start" binding:"required"`
+	End      string `bson:"end" json:"end" binding:"required"`
+	Timezone string `bson:"timezone" json:"timezone" binding:"required"`
+}
+
+// DefaultPreferences son las preferencias de un usuario que aún no las ha configurado: todo por in-app
+func DefaultPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:      userID,
+		EventTypes:  map[string]bool{},
+		Channels:    Channels{InApp: true},
+		MutedBoards: []string{},
+	}
+}
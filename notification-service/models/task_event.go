@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// Keys con las que task-service publica los eventos de tareas en task-events
+const (
+	TaskCreatedEvent       = "new-task"
+	TaskUpdatedEvent       = "updated-task"
+	TaskStatusUpdatedEvent = "update-task-status"
+	TaskMovedEvent         = "moved-task"
+	TaskAssignedEvent      = "assigned-task"
+	TaskDeletedEvent       = "deleted-task"
+	TaskRestoredEvent      = "restored-task"
+	TaskArchivedEvent      = "archived-task"
+	TaskUnarchivedEvent    = "unarchived-task"
+	TaskCommentedEvent     = "new-comment"
+	TaskMentionedEvent     = "mentioned-in-comment"
+	TaskDueSoonEvent       = "task-due-soon"
+	TaskOverdueEvent       = "task-overdue"
+)
+
+// TaskEventTypes son los tipos de evento que el usuario puede activar o desactivar en sus preferencias
+var TaskEventTypes = []string{
+	TaskCreatedEvent,
+	TaskUpdatedEvent,
+	TaskStatusUpdatedEvent,
+	TaskMovedEvent,
+	TaskAssignedEvent,
+	TaskDeletedEvent,
+	TaskRestoredEvent,
+	TaskArchivedEvent,
+	TaskUnarchivedEvent,
+	TaskCommentedEvent,
+	TaskMentionedEvent,
+	TaskDueSoonEvent,
+	TaskOverdueEvent,
+}
+
+// Task representa el esquema de la tarea recibida mediante el evento de Kafka
+type Task struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	BoardID     string     `json:"board_id"`
+	ListID      string     `json:"list_id"`
+	UserID      string     `json:"user_id"`
+	AssigneeID  string     `json:"assignee_id"`
+	Status      string     `json:"status"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+}
+
+// TaskEvent es el payload de todos los eventos de task-events: la tarea después del cambio,
+// quién lo hizo y los valores anteriores cuando aplican
+type TaskEvent struct {
+	Task       Task   `json:"task"`
+	ActorID    string `json:"actor_id"`
+	OldStatus  string `json:"old_status,omitempty"`
+	OldBoardID string `json:"old_board_id,omitempty"`
+	OldListID  string `json:"old_list_id,omitempty"`
+	// Comment y MentionedIDs solo vienen en los eventos de comentarios
+	Comment      *Comment `json:"comment,omitempty"`
+	MentionedIDs []string `json:"mentioned_ids,omitempty"`
+	// EventID solo viene en los recordatorios, task-service lo repite si publica el mismo recordatorio otra vez
+	EventID string `json:"event_id,omitempty"`
+}
+
+// Comment es el comentario que originó un evento de comentarios
+type Comment struct {
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id,omitempty"`
+	AuthorID string `json:"author_id"`
+	Body     string `json:"body"`
+}
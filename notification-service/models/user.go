@@ -0,0 +1,8 @@
+package models
+
+// User es la proyección local de los usuarios de user-service, se usa para el canal de correo
+type User struct {
+	ID    string `bson:"_id" json:"id"`
+	Name  string `bson:"name" json:"name"`
+	Email string `bson:"email" json:"email"`
+}
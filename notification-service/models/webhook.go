@@ -0,0 +1,67 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Keys con las que board-service publica los eventos de tableros en board-events
+const (
+	BoardCreatedEvent        = "new-board"
+	BoardTrashedEvent        = "trashed-board"
+	BoardRestoredEvent       = "restored-board"
+	BoardArchivedEvent       = "archived-board"
+	BoardUnarchivedEvent     = "unarchived-board"
+	BoardDeletedEvent        = "drop-board" // El tablero se purgó de la papelera
+	BoardMembersUpdatedEvent = "updated-members"
+)
+
+// WebhookEventTypes son los eventos a los que se puede suscribir un webhook de tablero
+var WebhookEventTypes = append([]string{BoardTrashedEvent, BoardRestoredEvent, BoardArchivedEvent, BoardUnarchivedEvent, BoardDeletedEvent, BoardMembersUpdatedEvent}, TaskEventTypes...)
+
+// Estados de una entrega de webhook
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook es un registro de un tablero para recibir sus eventos por HTTP en una URL externa
+type Webhook struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	BoardID    string             `bson:"board_id" json:"board_id"`
+	URL        string             `bson:"url" json:"url" binding:"required,url"`
+	EventTypes []string           `bson:"event_types" json:"event_types" binding:"required,min=1"`
+	Secret     string             `bson:"secret" json:"secret,omitempty"` // Solo se devuelve al crear el webhook
+	CreatedBy  string             `bson:"created_by" json:"created_by"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// WebhookPayload es el cuerpo JSON que recibe el destino del webhook
+type WebhookPayload struct {
+	DeliveryID string          `json:"delivery_id"`
+	Event      string          `json:"event"`
+	BoardID    string          `json:"board_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Data       json.RawMessage `json:"data"` // Evento tal como se publicó en Kafka
+}
+
+// WebhookDelivery es el registro de una entrega con sus reintentos
+type WebhookDelivery struct {
+	ID             primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	WebhookID      primitive.ObjectID  `bson:"webhook_id" json:"webhook_id"`
+	BoardID        string              `bson:"board_id" json:"board_id"`
+	Event          string              `bson:"event" json:"event"`
+	Payload        string              `bson:"payload" json:"payload"`
+	Status         string              `bson:"status" json:"status"`
+	Attempts       int                 `bson:"attempts" json:"attempts"`
+	ResponseStatus int                 `bson:"response_status,omitempty" json:"response_status,omitempty"`
+	Error          string              `bson:"error,omitempty" json:"error,omitempty"`
+	NextAttemptAt  time.Time           `bson:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt    *time.Time          `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	RedeliveryOf   *primitive.ObjectID `bson:"redelivery_of,omitempty" json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time           `bson:"updated_at" json:"updated_at"`
+}
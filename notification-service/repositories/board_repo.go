@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BoardRepository guarda la proyección de tableros alimentada por board-events
+type BoardRepository struct {
+	collection *mongo.Collection
+}
+
+func NewBoardRepository() *BoardRepository {
+	return &BoardRepository{
+		collection: config.DB.Collection("boards"),
+	}
+}
+
+// SaveBoard inserta o reemplaza el tablero, los eventos pueden llegar repetidos o desordenados:
+// solo se reemplaza si la versión de miembros recibida no es más vieja que la guardada. La misma versión
+// se vuelve a escribir para que la sincronización de board-service complete los roles de proyecciones anteriores.
+func (r *BoardRepository) SaveBoard(ctx context.Context, board *models.Board) error {
+	filter := bson.M{"_id": board.ID, "members_version": bson.M{"$not": bson.M{"$gt": board.MembersVersion}}}
+	_, err := r.collection.ReplaceOne(ctx, filter, board, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// El tablero ya tiene una versión más reciente
+		return nil
+	}
+	return err
+}
+
+// GetBoardByID obtiene el tablero, devuelve mongo.ErrNoDocuments si aún no se recibió su evento
+func (r *BoardRepository) GetBoardByID(ctx context.Context, boardID string) (*models.Board, error) {
+	var board models.Board
+	if err := r.collection.FindOne(ctx, bson.M{"_id": boardID}).Decode(&board); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func (r *BoardRepository) DeleteBoard(ctx context.Context, boardID string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": boardID})
+	return err
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailQueueRepository guarda las notificaciones pendientes de enviarse en el resumen por email.
+// Varias réplicas pueden enviar resúmenes a la vez, cada una reclama los pendientes con un claim_id.
+type EmailQueueRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailQueueRepository() *EmailQueueRepository {
+	return &EmailQueueRepository{
+		collection: config.DB.Collection("pending_emails"),
+	}
+}
+
+func (r *EmailQueueRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "claim_id", Value: 1}}},
+	})
+	return err
+}
+
+func (r *EmailQueueRepository) Enqueue(ctx context.Context, pending *models.PendingEmail) error {
+	_, err := r.collection.InsertOne(ctx, pending)
+	return err
+}
+
+// unclaimed son los pendientes sin reclamar o cuyo reclamo es anterior a staleBefore
+func unclaimed(staleBefore time.Time) bson.M {
+	return bson.M{"$or": bson.A{
+		bson.M{"claim_id": bson.M{"$exists": false}},
+		bson.M{"claimed_at": bson.M{"$lt": staleBefore}},
+	}}
+}
+
+// GetPendingUsers devuelve los usuarios con notificaciones esperando resumen
+func (r *EmailQueueRepository) GetPendingUsers(ctx context.Context, staleBefore time.Time) ([]string, error) {
+	values, err := r.collection.Distinct(ctx, "user_id", unclaimed(staleBefore))
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(values))
+	for _, value := range values {
+		if userID, ok := value.(string); ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+// Claim marca con claimID los pendientes del usuario que nadie más está enviando
+func (r *EmailQueueRepository) Claim(ctx context.Context, userID, claimID string, staleBefore time.Time) (int64, error) {
+	filter := unclaimed(staleBefore)
+	filter["user_id"] = userID
+
+	result, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"claim_id": claimID, "claimed_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetClaimed obtiene en orden cronológico los pendientes reclamados con claimID
+func (r *EmailQueueRepository) GetClaimed(ctx context.Context, claimID string) ([]models.PendingEmail, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"claim_id": claimID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	pending := []models.PendingEmail{}
+	if err := cursor.All(ctx, &pending); err != nil {
+		return nil, err
+	}
+	return pending, nil
+}
+
+// DeleteClaimed elimina los pendientes que ya se enviaron
+func (r *EmailQueueRepository) DeleteClaimed(ctx context.Context, claimID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"claim_id": claimID})
+	return err
+}
+
+// ReleaseClaim libera los pendientes para reintentarlos en el siguiente resumen
+func (r *EmailQueueRepository) ReleaseClaim(ctx context.Context, claimID string) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"claim_id": claimID}, bson.M{"$unset": bson.M{"claim_id": "", "claimed_at": ""}})
+	return err
+}
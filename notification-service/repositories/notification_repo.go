@@ -2,23 +2,186 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
 	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type NotificationRepository struct {
 	collection *mongo.Collection
+	// events guarda los event_id ya procesados para descartar los eventos que llegan repetidos
+	events *mongo.Collection
+}
+
+// processedEventTTL es el tiempo que se recuerda un event_id procesado
+const processedEventTTL = 7 * 24 * time.Hour
+
+// NotificationCursor marca la última notificación entregada en una página del inbox
+type NotificationCursor struct {
+	CreatedAt time.Time
+	ID        primitive.ObjectID
 }
 
 func NewNotificationRepository() *NotificationRepository {
 	return &NotificationRepository{
 		collection: config.DB.Collection("notifications"),
+		events:     config.DB.Collection("processed_events"),
 	}
 }
 
-func (r *NotificationRepository) SaveNotification(ctx context.Context, notification *models.Notification) error {
-	_, err := r.collection.InsertOne(ctx, notification)
+// EnsureIndexes crea los índices usados por el inbox: listado por fecha y conteo de no leídas,
+// y el que expira los event_id procesados
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read_at", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.events.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(int32(processedEventTTL.Seconds())),
+	})
 	return err
 }
+
+// ClaimEvent registra el event_id como procesado, devuelve false si otra entrega del mismo evento ya lo registró
+func (r *NotificationRepository) ClaimEvent(ctx context.Context, eventID string) (bool, error) {
+	_, err := r.events.InsertOne(ctx, bson.M{"_id": eventID, "created_at": time.Now()})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ReleaseEvent olvida el event_id para que el evento se pueda procesar otra vez si vuelve a llegar
+func (r *NotificationRepository) ReleaseEvent(ctx context.Context, eventID string) error {
+	_, err := r.events.DeleteOne(ctx, bson.M{"_id": eventID})
+	return err
+}
+
+func (r *NotificationRepository) SaveNotification(ctx context.Context, notification *models.Notification) error {
+	result, err := r.collection.InsertOne(ctx, notification)
+	if err != nil {
+		return err
+	}
+
+	// Conservar el ID generado para que viaje junto con la notificación por WebSocket
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		notification.ID = id
+	}
+	return nil
+}
+
+// GetNotificationsByUser obtiene las notificaciones del usuario de la más reciente a la más antigua,
+// comenzando después del cursor si se indica uno.
+func (r *NotificationRepository) GetNotificationsByUser(ctx context.Context, userID string, cursor *NotificationCursor, limit int64, unreadOnly bool) ([]models.Notification, error) {
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read_at"] = nil
+	}
+	if cursor != nil {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$lt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$lt": cursor.ID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	cursorDB, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursorDB.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursorDB.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// GetNotificationByID obtiene una notificación del usuario
+func (r *NotificationRepository) GetNotificationByID(ctx context.Context, userID string, id primitive.ObjectID) (*models.Notification, error) {
+	var notification models.Notification
+	err := r.collection.FindOne(ctx, bson.M{"_id": id, "user_id": userID}).Decode(&notification)
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// GetNotificationsAfter obtiene en orden cronológico las notificaciones del usuario posteriores al cursor.
+// Si el cursor no tiene ID se devuelven las creadas después de su fecha.
+func (r *NotificationRepository) GetNotificationsAfter(ctx context.Context, userID string, cursor *NotificationCursor, limit int64) ([]models.Notification, error) {
+	filter := bson.M{"user_id": userID}
+	if cursor.ID.IsZero() {
+		filter["createdAt"] = bson.M{"$gt": cursor.CreatedAt}
+	} else {
+		filter["$or"] = bson.A{
+			bson.M{"createdAt": bson.M{"$gt": cursor.CreatedAt}},
+			bson.M{"createdAt": cursor.CreatedAt, "_id": bson.M{"$gt": cursor.ID}},
+		}
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit)
+
+	cursorDB, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursorDB.Close(ctx)
+
+	notifications := []models.Notification{}
+	if err := cursorDB.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// CountUnread cuenta las notificaciones sin leer del usuario
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+// MarkAsRead marca como leídas las notificaciones indicadas que pertenezcan al usuario
+func (r *NotificationRepository) MarkAsRead(ctx context.Context, userID string, ids []primitive.ObjectID) (int64, error) {
+	filter := bson.M{"user_id": userID, "_id": bson.M{"$in": ids}, "read_at": nil}
+	return r.markAsRead(ctx, filter)
+}
+
+// MarkAllAsRead marca como leídas todas las notificaciones del usuario
+func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID string) (int64, error) {
+	return r.markAsRead(ctx, bson.M{"user_id": userID, "read_at": nil})
+}
+
+func (r *NotificationRepository) markAsRead(ctx context.Context, filter bson.M) (int64, error) {
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
+
+// DeleteNotifications elimina las notificaciones indicadas que pertenezcan al usuario
+func (r *NotificationRepository) DeleteNotifications(ctx context.Context, userID string, ids []primitive.ObjectID) (int64, error) {
+	mongoResult, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID, "_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.DeletedCount, nil
+}
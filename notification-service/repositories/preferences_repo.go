@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PreferencesRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPreferencesRepository() *PreferencesRepository {
+	return &PreferencesRepository{
+		collection: config.DB.Collection("preferences"),
+	}
+}
+
+// GetPreferences obtiene las preferencias del usuario, devuelve mongo.ErrNoDocuments si nunca las ha guardado
+func (r *PreferencesRepository) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	var preferences models.NotificationPreferences
+	if err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&preferences); err != nil {
+		return nil, err
+	}
+	return &preferences, nil
+}
+
+// SavePreferences reemplaza las preferencias del usuario
+func (r *PreferencesRepository) SavePreferences(ctx context.Context, preferences *models.NotificationPreferences) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": preferences.UserID}, preferences, options.Replace().SetUpsert(true))
+	return err
+}
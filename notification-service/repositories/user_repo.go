@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserRepository guarda la proyección de usuarios alimentada por user-events
+type UserRepository struct {
+	collection *mongo.Collection
+}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{
+		collection: config.DB.Collection("users"),
+	}
+}
+
+// SaveUser inserta o reemplaza el usuario, los eventos pueden llegar repetidos
+func (r *UserRepository) SaveUser(ctx context.Context, user *models.User) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": user.ID}, user, options.Replace().SetUpsert(true))
+	return err
+}
+
+// GetUserByID obtiene el usuario, devuelve mongo.ErrNoDocuments si aún no se recibió su evento
+func (r *UserRepository) GetUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	if err := r.collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WebhookRepository struct {
+	webhooks   *mongo.Collection
+	deliveries *mongo.Collection
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{
+		webhooks:   config.DB.Collection("webhooks"),
+		deliveries: config.DB.Collection("webhook_deliveries"),
+	}
+}
+
+// EnsureIndexes crea los índices para buscar webhooks por tablero, el log de entregas y las entregas pendientes
+func (r *WebhookRepository) EnsureIndexes(ctx context.Context) error {
+	if _, err := r.webhooks.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "board_id", Value: 1}}}); err != nil {
+		return err
+	}
+	_, err := r.deliveries.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+	})
+	return err
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	result, err := r.webhooks.InsertOne(ctx, webhook)
+	if err != nil {
+		return err
+	}
+	webhook.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *WebhookRepository) GetWebhookByID(ctx context.Context, id primitive.ObjectID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.webhooks.FindOne(ctx, bson.M{"_id": id}).Decode(&webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) GetWebhooksByBoard(ctx context.Context, boardID string) ([]models.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{"board_id": boardID})
+}
+
+// GetSubscribedWebhooks obtiene los webhooks de los tableros que están suscritos al evento
+func (r *WebhookRepository) GetSubscribedWebhooks(ctx context.Context, boardIDs []string, event string) ([]models.Webhook, error) {
+	return r.findWebhooks(ctx, bson.M{"board_id": bson.M{"$in": boardIDs}, "event_types": event})
+}
+
+func (r *WebhookRepository) findWebhooks(ctx context.Context, filter bson.M) ([]models.Webhook, error) {
+	cursor, err := r.webhooks.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []models.Webhook{}
+	if err := cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, boardID string, id primitive.ObjectID) (int64, error) {
+	result, err := r.webhooks.DeleteOne(ctx, bson.M{"_id": id, "board_id": boardID})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	result, err := r.deliveries.InsertOne(ctx, delivery)
+	if err != nil {
+		return err
+	}
+	delivery.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, webhookID, id primitive.ObjectID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.deliveries.FindOne(ctx, bson.M{"_id": id, "webhook_id": webhookID}).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// GetDeliveries obtiene las entregas más recientes del webhook
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID primitive.ObjectID, limit int64) ([]models.WebhookDelivery, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := r.deliveries.Find(ctx, bson.M{"webhook_id": webhookID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDueDelivery toma la siguiente entrega pendiente cuyo intento ya toca y la aparta durante lease
+// para que ninguna otra réplica la intente al mismo tiempo. Devuelve mongo.ErrNoDocuments si no hay ninguna.
+func (r *WebhookRepository) ClaimDueDelivery(ctx context.Context, lease time.Duration) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	filter := bson.M{"status": models.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease)}}
+	findOptions := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}})
+
+	var delivery models.WebhookDelivery
+	if err := r.deliveries.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&delivery); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// UpdateDelivery guarda el resultado de un intento
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	_, err := r.deliveries.ReplaceOne(ctx, bson.M{"_id": delivery.ID}, delivery)
+	return err
+}
@@ -3,9 +3,25 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/vadgun/gotrelloclone/notification-service/handlers"
+	"github.com/vadgun/gotrelloclone/notification-service/middlewares"
 )
 
 func SetupNotificationRoutes(router *gin.Engine, notificationHandler *handlers.NotificationHandler) {
 	router.POST("/notify", notificationHandler.SendNotification)
-	router.GET("/ws", notificationHandler.HandleConnections)
+	router.GET("/ws", middlewares.StreamAuthMiddleware(), notificationHandler.HandleConnections)
+	router.GET("/notifications/stream", middlewares.StreamAuthMiddleware(), notificationHandler.StreamNotifications) // Alternativa SSE a /ws
+
+	// Inbox del usuario autenticado
+	notificationGroup := router.Group("/notifications")
+	notificationGroup.Use(middlewares.AuthMiddleware())
+
+	notificationGroup.GET("", notificationHandler.GetNotifications)                      // Listar notificaciones con paginación por cursor
+	notificationGroup.GET("/unread-count", notificationHandler.GetUnreadCount)           // Conteo de no leídas para el badge
+	notificationGroup.GET("/preferences", notificationHandler.GetPreferences)            // Preferencias y reglas de silencio
+	notificationGroup.PUT("/preferences", notificationHandler.UpdatePreferences)         // Reemplazar las preferencias
+	notificationGroup.PUT("/read", notificationHandler.MarkManyAsRead)                   // Marcar varias como leídas
+	notificationGroup.PUT("/read-all", notificationHandler.MarkAllAsRead)                // Marcar todas como leídas
+	notificationGroup.PUT("/:notificationID/read", notificationHandler.MarkAsRead)       // Marcar una como leída
+	notificationGroup.DELETE("", notificationHandler.DeleteNotifications)                // Eliminar varias
+	notificationGroup.DELETE("/:notificationID", notificationHandler.DeleteNotification) // Eliminar una
 }
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/notification-service/handlers"
+	"github.com/vadgun/gotrelloclone/notification-service/middlewares"
+)
+
+func SetupWebhookRoutes(router *gin.Engine, webhookHandler *handlers.WebhookHandler) {
+	// Webhooks de un tablero, solo para su owner y miembros
+	webhookGroup := router.Group("/boards/:boardID/webhooks")
+	webhookGroup.Use(middlewares.AuthMiddleware())
+
+	webhookGroup.POST("", webhookHandler.CreateWebhook)                                         // Registrar webhook
+	webhookGroup.GET("", webhookHandler.GetWebhooks)                                            // Listar webhooks del tablero
+	webhookGroup.DELETE("/:webhookID", webhookHandler.DeleteWebhook)                            // Eliminar webhook
+	webhookGroup.GET("/:webhookID/deliveries", webhookHandler.GetDeliveries)                    // Log de entregas recientes
+	webhookGroup.POST("/:webhookID/deliveries/:deliveryID/redeliver", webhookHandler.Redeliver) // Reenviar una entrega
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/email"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// staleClaim es el tiempo tras el cual un resumen reclamado por otra réplica se considera abandonado
+const staleClaim = 10 * time.Minute
+
+// emailSubjects son los asuntos por tipo de evento, el resto usa defaultEmailSubject
+var emailSubjects = map[string]string{
+	models.TaskCreatedEvent:       "Nueva tarea creada",
+	models.TaskUpdatedEvent:       "Tarea actualizada",
+	models.TaskStatusUpdatedEvent: "Cambió el estado de una tarea",
+	models.TaskMovedEvent:         "Tarea movida de tablero",
+	models.TaskAssignedEvent:      "Tarea asignada",
+	models.TaskDeletedEvent:       "Tarea eliminada",
+	models.TaskRestoredEvent:      "Tarea restaurada",
+	models.TaskArchivedEvent:      "Tarea archivada",
+	models.TaskUnarchivedEvent:    "Tarea desarchivada",
+	models.TaskCommentedEvent:     "Nuevo comentario en una tarea",
+	models.TaskMentionedEvent:     "Te mencionaron en un comentario",
+	models.TaskDueSoonEvent:       "Una tarea vence pronto",
+	models.TaskOverdueEvent:       "Una tarea está vencida",
+}
+
+const (
+	defaultEmailSubject = "Nueva notificación"
+	digestEmailSubject  = "Tu resumen de notificaciones"
+)
+
+// EmailService es el canal de correo: envía las notificaciones al momento o las acumula para el resumen
+type EmailService struct {
+	sender    email.Sender
+	templates *email.Templates
+	userRepo  *repositories.UserRepository
+	queueRepo *repositories.EmailQueueRepository
+}
+
+// NewEmailService crea el canal de correo, si sender es nil las notificaciones por email se ignoran
+func NewEmailService(sender email.Sender, templates *email.Templates, userRepo *repositories.UserRepository, queueRepo *repositories.EmailQueueRepository) *EmailService {
+	return &EmailService{sender: sender, templates: templates, userRepo: userRepo, queueRepo: queueRepo}
+}
+
+// SaveUser actualiza la proyección local con el usuario recibido de user-events
+func (s *EmailService) SaveUser(ctx context.Context, user *models.User) error {
+	return s.userRepo.SaveUser(ctx, user)
+}
+
+// Deliver envía la notificación por correo o la deja pendiente para el siguiente resumen si digest es true
+func (s *EmailService) Deliver(ctx context.Context, notification models.Notification, digest bool) error {
+	if s.sender == nil {
+		return nil
+	}
+
+	if digest {
+		return s.queueRepo.Enqueue(ctx, &models.PendingEmail{
+			UserID:       notification.UserID,
+			Notification: notification,
+			CreatedAt:    time.Now(),
+		})
+	}
+
+	user, err := s.recipient(ctx, notification.UserID)
+	if err != nil || user == nil {
+		return err
+	}
+
+	subject, ok := emailSubjects[notification.Type]
+	if !ok {
+		subject = defaultEmailSubject
+	}
+
+	return s.send(user, subject, notification.Type, email.TemplateData{UserName: user.Name, Notification: notification})
+}
+
+// SendDigests envía a cada usuario con pendientes un único correo con todas sus notificaciones acumuladas
+func (s *EmailService) SendDigests(ctx context.Context) error {
+	if s.sender == nil {
+		return nil
+	}
+
+	userIDs, err := s.queueRepo.GetPendingUsers(ctx, time.Now().Add(-staleClaim))
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if err := s.sendDigest(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("error enviando resumen a %s: %v", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *EmailService) sendDigest(ctx context.Context, userID string) error {
+	// Solo la réplica que reclama los pendientes los envía
+	claimID := primitive.NewObjectID().Hex()
+	claimed, err := s.queueRepo.Claim(ctx, userID, claimID, time.Now().Add(-staleClaim))
+	if err != nil || claimed == 0 {
+		return err
+	}
+
+	pending, err := s.queueRepo.GetClaimed(ctx, claimID)
+	if err != nil {
+		s.queueRepo.ReleaseClaim(ctx, claimID)
+		return err
+	}
+
+	user, err := s.recipient(ctx, userID)
+	if err != nil {
+		s.queueRepo.ReleaseClaim(ctx, claimID)
+		return err
+	}
+	if user == nil {
+		// Sin email no hay a quién enviar el resumen
+		return s.queueRepo.DeleteClaimed(ctx, claimID)
+	}
+
+	notifications := make([]models.Notification, 0, len(pending))
+	for _, item := range pending {
+		notifications = append(notifications, item.Notification)
+	}
+
+	if err := s.send(user, digestEmailSubject, email.DigestTemplate, email.TemplateData{UserName: user.Name, Notifications: notifications}); err != nil {
+		s.queueRepo.ReleaseClaim(ctx, claimID)
+		return err
+	}
+
+	log.Printf("📧 Resumen enviado | UserID: %s | Notificaciones: %d\n", userID, len(notifications))
+	return s.queueRepo.DeleteClaimed(ctx, claimID)
+}
+
+// StartDigestScheduler envía los resúmenes cada interval hasta que ctx se cancela
+func (s *EmailService) StartDigestScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.SendDigests(ctx); err != nil {
+				log.Printf("⚠️ Error enviando resúmenes por email: %v\n", err)
+			}
+		}
+	}
+}
+
+// recipient obtiene el usuario destinatario, devuelve nil si aún no se conoce su email
+func (s *EmailService) recipient(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && user.Email == "") {
+		log.Printf("⚠️ Usuario sin email, no se envía correo | UserID: %s\n", userID)
+		return nil, nil
+	}
+	return user, err
+}
+
+func (s *EmailService) send(user *models.User, subject, templateName string, data email.TemplateData) error {
+	text, html, err := s.templates.Render(templateName, data)
+	if err != nil {
+		return err
+	}
+	return s.sender.Send(email.Message{To: user.Email, Subject: subject, Text: text, HTML: html})
+}
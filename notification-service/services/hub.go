@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/metrics"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OverflowPolicy define qué hacer cuando la cola de una conexión está llena
+type OverflowPolicy string
+
+const (
+	// DropOldest descarta la notificación más antigua de la cola para hacer espacio a la nueva
+	DropOldest OverflowPolicy = "drop-oldest"
+	// Disconnect cierra la conexión lenta, el cliente recupera lo perdido al reconectar con replay
+	Disconnect OverflowPolicy = "disconnect"
+)
+
+// ErrSlowClient indica que la conexión se cerró porque no consumía sus notificaciones a tiempo
+var ErrSlowClient = errors.New("cliente lento desconectado")
+
+// ReplayFunc obtiene las notificaciones que el cliente no recibió mientras estuvo desconectado
+type ReplayFunc func() ([]models.Notification, error)
+
+// Subscription es una conexión (pestaña) de un usuario, ya sea WebSocket o SSE.
+// Tiene su propia cola acotada para que una conexión lenta no frene la entrega a las demás.
+type Subscription struct {
+	userID        string
+	notifications chan models.Notification
+	evicted       chan struct{}
+}
+
+// Hub es el núcleo de fan-out compartido por WebSocket y SSE: reparte cada notificación
+// únicamente a las suscripciones del usuario al que pertenece.
+type Hub struct {
+	// Suscripciones agrupadas por userID, un usuario puede tener varias pestañas abiertas
+	subscriptions map[string]map[*Subscription]bool
+	broadcast     chan models.Notification
+	bufferSize    int
+	policy        OverflowPolicy
+	mu            sync.Mutex
+}
+
+func NewHub(bufferSize int, policy OverflowPolicy) *Hub {
+	return &Hub{
+		subscriptions: make(map[string]map[*Subscription]bool),
+		broadcast:     make(chan models.Notification),
+		bufferSize:    bufferSize,
+		policy:        policy,
+	}
+}
+
+// Run reparte las notificaciones publicadas a las colas de las suscripciones de su usuario, nunca se bloquea
+// esperando a un cliente
+func (h *Hub) Run() {
+	for notification := range h.broadcast {
+		h.mu.Lock()
+		for sub := range h.subscriptions[notification.UserID] {
+			h.enqueue(sub, notification)
+		}
+		h.mu.Unlock()
+	}
+}
+
+// enqueue debe llamarse con h.mu bloqueado, Run es el único que escribe en las colas
+func (h *Hub) enqueue(sub *Subscription, notification models.Notification) {
+	select {
+	case sub.notifications <- notification:
+		return
+	default:
+	}
+
+	// La notificación descartada ya está guardada, el cliente la recupera al reconectar
+	metrics.DroppedMessagesTotal.WithLabelValues(string(h.policy)).Inc()
+
+	if h.policy == Disconnect {
+		log.Printf("⚠️ Cola llena, desconectando cliente lento | UserID: %s\n", sub.userID)
+		h.remove(sub)
+		close(sub.evicted)
+		return
+	}
+
+	log.Printf("⚠️ Cola llena, descartando la notificación más antigua | UserID: %s\n", sub.userID)
+	select {
+	case <-sub.notifications:
+	default:
+	}
+	select {
+	case sub.notifications <- notification:
+	default:
+	}
+}
+
+// Publish envía la notificación a todas las suscripciones de su usuario
+func (h *Hub) Publish(notification models.Notification) {
+	h.broadcast <- notification
+}
+
+// Subscribe registra una nueva conexión del usuario
+func (h *Hub) Subscribe(userID string) *Subscription {
+	sub := &Subscription{
+		userID:        userID,
+		notifications: make(chan models.Notification, h.bufferSize),
+		evicted:       make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.subscriptions[userID] == nil {
+		h.subscriptions[userID] = make(map[*Subscription]bool)
+	}
+	h.subscriptions[userID][sub] = true
+	metrics.ConnectedClients.Inc()
+
+	return sub
+}
+
+// Unsubscribe elimina la conexión, después de llamarlo ya no se le entregan notificaciones
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(sub)
+}
+
+// remove debe llamarse con h.mu bloqueado, es seguro llamarlo más de una vez
+func (h *Hub) remove(sub *Subscription) {
+	if !h.subscriptions[sub.userID][sub] {
+		return
+	}
+
+	delete(h.subscriptions[sub.userID], sub)
+	if len(h.subscriptions[sub.userID]) == 0 {
+		delete(h.subscriptions, sub.userID)
+	}
+	metrics.ConnectedClients.Dec()
+}
+
+// Stream entrega a send primero el backlog de replay (si existe) y después las notificaciones en vivo
+// de la suscripción, sin duplicar las que ya venían en el backlog. Termina cuando ctx se cancela, send falla
+// o la conexión es desconectada por lenta (ErrSlowClient).
+// La suscripción debe crearse antes de llamar a Stream para no perder lo que llegue durante el replay.
+func (h *Hub) Stream(ctx context.Context, sub *Subscription, replay ReplayFunc, send func(models.Notification) error) error {
+	replayed := make(map[primitive.ObjectID]bool)
+
+	if replay != nil {
+		backlog, err := replay()
+		if err != nil {
+			return err
+		}
+
+		for _, notification := range backlog {
+			if err := send(notification); err != nil {
+				return err
+			}
+			replayed[notification.ID] = true
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sub.evicted:
+			return ErrSlowClient
+		case notification := <-sub.notifications:
+			if replayed[notification.ID] {
+				continue
+			}
+			if err := send(notification); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// collect ejecuta Stream en segundo plano y devuelve las notificaciones que recibe la suscripción
+func collect(hub *services.Hub, sub *services.Subscription, replay services.ReplayFunc) (chan models.Notification, context.CancelFunc) {
+	received := make(chan models.Notification, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.Stream(ctx, sub, replay, func(notification models.Notification) error {
+		received <- notification
+		return nil
+	})
+	return received, cancel
+}
+
+func receive(t *testing.T, received chan models.Notification) models.Notification {
+	select {
+	case notification := <-received:
+		return notification
+	case <-time.After(time.Second):
+		t.Fatal("no se recibió la notificación")
+		return models.Notification{}
+	}
+}
+
+// flush espera a que Run termine de repartir lo publicado: Run procesa en orden,
+// por lo que al aceptar otra notificación ya encoló las anteriores
+func flush(hub *services.Hub) {
+	hub.Publish(models.Notification{UserID: "flush"})
+}
+
+func TestHub_Publish_OnlyToOwner(t *testing.T) {
+	hub := services.NewHub(10, services.DropOldest)
+	go hub.Run()
+
+	// El usuario 1 tiene dos pestañas abiertas y el usuario 2 una
+	firstTab, cancelFirst := collect(hub, hub.Subscribe("user-1"), nil)
+	defer cancelFirst()
+	secondTab, cancelSecond := collect(hub, hub.Subscribe("user-1"), nil)
+	defer cancelSecond()
+	otherUser, cancelOther := collect(hub, hub.Subscribe("user-2"), nil)
+	defer cancelOther()
+
+	notification := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1", Message: "Nueva tarea creada"}
+	hub.Publish(notification)
+
+	// Ambas pestañas del usuario 1 reciben la notificación
+	assert.Equal(t, notification.ID, receive(t, firstTab).ID)
+	assert.Equal(t, notification.ID, receive(t, secondTab).ID)
+
+	// El usuario 2 no recibe nada
+	select {
+	case <-otherUser:
+		t.Fatal("el usuario 2 recibió una notificación ajena")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_Stream_ReplaysBacklogWithoutDuplicates(t *testing.T) {
+	hub := services.NewHub(10, services.DropOldest)
+	go hub.Run()
+
+	missed := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1", Message: "Perdida"}
+	overlapping := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1", Message: "En backlog y en vivo"}
+	live := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1", Message: "En vivo"}
+
+	// La suscripción existe antes del replay, por lo que recibe en vivo una notificación que también está en el backlog
+	sub := hub.Subscribe("user-1")
+	hub.Publish(overlapping)
+
+	received, cancel := collect(hub, sub, func() ([]models.Notification, error) {
+		return []models.Notification{missed, overlapping}, nil
+	})
+	defer cancel()
+
+	hub.Publish(live)
+
+	// Primero el backlog en orden, después lo que llegó en vivo sin repetir
+	assert.Equal(t, missed.ID, receive(t, received).ID)
+	assert.Equal(t, overlapping.ID, receive(t, received).ID)
+	assert.Equal(t, live.ID, receive(t, received).ID)
+
+	select {
+	case notification := <-received:
+		t.Fatalf("notificación duplicada: %s", notification.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_Unsubscribe_StopsDelivery(t *testing.T) {
+	hub := services.NewHub(10, services.DropOldest)
+	go hub.Run()
+
+	sub := hub.Subscribe("user-1")
+	received, cancel := collect(hub, sub, nil)
+	defer cancel()
+
+	hub.Unsubscribe(sub)
+	hub.Publish(models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"})
+
+	select {
+	case <-received:
+		t.Fatal("se recibió una notificación después de cancelar la suscripción")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_DropOldest_KeepsNewest(t *testing.T) {
+	hub := services.NewHub(2, services.DropOldest)
+	go hub.Run()
+
+	// Nadie consume la cola mientras se publican tres notificaciones
+	sub := hub.Subscribe("user-1")
+	first := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"}
+	second := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"}
+	third := models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"}
+	hub.Publish(first)
+	hub.Publish(second)
+	hub.Publish(third)
+	flush(hub)
+
+	received, cancel := collect(hub, sub, nil)
+	defer cancel()
+
+	// La más antigua se descartó para hacer espacio
+	assert.Equal(t, second.ID, receive(t, received).ID)
+	assert.Equal(t, third.ID, receive(t, received).ID)
+}
+
+func TestHub_Disconnect_EvictsSlowClient(t *testing.T) {
+	hub := services.NewHub(1, services.Disconnect)
+	go hub.Run()
+
+	sub := hub.Subscribe("user-1")
+	hub.Publish(models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"})
+	hub.Publish(models.Notification{ID: primitive.NewObjectID(), UserID: "user-1"})
+	flush(hub)
+
+	// Al consumir, la conexión ya fue marcada como lenta y Stream termina con ErrSlowClient
+	err := hub.Stream(context.Background(), sub, nil, func(models.Notification) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, services.ErrSlowClient)
+}
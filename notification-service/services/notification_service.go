@@ -2,26 +2,161 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"log"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/vadgun/gotrelloclone/notification-service/infra/backplane"
 	"github.com/vadgun/gotrelloclone/notification-service/models"
 	"github.com/vadgun/gotrelloclone/notification-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// maxReplay limita cuántas notificaciones perdidas se reenvían al reconectar
+const maxReplay = 500
+
+var (
+	ErrInvalidCursor         = errors.New("cursor inválido")
+	ErrInvalidNotificationID = errors.New("id de notificación inválido")
 )
 
 type NotificationService struct {
-	repo             *repositories.NotificationRepository
-	webSocketService *WebSocketService
+	repo            *repositories.NotificationRepository
+	boardRepo       *repositories.BoardRepository
+	preferencesRepo *repositories.PreferencesRepository
+	backplane       backplane.Backplane
+	emailService    *EmailService
 }
 
-func NewNotificationService(repo *repositories.NotificationRepository, wsService *WebSocketService) *NotificationService {
-	return &NotificationService{repo: repo, webSocketService: wsService}
+func NewNotificationService(repo *repositories.NotificationRepository, boardRepo *repositories.BoardRepository, preferencesRepo *repositories.PreferencesRepository, notificationBackplane backplane.Backplane, emailService *EmailService) *NotificationService {
+	return &NotificationService{repo: repo, boardRepo: boardRepo, preferencesRepo: preferencesRepo, backplane: notificationBackplane, emailService: emailService}
 }
 
 func (s *NotificationService) CreateNotification(ctx context.Context, notification *models.Notification) error {
 	return s.repo.SaveNotification(ctx, notification)
 }
 
-func (s *NotificationService) SendNotificationWebSocket(message string) {
-	log.Println("Enviando notificación por WebSocket:", message)
-	s.webSocketService.SendMessage(message)
+// PublishNotification envía la notificación en tiempo real (WebSocket y SSE) solo a las conexiones
+// del usuario al que pertenece, sin importar en qué réplica estén conectadas
+func (s *NotificationService) PublishNotification(notification *models.Notification) {
+	log.Printf("Enviando notificación en tiempo real | UserID: %s | Message: %s\n", notification.UserID, notification.Message)
+	if err := s.backplane.Publish(context.Background(), *notification); err != nil {
+		log.Println("Error publicando la notificación en el backplane:", err)
+	}
+}
+
+// GetMissedNotifications obtiene en orden cronológico las notificaciones posteriores a la última
+// que vio el cliente, identificada por su ID (lastID) o por una fecha (since).
+func (s *NotificationService) GetMissedNotifications(ctx context.Context, userID string, lastID primitive.ObjectID, since time.Time) ([]models.Notification, error) {
+	if !lastID.IsZero() {
+		last, err := s.repo.GetNotificationByID(ctx, userID, lastID)
+		if err == nil {
+			return s.repo.GetNotificationsAfter(ctx, userID, &repositories.NotificationCursor{CreatedAt: last.CreatedAt, ID: last.ID}, maxReplay)
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, err
+		}
+		// Si la notificación ya fue eliminada se usa la fecha contenida en su ObjectID
+		since = lastID.Timestamp()
+	}
+
+	return s.repo.GetNotificationsAfter(ctx, userID, &repositories.NotificationCursor{CreatedAt: since}, maxReplay)
+}
+
+// GetNotifications devuelve una página del inbox del usuario y el cursor para pedir la siguiente,
+// el cursor es vacío cuando ya no hay más notificaciones.
+func (s *NotificationService) GetNotifications(ctx context.Context, userID, cursor string, limit int64, unreadOnly bool) ([]models.Notification, string, error) {
+	var after *repositories.NotificationCursor
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+	}
+
+	// Se pide una notificación extra para saber si existe otra página
+	notifications, err := s.repo.GetNotificationsByUser(ctx, userID, after, limit+1, unreadOnly)
+	if err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if int64(len(notifications)) > limit {
+		notifications = notifications[:limit]
+		last := notifications[len(notifications)-1]
+		nextCursor = encodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return notifications, nextCursor, nil
+}
+
+func (s *NotificationService) CountUnread(ctx context.Context, userID string) (int64, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+func (s *NotificationService) MarkAsRead(ctx context.Context, userID string, notificationIDs []string) (int64, error) {
+	ids, err := parseNotificationIDs(notificationIDs)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.MarkAsRead(ctx, userID, ids)
+}
+
+func (s *NotificationService) MarkAllAsRead(ctx context.Context, userID string) (int64, error) {
+	return s.repo.MarkAllAsRead(ctx, userID)
+}
+
+func (s *NotificationService) DeleteNotifications(ctx context.Context, userID string, notificationIDs []string) (int64, error) {
+	ids, err := parseNotificationIDs(notificationIDs)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.DeleteNotifications(ctx, userID, ids)
+}
+
+func parseNotificationIDs(notificationIDs []string) ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(notificationIDs))
+	for _, notificationID := range notificationIDs {
+		id, err := primitive.ObjectIDFromHex(notificationID)
+		if err != nil {
+			return nil, ErrInvalidNotificationID
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// El cursor es opaco para el cliente: base64("<createdAt en unix nano>:<id>")
+func encodeCursor(createdAt time.Time, id primitive.ObjectID) string {
+	raw := strconv.FormatInt(createdAt.UnixNano(), 10) + ":" + id.Hex()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*repositories.NotificationCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := primitive.ObjectIDFromHex(parts[1])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &repositories.NotificationCursor{CreatedAt: time.Unix(0, nanos), ID: id}, nil
 }
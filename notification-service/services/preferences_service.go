@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// quietHoursLayout es el formato de las horas de inicio y fin del horario silencioso
+const quietHoursLayout = "15:04"
+
+var ErrInvalidPreferences = errors.New("preferencias inválidas")
+
+// Delivery indica por qué canales se entrega una notificación después de aplicar las preferencias del usuario
+type Delivery struct {
+	InApp       bool // Se guarda en el inbox
+	Push        bool // Se envía en tiempo real por WebSocket/SSE
+	Email       bool
+	EmailDigest bool // El correo espera al siguiente resumen en lugar de enviarse al momento
+}
+
+// Discarded indica que la notificación no se entrega por ningún canal
+func (d Delivery) Discarded() bool {
+	return !d.InApp && !d.Email
+}
+
+// GetPreferences obtiene las preferencias del usuario o las de por defecto si nunca las ha guardado
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	preferences, err := s.preferencesRepo.GetPreferences(ctx, userID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		defaults := models.DefaultPreferences(userID)
+		return &defaults, nil
+	}
+	return preferences, err
+}
+
+// UpdatePreferences valida y guarda las preferencias del usuario
+func (s *NotificationService) UpdatePreferences(ctx context.Context, preferences *models.NotificationPreferences) error {
+	if err := ValidatePreferences(preferences); err != nil {
+		return err
+	}
+	if preferences.EventTypes == nil {
+		preferences.EventTypes = map[string]bool{}
+	}
+	if preferences.MutedBoards == nil {
+		preferences.MutedBoards = []string{}
+	}
+	preferences.UpdatedAt = time.Now()
+	return s.preferencesRepo.SavePreferences(ctx, preferences)
+}
+
+// ValidatePreferences revisa que los tipos de evento existan y que el horario silencioso sea válido
+func ValidatePreferences(preferences *models.NotificationPreferences) error {
+	for eventType := range preferences.EventTypes {
+		if !slices.Contains(models.TaskEventTypes, eventType) {
+			return fmt.Errorf("%w: tipo de evento desconocido %q", ErrInvalidPreferences, eventType)
+		}
+	}
+
+	if quietHours := preferences.QuietHours; quietHours != nil {
+		if _, err := time.Parse(quietHoursLayout, quietHours.Start); err != nil {
+			return fmt.Errorf("%w: hora de inicio inválida, se espera HH:MM", ErrInvalidPreferences)
+		}
+		if _, err := time.Parse(quietHoursLayout, quietHours.End); err != nil {
+			return fmt.Errorf("%w: hora de fin inválida, se espera HH:MM", ErrInvalidPreferences)
+		}
+		if _, err := time.LoadLocation(quietHours.Timezone); err != nil {
+			return fmt.Errorf("%w: zona horaria desconocida %q", ErrInvalidPreferences, quietHours.Timezone)
+		}
+	}
+
+	return nil
+}
+
+// EvaluatePreferences aplica las reglas del usuario a la notificación:
+// los tipos de evento desactivados y los tableros silenciados se descartan, y durante el horario
+// silencioso la notificación se guarda en el inbox pero no se envía en tiempo real y su correo espera al resumen.
+func EvaluatePreferences(preferences *models.NotificationPreferences, notification models.Notification, now time.Time) Delivery {
+	if enabled, ok := preferences.EventTypes[notification.Type]; ok && !enabled {
+		return Delivery{}
+	}
+	if notification.BoardID != "" && slices.Contains(preferences.MutedBoards, notification.BoardID) {
+		return Delivery{}
+	}
+
+	quiet := InQuietHours(preferences.QuietHours, now)
+	return Delivery{
+		InApp:       preferences.Channels.InApp,
+		Push:        preferences.Channels.InApp && !quiet,
+		Email:       preferences.Channels.Email,
+		EmailDigest: preferences.Channels.Email && (preferences.EmailDigest || quiet),
+	}
+}
+
+// InQuietHours indica si now cae dentro del horario silencioso en la zona horaria del usuario.
+// El rango puede cruzar la medianoche; si inicio y fin son iguales no hay horario silencioso.
+func InQuietHours(quietHours *models.QuietHours, now time.Time) bool {
+	if quietHours == nil {
+		return false
+	}
+
+	location, err := time.LoadLocation(quietHours.Timezone)
+	if err != nil {
+		return false
+	}
+	start, errStart := time.Parse(quietHoursLayout, quietHours.Start)
+	end, errEnd := time.Parse(quietHoursLayout, quietHours.End)
+	if errStart != nil || errEnd != nil {
+		return false
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	if startMinute > endMinute {
+		return minute >= startMinute || minute < endMinute
+	}
+	return false
+}
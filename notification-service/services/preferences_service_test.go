@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+)
+
+func TestEvaluatePreferences_Defaults(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	notification := models.Notification{Type: models.TaskCreatedEvent, BoardID: "board"}
+
+	delivery := services.EvaluatePreferences(&preferences, notification, time.Now())
+
+	assert.Equal(t, services.Delivery{InApp: true, Push: true}, delivery)
+}
+
+func TestEvaluatePreferences_DisabledEventAndMutedBoard(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	preferences.EventTypes[models.TaskUpdatedEvent] = false
+	preferences.MutedBoards = []string{"muted"}
+
+	updated := models.Notification{Type: models.TaskUpdatedEvent, BoardID: "board"}
+	assert.True(t, services.EvaluatePreferences(&preferences, updated, time.Now()).Discarded())
+
+	muted := models.Notification{Type: models.TaskCreatedEvent, BoardID: "muted"}
+	assert.True(t, services.EvaluatePreferences(&preferences, muted, time.Now()).Discarded())
+}
+
+func TestEvaluatePreferences_NoChannelsDiscards(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	preferences.Channels = models.Channels{}
+	notification := models.Notification{Type: models.TaskCreatedEvent, BoardID: "board"}
+
+	// Sin in-app ni email la notificación no llega por ningún lado, no debe contarse como entregada
+	assert.True(t, services.EvaluatePreferences(&preferences, notification, time.Now()).Discarded())
+}
+
+func TestEvaluatePreferences_QuietHoursPersistWithoutPush(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	preferences.QuietHours = &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "America/Mexico_City"}
+	notification := models.Notification{Type: models.TaskCreatedEvent}
+
+	// 05:00 UTC son las 23:00 en Ciudad de México
+	night := time.Date(2025, 3, 10, 5, 0, 0, 0, time.UTC)
+	delivery := services.EvaluatePreferences(&preferences, notification, night)
+	assert.True(t, delivery.InApp)
+	assert.False(t, delivery.Push)
+
+	// 18:00 UTC son las 12:00 en Ciudad de México
+	noon := time.Date(2025, 3, 10, 18, 0, 0, 0, time.UTC)
+	assert.True(t, services.EvaluatePreferences(&preferences, notification, noon).Push)
+}
+
+func TestValidatePreferences(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	preferences.EventTypes["unknown"] = false
+	assert.ErrorIs(t, services.ValidatePreferences(&preferences), services.ErrInvalidPreferences)
+
+	preferences = models.DefaultPreferences("user")
+	preferences.QuietHours = &models.QuietHours{Start: "25:00", End: "07:00", Timezone: "UTC"}
+	assert.ErrorIs(t, services.ValidatePreferences(&preferences), services.ErrInvalidPreferences)
+
+	preferences.QuietHours = &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}
+	assert.ErrorIs(t, services.ValidatePreferences(&preferences), services.ErrInvalidPreferences)
+
+	preferences.QuietHours.Timezone = "Europe/Madrid"
+	assert.NoError(t, services.ValidatePreferences(&preferences))
+}
+
+func TestEvaluatePreferences_EmailDigest(t *testing.T) {
+	preferences := models.DefaultPreferences("user")
+	preferences.Channels.Email = true
+	preferences.QuietHours = &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"}
+	notification := models.Notification{Type: models.TaskAssignedEvent}
+
+	// Fuera del horario silencioso el correo se envía al momento
+	noon := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	delivery := services.EvaluatePreferences(&preferences, notification, noon)
+	assert.True(t, delivery.Email)
+	assert.False(t, delivery.EmailDigest)
+
+	// Durante el horario silencioso espera al resumen
+	night := time.Date(2025, 3, 10, 23, 0, 0, 0, time.UTC)
+	assert.True(t, services.EvaluatePreferences(&preferences, notification, night).EmailDigest)
+
+	preferences.EmailDigest = true
+	assert.True(t, services.EvaluatePreferences(&preferences, notification, noon).EmailDigest)
+}
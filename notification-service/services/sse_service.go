@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+)
+
+// heartbeatInterval evita que proxies y balanceadores cierren el stream por inactividad
+const heartbeatInterval = 15 * time.Second
+
+// SSEService entrega las notificaciones por Server-Sent Events, alternativa a WebSocket
+// para redes donde los proxies bloquean el upgrade.
+type SSEService struct {
+	hub *Hub
+}
+
+func NewSSEService(hub *Hub) *SSEService {
+	return &SSEService{hub: hub}
+}
+
+// HandleStream mantiene abierto el stream del usuario autenticado hasta que el cliente se desconecta
+// o expira su token. Cada evento lleva como id el de la notificación para reanudar con Last-Event-ID.
+func (s *SSEService) HandleStream(w http.ResponseWriter, r *http.Request, userID string, expiresAt time.Time, replay ReplayFunc) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming no soportado", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	if !expiresAt.IsZero() {
+		ctx, cancel = context.WithDeadline(ctx, expiresAt)
+		defer cancel()
+	}
+
+	// La suscripción se registra antes de consultar el backlog para no perder lo que llegue mientras tanto
+	sub := s.hub.Subscribe(userID)
+	defer s.hub.Unsubscribe(sub)
+
+	// Los heartbeats y los eventos se escriben desde goroutines distintas, cada escritura tiene un tiempo
+	// máximo para que un cliente que no lee no bloquee la goroutine indefinidamente
+	controller := http.NewResponseController(w)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-heartbeat.C:
+				mu.Lock()
+				controller.SetWriteDeadline(time.Now().Add(config.WriteWait))
+				_, err := fmt.Fprint(w, ": ping\n\n")
+				if err == nil {
+					flusher.Flush()
+				}
+				mu.Unlock()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err := s.hub.Stream(ctx, sub, replay, func(notification models.Notification) error {
+		mu.Lock()
+		defer mu.Unlock()
+		controller.SetWriteDeadline(time.Now().Add(config.WriteWait))
+		return writeEvent(w, flusher, notification)
+	})
+
+	if errors.Is(err, ErrSlowClient) {
+		// El cliente recupera lo perdido al reconectar con Last-Event-ID
+		log.Printf("Cerrando stream SSE de cliente lento | UserID: %s\n", userID)
+	} else if err != nil && ctx.Err() == nil {
+		log.Printf("Error al enviar evento SSE | UserID: %s | Error: %v\n", userID, err)
+	}
+
+	// Esperar a que el heartbeat deje de escribir antes de terminar la respuesta
+	expired := ctx.Err() == context.DeadlineExceeded
+	cancel()
+	wg.Wait()
+
+	if expired {
+		// Se avisa al cliente para que renueve el token antes de reconectar
+		fmt.Fprint(w, "event: token-expired\ndata: {}\n\n")
+		flusher.Flush()
+		log.Printf("Token expirado, cerrando stream SSE | UserID: %s\n", userID)
+	}
+}
+
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, notification models.Notification) error {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: notification\ndata: %s\n\n", notification.ID.Hex(), data); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
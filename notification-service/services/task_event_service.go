@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NotifyTaskEvent crea y entrega una notificación por cada involucrado en el evento de la tarea:
+// creador, asignado y owner/miembros del tablero (también del tablero anterior si la tarea se movió).
+// Quien hizo el cambio no se notifica a sí mismo y cada notificación pasa por las preferencias del destinatario.
+// Los eventos con event_id (recordatorios) se notifican una sola vez aunque lleguen repetidos.
+func (s *NotificationService) NotifyTaskEvent(ctx context.Context, key string, event models.TaskEvent) (err error) {
+	if _, ok := TaskEventMessage(key, event, ""); !ok {
+		log.Printf("⚠️ Evento de tarea desconocido, se ignora | Key: %s\n", key)
+		return nil
+	}
+
+	if event.EventID != "" {
+		claimed, err := s.repo.ClaimEvent(ctx, event.EventID)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			log.Printf("🔁 Evento repetido, se ignora | Key: %s | EventID: %s\n", key, event.EventID)
+			return nil
+		}
+		defer func() {
+			// Si no se pudo notificar, una nueva entrega del evento lo vuelve a intentar
+			if err != nil {
+				if releaseErr := s.repo.ReleaseEvent(context.Background(), event.EventID); releaseErr != nil {
+					log.Printf("⚠️ Error liberando el evento %s: %v\n", event.EventID, releaseErr)
+				}
+			}
+		}()
+	}
+
+	// Reordenar una tarea dentro de su misma lista no se notifica
+	if key == models.TaskMovedEvent && isReorder(event) {
+		return nil
+	}
+
+	var recipients []string
+	switch key {
+	case models.TaskMentionedEvent:
+		recipients = MentionRecipients(event)
+	case models.TaskDueSoonEvent, models.TaskOverdueEvent:
+		recipients = ReminderRecipients(event)
+	default:
+		boards, err := s.taskEventBoards(ctx, event)
+		if err != nil {
+			return err
+		}
+		recipients = TaskEventRecipients(event, boards...)
+	}
+
+	var errs []error
+	for _, userID := range recipients {
+		message, _ := TaskEventMessage(key, event, userID)
+		notification := models.Notification{
+			UserID:    userID,
+			Message:   message,
+			Type:      key,
+			TaskID:    event.Task.ID,
+			BoardID:   event.Task.BoardID,
+			CreatedAt: time.Now(),
+		}
+
+		preferences, err := s.GetPreferences(ctx, userID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error al obtener las preferencias de %s: %v", userID, err))
+			continue
+		}
+
+		delivery := EvaluatePreferences(preferences, notification, notification.CreatedAt)
+		if delivery.Discarded() {
+			log.Printf("🔕 Notificación descartada por preferencias | UserID: %s | Type: %s\n", userID, key)
+			continue
+		}
+
+		if err := s.deliver(ctx, &notification, delivery); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// deliver entrega la notificación por los canales que permiten las preferencias del destinatario
+func (s *NotificationService) deliver(ctx context.Context, notification *models.Notification, delivery Delivery) error {
+	var errs []error
+
+	if delivery.InApp {
+		if err := s.CreateNotification(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("error al guardar la notificación de %s: %v", notification.UserID, err))
+		} else if delivery.Push {
+			s.PublishNotification(notification)
+		}
+	}
+
+	if delivery.Email {
+		if err := s.emailService.Deliver(ctx, *notification, delivery.EmailDigest); err != nil {
+			errs = append(errs, fmt.Errorf("error al enviar el correo de %s: %v", notification.UserID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// taskEventBoards obtiene de la proyección local los tableros afectados por el evento.
+// Un tablero cuyo evento aún no llega simplemente no aporta destinatarios.
+func (s *NotificationService) taskEventBoards(ctx context.Context, event models.TaskEvent) ([]*models.Board, error) {
+	boardIDs := []string{event.Task.BoardID}
+	if event.OldBoardID != "" && event.OldBoardID != event.Task.BoardID {
+		boardIDs = append(boardIDs, event.OldBoardID)
+	}
+
+	var boards []*models.Board
+	for _, boardID := range boardIDs {
+		board, err := s.boardRepo.GetBoardByID(ctx, boardID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+// TaskEventRecipients devuelve sin duplicados a los usuarios que deben enterarse del evento,
+// excluyendo a quien lo originó y a los mencionados en el comentario, que reciben su propio aviso
+func TaskEventRecipients(event models.TaskEvent, boards ...*models.Board) []string {
+	candidates := []string{event.Task.UserID, event.Task.AssigneeID}
+	for _, board := range boards {
+		candidates = append(candidates, board.OwnerID)
+		candidates = append(candidates, board.MemberIDs...)
+	}
+
+	recipients := []string{}
+	for _, userID := range candidates {
+		if userID == "" || userID == event.ActorID || slices.Contains(event.MentionedIDs, userID) || slices.Contains(recipients, userID) {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+// MentionRecipients devuelve sin duplicados a los usuarios mencionados en el comentario, excepto su autor
+func MentionRecipients(event models.TaskEvent) []string {
+	recipients := []string{}
+	for _, userID := range event.MentionedIDs {
+		if userID == "" || userID == event.ActorID || slices.Contains(recipients, userID) {
+			continue
+		}
+		recipients = append(recipients, userID)
+	}
+	return recipients
+}
+
+// ReminderRecipients devuelve a quien debe recibir el recordatorio de la fecha límite:
+// el asignado, o el creador si la tarea no tiene asignado
+func ReminderRecipients(event models.TaskEvent) []string {
+	if event.Task.AssigneeID != "" {
+		return []string{event.Task.AssigneeID}
+	}
+	if event.Task.UserID != "" {
+		return []string{event.Task.UserID}
+	}
+	return []string{}
+}
+
+// isReorder indica si el movimiento solo cambió la posición de la tarea dentro de su lista
+func isReorder(event models.TaskEvent) bool {
+	sameBoard := event.OldBoardID == "" || event.OldBoardID == event.Task.BoardID
+	return sameBoard && event.OldListID == event.Task.ListID
+}
+
+// TaskEventMessage arma el mensaje del evento para el destinatario, devuelve false si la key no se conoce
+func TaskEventMessage(key string, event models.TaskEvent, recipientID string) (string, bool) {
+	title := event.Task.Title
+	switch key {
+	case models.TaskCreatedEvent:
+		return fmt.Sprintf("Nueva tarea creada: %s", title), true
+	case models.TaskUpdatedEvent:
+		return fmt.Sprintf("Tarea actualizada: %s", title), true
+	case models.TaskStatusUpdatedEvent:
+		if event.OldStatus == "" {
+			return fmt.Sprintf("Estado de la tarea actualizado: %s (%s)", title, event.Task.Status), true
+		}
+		return fmt.Sprintf("Estado de la tarea actualizado: %s (%s → %s)", title, event.OldStatus, event.Task.Status), true
+	case models.TaskMovedEvent:
+		if event.OldBoardID != "" && event.OldBoardID != event.Task.BoardID {
+			return fmt.Sprintf("Tarea movida a otro tablero: %s", title), true
+		}
+		return fmt.Sprintf("Tarea movida de lista: %s", title), true
+	case models.TaskAssignedEvent:
+		if recipientID != "" && recipientID == event.Task.AssigneeID {
+			return fmt.Sprintf("Te asignaron la tarea: %s", title), true
+		}
+		return fmt.Sprintf("Tarea asignada: %s", title), true
+	case models.TaskDeletedEvent:
+		return fmt.Sprintf("Tarea eliminada: %s", title), true
+	case models.TaskRestoredEvent:
+		return fmt.Sprintf("Tarea restaurada: %s", title), true
+	case models.TaskArchivedEvent:
+		return fmt.Sprintf("Tarea archivada: %s", title), true
+	case models.TaskUnarchivedEvent:
+		return fmt.Sprintf("Tarea desarchivada: %s", title), true
+	case models.TaskCommentedEvent:
+		return fmt.Sprintf("Nuevo comentario en la tarea: %s", title), true
+	case models.TaskMentionedEvent:
+		return fmt.Sprintf("Te mencionaron en un comentario de la tarea: %s", title), true
+	case models.TaskDueSoonEvent:
+		if event.Task.DueAt == nil {
+			return fmt.Sprintf("La tarea vence pronto: %s", title), true
+		}
+		return fmt.Sprintf("La tarea vence pronto: %s (%s UTC)", title, event.Task.DueAt.UTC().Format("02/01/2006 15:04")), true
+	case models.TaskOverdueEvent:
+		return fmt.Sprintf("La tarea está vencida: %s", title), true
+	}
+	return "", false
+}
+
+// SaveBoard actualiza la proyección local con el tablero recibido de board-events
+func (s *NotificationService) SaveBoard(ctx context.Context, board *models.Board) error {
+	return s.boardRepo.SaveBoard(ctx, board)
+}
+
+func (s *NotificationService) DeleteBoard(ctx context.Context, boardID string) error {
+	return s.boardRepo.DeleteBoard(ctx, boardID)
+}
+
+// SaveUser actualiza la proyección local de usuarios usada por el canal de correo
+func (s *NotificationService) SaveUser(ctx context.Context, user *models.User) error {
+	return s.emailService.SaveUser(ctx, user)
+}
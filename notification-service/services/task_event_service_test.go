@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+)
+
+func TestTaskEventRecipients_ExcludesActorAndDuplicates(t *testing.T) {
+	event := models.TaskEvent{
+		Task:    models.Task{UserID: "creator", AssigneeID: "assignee"},
+		ActorID: "assignee",
+	}
+	board := &models.Board{OwnerID: "creator", MemberIDs: []string{"member", "assignee"}}
+
+	recipients := services.TaskEventRecipients(event, board)
+
+	assert.Equal(t, []string{"creator", "member"}, recipients)
+}
+
+func TestTaskEventRecipients_Mentions(t *testing.T) {
+	event := models.TaskEvent{
+		Task:         models.Task{UserID: "creator", AssigneeID: "assignee"},
+		ActorID:      "author",
+		MentionedIDs: []string{"assignee", "author", "member", "assignee"},
+	}
+	board := &models.Board{OwnerID: "creator", MemberIDs: []string{"member", "author"}}
+
+	assert.Equal(t, []string{"creator"}, services.TaskEventRecipients(event, board))
+	assert.Equal(t, []string{"assignee", "member"}, services.MentionRecipients(event))
+}
+
+func TestTaskEventRecipients_IncludesOldBoardOnMove(t *testing.T) {
+	event := models.TaskEvent{
+		Task:       models.Task{UserID: "creator", BoardID: "new"},
+		ActorID:    "creator",
+		OldBoardID: "old",
+	}
+	newBoard := &models.Board{ID: "new", OwnerID: "new-owner"}
+	oldBoard := &models.Board{ID: "old", OwnerID: "old-owner"}
+
+	recipients := services.TaskEventRecipients(event, newBoard, oldBoard)
+
+	assert.Equal(t, []string{"new-owner", "old-owner"}, recipients)
+}
+
+func TestTaskEventMessage(t *testing.T) {
+	event := models.TaskEvent{
+		Task:      models.Task{Title: "Diseño", AssigneeID: "assignee", Status: "DONE"},
+		OldStatus: "TODO",
+	}
+
+	message, ok := services.TaskEventMessage(models.TaskStatusUpdatedEvent, event, "creator")
+	assert.True(t, ok)
+	assert.Equal(t, "Estado de la tarea actualizado: Diseño (TODO → DONE)", message)
+
+	message, _ = services.TaskEventMessage(models.TaskAssignedEvent, event, "assignee")
+	assert.Equal(t, "Te asignaron la tarea: Diseño", message)
+
+	message, _ = services.TaskEventMessage(models.TaskRestoredEvent, event, "creator")
+	assert.Equal(t, "Tarea restaurada: Diseño", message)
+
+	_, ok = services.TaskEventMessage("unknown", event, "creator")
+	assert.False(t, ok)
+}
+
+func TestTaskEventMessage_Moved(t *testing.T) {
+	event := models.TaskEvent{
+		Task:      models.Task{Title: "Diseño", BoardID: "board", ListID: "done"},
+		OldListID: "todo",
+	}
+
+	message, _ := services.TaskEventMessage(models.TaskMovedEvent, event, "creator")
+	assert.Equal(t, "Tarea movida de lista: Diseño", message)
+
+	event.OldBoardID = "other"
+	message, _ = services.TaskEventMessage(models.TaskMovedEvent, event, "creator")
+	assert.Equal(t, "Tarea movida a otro tablero: Diseño", message)
+}
+
+func TestReminderRecipientsAndMessage(t *testing.T) {
+	dueAt := time.Date(2026, 3, 10, 18, 30, 0, 0, time.UTC)
+	event := models.TaskEvent{Task: models.Task{Title: "Diseño", UserID: "creator", AssigneeID: "assignee", DueAt: &dueAt}}
+
+	assert.Equal(t, []string{"assignee"}, services.ReminderRecipients(event))
+
+	message, _ := services.TaskEventMessage(models.TaskDueSoonEvent, event, "assignee")
+	assert.Equal(t, "La tarea vence pronto: Diseño (10/03/2026 18:30 UTC)", message)
+
+	event.Task.AssigneeID = ""
+	assert.Equal(t, []string{"creator"}, services.ReminderRecipients(event))
+}
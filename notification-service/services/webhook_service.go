@@ -0,0 +1,472 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// maxWebhookAttempts es el número de intentos antes de marcar la entrega como fallida
+	maxWebhookAttempts = 8
+	// webhookBaseBackoff es la espera antes del primer reintento, se duplica en cada intento
+	webhookBaseBackoff = 30 * time.Second
+	// webhookMaxBackoff limita la espera entre reintentos
+	webhookMaxBackoff = time.Hour
+	// webhookLease aparta una entrega mientras una réplica la intenta, debe ser mayor al timeout del cliente HTTP
+	webhookLease = time.Minute
+	// webhookPollInterval es cada cuánto se buscan reintentos pendientes
+	webhookPollInterval = 5 * time.Second
+)
+
+var (
+	ErrBoardNotFound     = errors.New("tablero no encontrado")
+	ErrNotBoardMember    = errors.New("el usuario no pertenece al tablero")
+	ErrNotBoardAdmin     = errors.New("solo el owner o un admin del tablero pueden gestionar sus webhooks")
+	ErrWebhookNotFound   = errors.New("webhook no encontrado")
+	ErrDeliveryNotFound  = errors.New("entrega no encontrada")
+	ErrInvalidEventType  = errors.New("tipo de evento inválido")
+	ErrInvalidWebhookURL = errors.New("la URL del webhook debe ser http o https")
+	// ErrWebhookTargetDenied evita que los webhooks se usen para llegar a servicios internos (SSRF)
+	ErrWebhookTargetDenied = errors.New("el destino del webhook no puede ser una dirección local, privada o interna")
+)
+
+// WebhookService registra webhooks por tablero y entrega sus eventos firmados con HMAC, reintentando con
+// backoff exponencial. Cada intento queda en el log de entregas.
+type WebhookService struct {
+	repo      *repositories.WebhookRepository
+	boardRepo *repositories.BoardRepository
+	client    *http.Client
+	wake      chan struct{}
+}
+
+func NewWebhookService(repo *repositories.WebhookRepository, boardRepo *repositories.BoardRepository) *WebhookService {
+	return &WebhookService{
+		repo:      repo,
+		boardRepo: boardRepo,
+		client:    NewWebhookClient(),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// NewWebhookClient crea el cliente HTTP de las entregas. La IP se revisa al conectar, después de resolver el DNS,
+// así un dominio que cambia a una IP interna después de validarse, o una redirección, tampoco llega a la red interna.
+func NewWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: webhookDialControl}
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		// Sin proxy, el control de la conexión debe ver la IP del destino
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+}
+
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil || !WebhookAddrAllowed(addrPort.Addr()) {
+		return ErrWebhookTargetDenied
+	}
+	return nil
+}
+
+// WebhookAddrAllowed indica si el webhook puede entregar a la IP: solo se permiten direcciones públicas
+func WebhookAddrAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified() &&
+		!sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace es el rango CGNAT (RFC 6598), tampoco es alcanzable desde internet
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// CanManageWebhooks indica si el usuario es owner o admin del tablero, los webhooks reciben todos sus eventos
+func CanManageWebhooks(board *models.Board, userID string) bool {
+	if board.OwnerID == userID {
+		return true
+	}
+	for _, member := range board.Members {
+		if member.UserID == userID {
+			return member.Role == models.RoleOwner || member.Role == models.RoleAdmin
+		}
+	}
+	return false
+}
+
+// authorizeBoard verifica que el tablero exista en la proyección y que el usuario sea su owner o admin
+func (s *WebhookService) authorizeBoard(ctx context.Context, userID, boardID string) error {
+	board, err := s.boardRepo.GetBoardByID(ctx, boardID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrBoardNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if board.OwnerID != userID && !slices.Contains(board.MemberIDs, userID) {
+		return ErrNotBoardMember
+	}
+	if !CanManageWebhooks(board, userID) {
+		return ErrNotBoardAdmin
+	}
+	return nil
+}
+
+// CreateWebhook registra el webhook en el tablero, si no se indica un secreto se genera uno
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID string, webhook *models.Webhook) error {
+	if err := s.authorizeBoard(ctx, userID, webhook.BoardID); err != nil {
+		return err
+	}
+	if err := ValidateWebhook(ctx, webhook); err != nil {
+		return err
+	}
+
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+	webhook.CreatedBy = userID
+	webhook.CreatedAt = time.Now()
+
+	return s.repo.CreateWebhook(ctx, webhook)
+}
+
+// ValidateWebhook revisa el esquema de la URL, que su host resuelva solo a IPs públicas y que los eventos existan
+func ValidateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+	if err := validateWebhookHost(ctx, target.Hostname()); err != nil {
+		return err
+	}
+	for _, event := range webhook.EventTypes {
+		if !slices.Contains(models.WebhookEventTypes, event) {
+			return fmt.Errorf("%w: %q", ErrInvalidEventType, event)
+		}
+	}
+	return nil
+}
+
+func validateWebhookHost(ctx context.Context, host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrWebhookTargetDenied
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: no se pudo resolver %q", ErrInvalidWebhookURL, host)
+	}
+	for _, addr := range addrs {
+		if !WebhookAddrAllowed(addr) {
+			return ErrWebhookTargetDenied
+		}
+	}
+	return nil
+}
+
+// GetWebhooks lista los webhooks del tablero sin sus secretos
+func (s *WebhookService) GetWebhooks(ctx context.Context, userID, boardID string) ([]models.Webhook, error) {
+	if err := s.authorizeBoard(ctx, userID, boardID); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.repo.GetWebhooksByBoard(ctx, boardID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID, boardID, webhookID string) error {
+	if err := s.authorizeBoard(ctx, userID, boardID); err != nil {
+		return err
+	}
+
+	id, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+	deleted, err := s.repo.DeleteWebhook(ctx, boardID, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// boardWebhook obtiene el webhook verificando que pertenezca al tablero y que el usuario tenga acceso
+func (s *WebhookService) boardWebhook(ctx context.Context, userID, boardID, webhookID string) (*models.Webhook, error) {
+	if err := s.authorizeBoard(ctx, userID, boardID); err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	webhook, err := s.repo.GetWebhookByID(ctx, id)
+	if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && webhook.BoardID != boardID) {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+// GetDeliveries devuelve las entregas más recientes del webhook
+func (s *WebhookService) GetDeliveries(ctx context.Context, userID, boardID, webhookID string, limit int64) ([]models.WebhookDelivery, error) {
+	webhook, err := s.boardWebhook(ctx, userID, boardID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetDeliveries(ctx, webhook.ID, limit)
+}
+
+// Redeliver crea una nueva entrega con el mismo payload, la original se conserva en el log
+func (s *WebhookService) Redeliver(ctx context.Context, userID, boardID, webhookID, deliveryID string) (*models.WebhookDelivery, error) {
+	webhook, err := s.boardWebhook(ctx, userID, boardID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	original, err := s.repo.GetDeliveryByID(ctx, webhook.ID, id)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		BoardID:       original.BoardID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: now,
+		RedeliveryOf:  &original.ID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	s.notifyWorker()
+	return delivery, nil
+}
+
+// EnqueueEvent crea una entrega pendiente para cada webhook de los tableros suscrito al evento.
+// data es el evento tal como llegó de Kafka.
+func (s *WebhookService) EnqueueEvent(ctx context.Context, event string, boardIDs []string, data []byte) error {
+	webhooks, err := s.repo.GetSubscribedWebhooks(ctx, boardIDs, event)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, webhook := range webhooks {
+		now := time.Now()
+		delivery := &models.WebhookDelivery{
+			ID:            primitive.NewObjectID(),
+			WebhookID:     webhook.ID,
+			BoardID:       webhook.BoardID,
+			Event:         event,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+
+		payload, err := json.Marshal(models.WebhookPayload{
+			DeliveryID: delivery.ID.Hex(),
+			Event:      event,
+			BoardID:    webhook.BoardID,
+			CreatedAt:  now,
+			Data:       data,
+		})
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivery.Payload = string(payload)
+
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("error registrando la entrega del webhook %s: %v", webhook.ID.Hex(), err))
+		}
+	}
+
+	if len(webhooks) > 0 {
+		s.notifyWorker()
+	}
+	return errors.Join(errs...)
+}
+
+// notifyWorker despierta al worker para que intente las entregas nuevas sin esperar al siguiente ciclo
+func (s *WebhookService) notifyWorker() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// RunWorker intenta las entregas pendientes hasta que ctx se cancela, varias réplicas pueden ejecutarlo a la vez
+func (s *WebhookService) RunWorker(ctx context.Context) {
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDueDeliveries(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *WebhookService) processDueDeliveries(ctx context.Context) {
+	for ctx.Err() == nil {
+		delivery, err := s.repo.ClaimDueDelivery(ctx, webhookLease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return
+		}
+		if err != nil {
+			log.Printf("⚠️ Error obteniendo entregas de webhooks pendientes: %v\n", err)
+			return
+		}
+
+		s.attempt(ctx, delivery)
+		if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Printf("⚠️ Error guardando el resultado de la entrega %s: %v\n", delivery.ID.Hex(), err)
+		}
+	}
+}
+
+// attempt envía la entrega y actualiza su estado, programando el siguiente reintento si falla
+func (s *WebhookService) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	webhook, err := s.repo.GetWebhookByID(ctx, delivery.WebhookID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		delivery.Status = models.DeliveryFailed
+		delivery.Error = ErrWebhookNotFound.Error()
+		return
+	}
+	if err != nil {
+		// Error de la base de datos, se reintenta cuando venza el lease
+		log.Printf("⚠️ Error obteniendo el webhook %s: %v\n", delivery.WebhookID.Hex(), err)
+		delivery.NextAttemptAt = time.Now().Add(webhookLease)
+		return
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, err = s.post(ctx, webhook, delivery)
+	if err == nil {
+		now := time.Now()
+		delivery.Status = models.DeliverySucceeded
+		delivery.Error = ""
+		delivery.DeliveredAt = &now
+		log.Printf("🪝 Webhook entregado | Webhook: %s | Event: %s\n", webhook.ID.Hex(), delivery.Event)
+		return
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Attempts >= maxWebhookAttempts {
+		delivery.Status = models.DeliveryFailed
+		log.Printf("⚠️ Webhook fallido tras %d intentos | Webhook: %s | Error: %v\n", delivery.Attempts, webhook.ID.Hex(), err)
+		return
+	}
+	delivery.NextAttemptAt = time.Now().Add(WebhookBackoff(delivery.Attempts))
+}
+
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "gotrelloclone-webhooks")
+	request.Header.Set("X-Webhook-Event", delivery.Event)
+	request.Header.Set("X-Webhook-Delivery", delivery.ID.Hex())
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", "sha256="+SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("respuesta %d del destino", response.StatusCode)
+	}
+	return response.StatusCode, nil
+}
+
+// SignWebhookPayload firma "<timestamp>.<body>" con HMAC-SHA256, el destino recalcula la firma con el secreto
+// y rechaza timestamps viejos para evitar que se reenvíe una petición capturada
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookBackoff devuelve la espera antes del siguiente reintento: 30s, 1m, 2m, 4m... hasta una hora
+func WebhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
+	"github.com/vadgun/gotrelloclone/notification-service/services"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event":"new-task"}`)
+
+	mac := hmac.New(sha256.New, []byte("secreto"))
+	mac.Write([]byte("1700000000." + string(body)))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	assert.Equal(t, expected, services.SignWebhookPayload("secreto", "1700000000", body))
+	assert.NotEqual(t, expected, services.SignWebhookPayload("otro", "1700000000", body))
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, services.WebhookBackoff(1))
+	assert.Equal(t, time.Minute, services.WebhookBackoff(2))
+	assert.Equal(t, 4*time.Minute, services.WebhookBackoff(4))
+	assert.Equal(t, time.Hour, services.WebhookBackoff(20))
+}
+
+func TestValidateWebhook(t *testing.T) {
+	ctx := context.Background()
+	webhook := &models.Webhook{URL: "https://93.184.215.14/hooks/1", EventTypes: []string{models.TaskAssignedEvent, models.BoardDeletedEvent}}
+	assert.NoError(t, services.ValidateWebhook(ctx, webhook))
+
+	webhook.URL = "ftp://example.com"
+	assert.ErrorIs(t, services.ValidateWebhook(ctx, webhook), services.ErrInvalidWebhookURL)
+
+	webhook.URL = "http://93.184.215.14"
+	webhook.EventTypes = []string{"unknown"}
+	assert.ErrorIs(t, services.ValidateWebhook(ctx, webhook), services.ErrInvalidEventType)
+}
+
+func TestValidateWebhook_RejectsInternalTargets(t *testing.T) {
+	urls := []string{
+		"http://localhost:8080/hook",
+		"http://api.localhost/hook",
+		"http://metadata.google.internal/computeMetadata/v1",
+		"http://127.0.0.1/hook",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://100.64.0.1/hook",
+		"http://0.0.0.0/hook",
+		"http://[::1]/hook",
+		"http://[fe80::1]/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+	}
+	for _, target := range urls {
+		webhook := &models.Webhook{URL: target, EventTypes: []string{models.TaskAssignedEvent}}
+		assert.ErrorIs(t, services.ValidateWebhook(context.Background(), webhook), services.ErrWebhookTargetDenied, target)
+	}
+}
+
+func TestWebhookClient_RejectsInternalTargetsAtDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// El servidor escucha en loopback, como un servicio interno al que apunta un DNS que cambió tras validarse
+	_, err := services.NewWebhookClient().Post(server.URL, "application/json", strings.NewReader("{}"))
+
+	assert.ErrorIs(t, err, services.ErrWebhookTargetDenied)
+}
+
+func TestCanManageWebhooks(t *testing.T) {
+	board := &models.Board{
+		OwnerID:   "owner",
+		MemberIDs: []string{"admin", "editor", "viewer"},
+		Members: []models.BoardMember{
+			{UserID: "admin", Role: models.RoleAdmin},
+			{UserID: "editor", Role: "editor"},
+			{UserID: "viewer", Role: "viewer"},
+		},
+	}
+
+	assert.True(t, services.CanManageWebhooks(board, "owner"))
+	assert.True(t, services.CanManageWebhooks(board, "admin"))
+	assert.False(t, services.CanManageWebhooks(board, "editor"))
+	assert.False(t, services.CanManageWebhooks(board, "viewer"))
+	assert.False(t, services.CanManageWebhooks(board, "stranger"))
+
+	// Una proyección guardada antes de tener roles solo deja pasar al owner hasta la siguiente sincronización
+	board.Members = nil
+	assert.False(t, services.CanManageWebhooks(board, "admin"))
+}
+
+func TestWebhookAddrAllowed(t *testing.T) {
+	assert.True(t, services.WebhookAddrAllowed(netip.MustParseAddr("93.184.215.14")))
+	assert.True(t, services.WebhookAddrAllowed(netip.MustParseAddr("2606:2800:220:1::1")))
+	assert.False(t, services.WebhookAddrAllowed(netip.MustParseAddr("172.16.0.1")))
+	assert.False(t, services.WebhookAddrAllowed(netip.MustParseAddr("fd00::1")))
+	assert.False(t, services.WebhookAddrAllowed(netip.MustParseAddr("224.0.0.1")))
+}
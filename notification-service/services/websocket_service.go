@@ -1,32 +1,43 @@
 package services
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
-	"sync"
+	"slices"
+	"time"
 
 	"github.com/gorilla/websocket"
+	"github.com/vadgun/gotrelloclone/notification-service/infra/config"
+	"github.com/vadgun/gotrelloclone/notification-service/models"
 )
 
+// BearerSubprotocol es el subprotocolo con el que el navegador envía el token en el handshake:
+// new WebSocket(url, ["bearer", token])
+const BearerSubprotocol = "bearer"
+
+// maxMessageSize limita lo que el cliente puede enviar, solo se esperan frames de control
+const maxMessageSize = 512
+
 type WebSocketService struct {
-	upgrader  websocket.Upgrader
-	clients   map[*websocket.Conn]bool
-	broadcast chan string
-	mu        sync.Mutex
+	upgrader websocket.Upgrader
+	hub      *Hub
 }
 
-func NewWebSocketService() *WebSocketService {
+func NewWebSocketService(hub *Hub) *WebSocketService {
 	return &WebSocketService{
 		upgrader: websocket.Upgrader{
-			CheckOrigin: func(r *http.Request) bool { return true },
+			CheckOrigin:  checkOrigin,
+			Subprotocols: []string{BearerSubprotocol},
 		},
-		clients:   make(map[*websocket.Conn]bool),
-		broadcast: make(chan string),
+		hub: hub,
 	}
 }
 
-// Maneja conexiones WebSocket
-func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Request) {
+// Maneja conexiones WebSocket del usuario autenticado, la conexión se cierra cuando expira su token.
+// Si se recibe replay, primero se reenvían las notificaciones perdidas y después se pasa a la entrega en vivo.
+func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Request, userID string, expiresAt time.Time, replay ReplayFunc) {
 	conn, err := ws.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Println("Error al actualizar conexión:", err)
@@ -34,40 +45,81 @@ func (ws *WebSocketService) HandleConnections(w http.ResponseWriter, r *http.Req
 	}
 	defer conn.Close()
 
-	ws.mu.Lock()
-	ws.clients[conn] = true
-	ws.mu.Unlock()
-
-	for {
-		_, _, err := conn.ReadMessage()
-		if err != nil {
-			log.Println("Cliente desconectado")
-			ws.mu.Lock()
-			delete(ws.clients, conn)
-			ws.mu.Unlock()
-			break
-		}
+	if !expiresAt.IsZero() {
+		timer := time.AfterFunc(time.Until(expiresAt), func() {
+			log.Printf("Token expirado, cerrando conexión | UserID: %s\n", userID)
+			closeConnection(conn, websocket.ClosePolicyViolation, "token expirado")
+		})
+		defer timer.Stop()
 	}
-}
 
-// Maneja el envío de mensajes a los clientes
-func (ws *WebSocketService) HandleMessages() {
-	for {
-		message := <-ws.broadcast
-		ws.mu.Lock()
-		for client := range ws.clients {
-			err := client.WriteMessage(websocket.TextMessage, []byte(message))
-			if err != nil {
-				log.Println("Error al enviar mensaje:", err)
-				client.Close()
-				delete(ws.clients, client)
+	// La suscripción se registra antes de consultar el backlog para no perder lo que llegue mientras tanto
+	sub := ws.hub.Subscribe(userID)
+	defer ws.hub.Unsubscribe(sub)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	// El cliente no envía mensajes, solo se lee para procesar los pong y detectar la desconexión
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(config.PongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(config.PongWait))
+	})
+	go func() {
+		defer cancel()
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				log.Printf("Cliente desconectado | UserID: %s\n", userID)
+				return
+			}
+		}
+	}()
+
+	// Los ping son frames de control, WriteControl puede llamarse en paralelo con WriteJSON
+	go func() {
+		ticker := time.NewTicker(config.PingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(config.WriteWait)); err != nil {
+					cancel()
+					return
+				}
 			}
 		}
-		ws.mu.Unlock()
+	}()
+
+	// Esta goroutine es la única que escribe mensajes de datos en la conexión
+	err = ws.hub.Stream(ctx, sub, replay, func(notification models.Notification) error {
+		conn.SetWriteDeadline(time.Now().Add(config.WriteWait))
+		return conn.WriteJSON(notification)
+	})
+
+	if errors.Is(err, ErrSlowClient) {
+		closeConnection(conn, websocket.CloseTryAgainLater, "cliente lento")
+		return
 	}
+	if err != nil && ctx.Err() == nil {
+		log.Printf("Error al enviar mensaje | UserID: %s | Error: %v\n", userID, err)
+	}
+}
+
+// closeConnection envía el frame de cierre con el código indicado y cierra la conexión
+func closeConnection(conn *websocket.Conn, code int, reason string) {
+	closeMessage := websocket.FormatCloseMessage(code, reason)
+	conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+	conn.Close()
 }
 
-// Envía un mensaje a todos los clientes WebSocket
-func (ws *WebSocketService) SendMessage(message string) {
-	ws.broadcast <- message
+// checkOrigin acepta clientes sin header Origin (no navegadores) y los orígenes configurados en WS_ALLOWED_ORIGINS
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	return slices.Contains(config.AllowedOrigins, origin)
 }
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/common/etag"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type checklistRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type checklistItemRequest struct {
+	Text       string     `json:"text" binding:"required"`
+	AssigneeID string     `json:"assignee_id"`
+	DueAt      *time.Time `json:"due_at"`
+}
+
+func (req checklistItemRequest) input() services.ChecklistItemInput {
+	return services.ChecklistItemInput{Text: req.Text, AssigneeID: req.AssigneeID, DueAt: req.DueAt}
+}
+
+// 1️⃣6️⃣ Agregar una checklist a la tarea
+func (h *TaskHandler) AddChecklist(ctx *gin.Context) {
+	var req checklistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.AddChecklist(ctx, task, req.Name, version)
+	h.respondChecklist(ctx, http.StatusCreated, updated, err)
+}
+
+// 1️⃣7️⃣ Renombrar una checklist
+func (h *TaskHandler) RenameChecklist(ctx *gin.Context) {
+	var req checklistRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.RenameChecklist(ctx, task, ctx.Param("checklistID"), req.Name, version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 1️⃣8️⃣ Eliminar una checklist con todos sus elementos
+func (h *TaskHandler) DeleteChecklist(ctx *gin.Context) {
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.DeleteChecklist(ctx, task, ctx.Param("checklistID"), version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 1️⃣9️⃣ Agregar un elemento al final de una checklist, opcionalmente asignado y con fecha límite
+func (h *TaskHandler) AddChecklistItem(ctx *gin.Context) {
+	var req checklistItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.AddChecklistItem(ctx, task, ctx.Param("checklistID"), req.input(), version)
+	h.respondChecklist(ctx, http.StatusCreated, updated, err)
+}
+
+// 2️⃣0️⃣ Editar el texto, el asignado y la fecha límite de un elemento
+func (h *TaskHandler) UpdateChecklistItem(ctx *gin.Context) {
+	var req checklistItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.UpdateChecklistItem(ctx, task, ctx.Param("checklistID"), ctx.Param("itemID"), req.input(), version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 2️⃣1️⃣ Marcar o desmarcar un elemento
+func (h *TaskHandler) ToggleChecklistItem(ctx *gin.Context) {
+	var req struct {
+		Done *bool `json:"done" binding:"required"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.ToggleChecklistItem(ctx, task, ctx.Param("checklistID"), ctx.Param("itemID"), *req.Done, version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 2️⃣2️⃣ Reordenar un elemento dentro de su checklist o pasarlo a otra checklist de la tarea
+func (h *TaskHandler) MoveChecklistItem(ctx *gin.Context) {
+	var req struct {
+		ChecklistID string `json:"checklist_id"`
+		BeforeID    string `json:"before_id"`
+		AfterID     string `json:"after_id"`
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	target := services.ItemPlacement{ChecklistID: req.ChecklistID, BeforeID: req.BeforeID, AfterID: req.AfterID}
+	updated, err := h.service.MoveChecklistItem(ctx, task, ctx.Param("checklistID"), ctx.Param("itemID"), target, version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 2️⃣3️⃣ Eliminar un elemento de una checklist
+func (h *TaskHandler) DeleteChecklistItem(ctx *gin.Context) {
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.DeleteChecklistItem(ctx, task, ctx.Param("checklistID"), ctx.Param("itemID"), version)
+	h.respondChecklist(ctx, http.StatusOK, updated, err)
+}
+
+// 2️⃣4️⃣ Convertir un elemento en una tarea de la misma lista, el elemento sale de la checklist
+func (h *TaskHandler) ConvertChecklistItem(ctx *gin.Context) {
+	task, version, ok := h.routeVersionedTask(ctx, models.RoleEditor)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetString("userID")
+	converted, source, err := h.service.ConvertChecklistItem(ctx, task, ctx.Param("checklistID"), ctx.Param("itemID"), userID, version)
+	if errors.Is(err, services.ErrListNotFound) {
+		ctx.JSON(http.StatusConflict, gin.H{"error": "La lista de la tarea ya no admite tareas nuevas"})
+		return
+	}
+	if err != nil {
+		h.respondChecklist(ctx, http.StatusOK, nil, err)
+		return
+	}
+
+	err = h.service.PublishTaskEvent(models.TaskCreatedEvent, models.TaskEvent{Task: *converted, ActorID: userID})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento a Kafka"})
+		return
+	}
+
+	etag.Set(ctx, source.Version)
+	ctx.JSON(http.StatusCreated, gin.H{"message": "El elemento se convirtió en tarea", "task": converted, "source_task": source})
+}
+
+// respondChecklist responde la tarea actualizada con su ETag, o el error del cambio en sus checklists;
+// si el If-Match ya no coincide responde 412 con la tarea actual
+func (h *TaskHandler) respondChecklist(ctx *gin.Context, status int, task *models.Task, err error) {
+	switch {
+	case errors.Is(err, services.ErrChecklistNotFound), errors.Is(err, services.ErrChecklistItemNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, mongo.ErrNoDocuments):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+	case errors.Is(err, services.ErrInvalidChecklistText), errors.Is(err, services.ErrInvalidItemNeighbor),
+		errors.Is(err, services.ErrAssigneeNotMember):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrItemNeighborConflict):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, repositories.ErrVersionConflict):
+		h.respondVersionConflict(ctx, ctx.Param("taskID"))
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar la checklist"})
+	default:
+		etag.Set(ctx, task.Version)
+		ctx.JSON(status, gin.H{"task": task})
+	}
+}
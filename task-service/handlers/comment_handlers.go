@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+type commentRequest struct {
+	Body     string `json:"body" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+// 1️⃣2️⃣ Listar los comentarios de una tarea con sus respuestas
+func (h *TaskHandler) GetComments(ctx *gin.Context) {
+	task, ok := h.routeTask(ctx, models.RoleViewer, false)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.ParseInt(ctx.Query("page"), 10, 64)
+	limit, _ := strconv.ParseInt(ctx.Query("limit"), 10, 64)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	comments, total, err := h.service.GetComments(ctx, task.ID.Hex(), page, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener los comentarios"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"comments":   comments,
+		"total":      total,
+		"page":       page,
+		"totalPages": int(math.Ceil(float64(total) / float64(limit))),
+	})
+}
+
+// 1️⃣3️⃣ Comentar una tarea o responder a un comentario, notifica a los observadores y a los mencionados
+func (h *TaskHandler) CreateComment(ctx *gin.Context) {
+	var req commentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, ok := h.routeTask(ctx, models.RoleEditor, true)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetString("userID")
+	comment, err := h.service.CreateComment(ctx, task, userID, req.ParentID, req.Body)
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El comentario al que respondes no existe"})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidComment) || errors.Is(err, services.ErrNestedReply) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear el comentario"})
+		return
+	}
+
+	event := models.TaskEvent{Task: *task, ActorID: userID, Comment: comment, MentionedIDs: comment.Mentions}
+	if err := h.service.PublishTaskEvent(models.TaskCommentedEvent, event); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de new-comment a Kafka"})
+		return
+	}
+	if !h.publishMentions(ctx, event, comment.Mentions) {
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"message": "Comentario creado", "comment": comment})
+}
+
+// 1️⃣4️⃣ Editar un comentario, solo su autor. Solo se notifica a los usuarios mencionados por primera vez
+func (h *TaskHandler) UpdateComment(ctx *gin.Context) {
+	var req commentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
+		return
+	}
+
+	task, comment, ok := h.taskComment(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetString("userID")
+	updated, newMentions, err := h.service.UpdateComment(ctx, comment, userID, req.Body)
+	if errors.Is(err, services.ErrNotCommentAuthor) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidComment) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Comentario no encontrado"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar el comentario"})
+		return
+	}
+
+	event := models.TaskEvent{Task: *task, ActorID: userID, Comment: updated}
+	if !h.publishMentions(ctx, event, newMentions) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"comment": updated})
+}
+
+// 1️⃣5️⃣ Eliminar un comentario y sus respuestas, lo puede hacer su autor o un admin del board
+func (h *TaskHandler) DeleteComment(ctx *gin.Context) {
+	_, comment, ok := h.taskComment(ctx)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteComment(ctx, comment, ctx.GetString("userID"))
+	if errors.Is(err, repositories.ErrCommentNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Comentario no encontrado"})
+		return
+	}
+	if errors.Is(err, services.ErrBoardNotFound) || errors.Is(err, services.ErrForbidden) {
+		respondBoardAccess(ctx, err)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo eliminar el comentario"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Comentario eliminado correctamente"})
+}
+
+// taskComment obtiene la tarea y el comentario de la ruta para editarlo o eliminarlo
+func (h *TaskHandler) taskComment(ctx *gin.Context) (*models.Task, *models.Comment, bool) {
+	task, ok := h.routeTask(ctx, models.RoleEditor, true)
+	if !ok {
+		return nil, nil, false
+	}
+
+	comment, err := h.service.GetComment(ctx, task.ID.Hex(), ctx.Param("commentID"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Comentario no encontrado"})
+		return nil, nil, false
+	}
+	return task, comment, true
+}
+
+// publishMentions publica mentioned-in-comment para los usuarios mencionados, responde 500 y devuelve false si falla
+func (h *TaskHandler) publishMentions(ctx *gin.Context, event models.TaskEvent, mentions []string) bool {
+	if len(mentions) == 0 {
+		return true
+	}
+
+	event.MentionedIDs = mentions
+	if err := h.service.PublishTaskEvent(models.TaskMentionedEvent, event); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de mentioned-in-comment a Kafka"})
+		return false
+	}
+	return true
+}
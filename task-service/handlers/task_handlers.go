@@ -2,17 +2,20 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/vadgun/gotrelloclone/common/etag"
 	"github.com/vadgun/gotrelloclone/task-service/infra/logger"
 	"github.com/vadgun/gotrelloclone/task-service/infra/metrics"
 	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
 	"github.com/vadgun/gotrelloclone/task-service/services"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.uber.org/zap"
 )
@@ -40,31 +43,39 @@ func (h *TaskHandler) CreateTask(ctx *gin.Context) {
 
 	userID, _ := ctx.Get("userID") // Obtenemos el ID del usuario autenticado
 
-	// 📌 Validar si el BoardID existe antes de crear la tarea
-	boardExists, err := h.service.BoardExists(ctx, task.BoardID)
-	if err != nil {
-		fmt.Println("Error al validar el BoardID")
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al validar el BoardID"})
-		return
-	}
-	if !boardExists {
-		fmt.Println("El Board no existe")
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "El Board no existe"})
+	// 📌 Validar que el Board exista y que el usuario pueda crear tareas en él
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
 		return
 	}
 
 	id, err := h.service.CreateTask(ctx, &task, userID.(string))
+	if errors.Is(err, services.ErrListNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La lista no existe en el board"})
+		return
+	}
+	if errors.Is(err, services.ErrUnknownStatus) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Estado no permitido en el flujo del board"})
+		return
+	}
+	if errors.Is(err, services.ErrAssigneeNotMember) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "El usuario no es miembro del board"})
+		return
+	}
+	if errors.Is(err, services.ErrUnknownLabel) || errors.Is(err, services.ErrInvalidDates) ||
+		errors.Is(err, services.ErrInvalidPriority) || errors.Is(err, services.ErrInvalidEstimate) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		fmt.Println("No se pudo crear la tarea")
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo crear la tarea"})
 		return
 	}
 
-	// Convertir la tarea a JSON
+	// Publicar el evento con la tarea creada
 	task.ID = id.(primitive.ObjectID)
-	taskJSON, _ := json.Marshal(task)
 
-	err = h.service.SendNotification(userID.(string), string(taskJSON), "task-events", "new-task")
+	err = h.service.PublishTaskEvent(models.TaskCreatedEvent, models.TaskEvent{Task: task, ActorID: userID.(string)})
 	if err != nil {
 		fmt.Println("Error enviando evento a Kafka")
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento a Kafka"})
@@ -93,7 +104,26 @@ func (h *TaskHandler) GetTasksByBoardID(ctx *gin.Context) {
 		limit = 10
 	}
 
-	tasks, total, err := h.service.GetTasksByBoardID(ctx, boardID, page, limit)
+	if !h.authorizeBoard(ctx, boardID, models.RoleViewer) {
+		return
+	}
+
+	// Las tareas se ordenan por lista y posición salvo que se indique sort (priority, due, created, updated)
+	// y order (asc o desc). list_id filtra una sola lista, las archivadas solo se incluyen con include_archived=true
+	// y labels deja las que tienen alguna de las etiquetas (separadas por comas), o todas con label_match=all
+	sort, err := services.ParseTaskSort(ctx.Query("sort"), ctx.Query("order"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := models.TaskFilter{
+		ListID:          ctx.Query("list_id"),
+		IncludeArchived: ctx.Query("include_archived") == "true",
+		Labels:          queryList(ctx, "labels"),
+		MatchAllLabels:  ctx.Query("label_match") == "all",
+		Sort:            sort,
+	}
+	tasks, total, err := h.service.GetTasksByBoardID(ctx, boardID, filter, page, limit)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudieron obtener las tareas"})
 		return
@@ -116,52 +146,136 @@ func (h *TaskHandler) GetTaskByID(ctx *gin.Context) {
 		return
 	}
 
+	if !h.authorizeBoard(ctx, task.BoardID, models.RoleViewer) {
+		return
+	}
+
+	etag.Set(ctx, task.Version)
 	ctx.JSON(http.StatusOK, task)
 }
 
 // 4️⃣ Actualizar tarea
 func (h *TaskHandler) UpdateTask(ctx *gin.Context) {
 	taskID := ctx.Param("taskID")
-	var updatedData bson.M
-	if err := ctx.ShouldBindJSON(&updatedData); err != nil {
+	var body map[string]json.RawMessage
+	if err := ctx.ShouldBindJSON(&body); err != nil {
 		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Datos inválidos"})
 		return
 	}
+	if len(body) == 0 {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No hay campos para actualizar"})
+		return
+	}
+
+	// Actualización parcial: solo se aceptan los campos editables y los omitidos no cambian
+	patch, fieldErrors := services.ParseTaskPatch(body)
+	if fieldErrors != nil {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Datos inválidos", "fields": fieldErrors})
+		return
+	}
 
-	err := h.service.UpdateTask(ctx, taskID, updatedData)
+	task, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
+		return
+	}
+
+	// 📌 Solo se guarda si nadie cambió la tarea desde la versión que editó el cliente
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = h.service.UpdateTask(ctx, task, patch, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		h.respondVersionConflict(ctx, taskID)
+		return
+	}
+	if errors.Is(err, services.ErrUnknownLabel) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Datos inválidos", "fields": services.FieldErrors{"labels": err.Error()}})
+		return
+	}
+	if errors.Is(err, services.ErrInvalidDates) {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Datos inválidos", "fields": services.FieldErrors{"start_at": err.Error()}})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar la tarea"})
 		return
 	}
 
-	updatedDataJSON, _ := json.Marshal(updatedData)
-	err = h.service.SendNotification(taskID, string(updatedDataJSON), "task-events", "updated-task")
+	task, err = h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+	etag.Set(ctx, task.Version)
+
+	userID, _ := ctx.Get("userID")
+	err = h.service.PublishTaskEvent(models.TaskUpdatedEvent, models.TaskEvent{Task: *task, ActorID: userID.(string)})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de update-task a Kafka"})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea actualizada y notificacion enviada a kafka con éxito"})
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea actualizada y notificacion enviada a kafka con éxito", "task": task})
 }
 
 // 5️⃣ Eliminar tarea
 func (h *TaskHandler) DeleteTask(ctx *gin.Context) {
 	taskID := ctx.Param("taskID")
 
-	err := h.service.DeleteTask(ctx, taskID)
+	// Se obtiene antes de eliminarla para notificar a los involucrados
+	task, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
+		return
+	}
+
+	// Solo se elimina la versión que vio el cliente, no un cambio que aún no conoce
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = h.service.DeleteTask(ctx, taskID, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		h.respondVersionConflict(ctx, taskID)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo eliminar la tarea"})
 		return
 	}
 
+	userID, _ := ctx.Get("userID")
+	err = h.service.PublishTaskEvent(models.TaskDeletedEvent, models.TaskEvent{Task: *task, ActorID: userID.(string)})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de deleted-task a Kafka"})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea eliminada correctamente"})
 }
 
 // 6️⃣ Mover tarea
 func (h *TaskHandler) MoveTask(ctx *gin.Context) {
 	taskID := ctx.Param("taskID")
+	// Todos los campos son opcionales: sin board ni lista se reordena dentro de la lista actual
+	// y sin vecinos la tarea queda al final de la lista destino
 	var request struct {
 		NewBoardID string `json:"new_board_id"`
+		ListID     string `json:"list_id"`
+		BeforeID   string `json:"before_id"`
+		AfterID    string `json:"after_id"`
 	}
 
 	if err := ctx.ShouldBindJSON(&request); err != nil {
@@ -169,24 +283,61 @@ func (h *TaskHandler) MoveTask(ctx *gin.Context) {
 		return
 	}
 
-	// 📌 Validar si el nuevo `BoardID` existe antes de mover la tarea
-	boardExists, err := h.service.BoardExists(ctx, request.NewBoardID)
+	task, err := h.service.GetTaskByID(ctx, taskID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al validar el nuevo BoardID"})
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
 		return
 	}
-	if !boardExists {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": "El Board destino no existe"})
+
+	// 📌 Mover entre boards requiere permisos de edición en el board origen y en el destino
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
 		return
 	}
+	if request.NewBoardID != "" && request.NewBoardID != task.BoardID && !h.authorizeBoardWrite(ctx, request.NewBoardID, models.RoleEditor) {
+		return
+	}
+	oldBoardID, oldListID := task.BoardID, task.ListID
 
-	err = h.service.MoveTask(ctx, taskID, request.NewBoardID)
-	if err != nil {
+	// 📌 Solo se mueve si nadie cambió la tarea desde la versión que vio el cliente
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = h.service.MoveTask(ctx, task, services.TaskPlacement{
+		BoardID:  request.NewBoardID,
+		ListID:   request.ListID,
+		BeforeID: request.BeforeID,
+		AfterID:  request.AfterID,
+	}, version)
+	switch {
+	case errors.Is(err, services.ErrListNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "La lista no existe en el board"})
+		return
+	case errors.Is(err, services.ErrInvalidNeighbor):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Las tareas vecinas deben estar en la lista destino"})
+		return
+	case errors.Is(err, services.ErrNeighborConflict):
+		// Otro usuario movió tareas de la lista, el cliente debe recargarla y reintentar
+		ctx.JSON(http.StatusConflict, gin.H{"error": "Las tareas cambiaron de posición, vuelve a cargar el tablero"})
+		return
+	case errors.Is(err, repositories.ErrVersionConflict):
+		h.respondVersionConflict(ctx, taskID)
+		return
+	case err != nil:
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo mover la tarea"})
 		return
 	}
+	etag.Set(ctx, task.Version)
 
-	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea movida exitosamente"})
+	userID, _ := ctx.Get("userID")
+	err = h.service.PublishTaskEvent(models.TaskMovedEvent, models.TaskEvent{Task: *task, ActorID: userID.(string), OldBoardID: oldBoardID, OldListID: oldListID})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de moved-task a Kafka"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea movida exitosamente", "task": task})
 }
 
 // 7️⃣ Asignar tarea a usuario
@@ -212,12 +363,56 @@ func (h *TaskHandler) AssignTask(ctx *gin.Context) {
 		return
 	}
 
-	err = h.service.AssignTask(ctx, taskID, request.UserID)
+	task, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
+		return
+	}
+
+	// 📌 Solo se puede asignar a miembros del board
+	isMember, err := h.service.IsBoardMember(ctx, task.BoardID, request.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al validar el usuario"})
+		return
+	}
+	if !isMember {
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": "El usuario no es miembro del board"})
+		return
+	}
+
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	err = h.service.AssignTask(ctx, taskID, request.UserID, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		h.respondVersionConflict(ctx, taskID)
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo asignar la tarea"})
 		return
 	}
 
+	task, err = h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+	etag.Set(ctx, task.Version)
+
+	userID, _ := ctx.Get("userID")
+	err = h.service.PublishTaskEvent(models.TaskAssignedEvent, models.TaskEvent{Task: *task, ActorID: userID.(string)})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de assigned-task a Kafka"})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea asignada exitosamente"})
 }
 
@@ -233,27 +428,43 @@ func (h *TaskHandler) UpdateTaskStatus(ctx *gin.Context) {
 		return
 	}
 
-	// Validar si el estado es válido
-	validStatuses := map[models.TaskStatus]bool{
-		models.TODO:       true,
-		models.INPROGRESS: true,
-		models.DONE:       true,
+	task, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
 	}
+	oldStatus := task.Status
 
-	if !validStatuses[request.Status] {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Estado no permitido"})
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
 		return
 	}
 
-	err := h.service.UpdateTaskStatus(ctx, taskID, request.Status)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar el estado en la base de datos"})
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
 		return
 	}
 
-	taskStatusJSON, _ := json.Marshal(request.Status)
+	// El estado y la transición se validan contra el flujo del board de la tarea
+	err = h.service.UpdateTaskStatus(ctx, task, request.Status, version)
+	var transitionErr *services.TransitionError
+	switch {
+	case errors.Is(err, services.ErrUnknownStatus):
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Estado no permitido en el flujo del board"})
+		return
+	case errors.As(err, &transitionErr):
+		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": transitionErr.Error(), "allowed_statuses": transitionErr.Allowed})
+		return
+	case errors.Is(err, repositories.ErrVersionConflict):
+		h.respondVersionConflict(ctx, taskID)
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo actualizar el estado en la base de datos"})
+		return
+	}
+	etag.Set(ctx, task.Version)
 
-	err = h.service.SendNotification(taskID, string(taskStatusJSON), "task-events", "update-task-status")
+	userID, _ := ctx.Get("userID")
+	err = h.service.PublishTaskEvent(models.TaskStatusUpdatedEvent, models.TaskEvent{Task: *task, ActorID: userID.(string), OldStatus: oldStatus})
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de update-task-status a Kafka"})
 		return
@@ -261,6 +472,104 @@ func (h *TaskHandler) UpdateTaskStatus(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Estado de la tarea y notificacion enviada a kafka con éxito"})
 }
 
+// 9️⃣ Listar la papelera de un board, solo el owner
+func (h *TaskHandler) GetTrash(ctx *gin.Context) {
+	boardID := ctx.Param("boardID")
+	if !h.authorizeBoard(ctx, boardID, models.RoleOwner) {
+		return
+	}
+
+	tasks, err := h.service.GetTrash(ctx, boardID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo obtener la papelera"})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"tasks": tasks})
+}
+
+// 🔟 Restaurar una tarea de la papelera, solo el owner del board
+func (h *TaskHandler) RestoreTask(ctx *gin.Context) {
+	task, err := h.service.GetTrashedTask(ctx, ctx.Param("taskID"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada en la papelera"})
+		return
+	}
+
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleOwner) {
+		return
+	}
+
+	restored, err := h.service.RestoreTask(ctx, task)
+	if errors.Is(err, services.ErrTaskNotTrashed) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada en la papelera"})
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo restaurar la tarea"})
+		return
+	}
+
+	err = h.service.PublishTaskEvent(models.TaskRestoredEvent, models.TaskEvent{Task: *restored, ActorID: ctx.GetString("userID")})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de restored-task a Kafka"})
+		return
+	}
+
+	etag.Set(ctx, restored.Version)
+	ctx.JSON(http.StatusOK, gin.H{"message": "Tarea restaurada correctamente", "task": restored})
+}
+
+// 1️⃣1️⃣ Archivar o desarchivar una tarea, archivada no aparece en el listado del board
+func (h *TaskHandler) ArchiveTask(ctx *gin.Context) {
+	h.archiveTask(ctx, true)
+}
+
+func (h *TaskHandler) UnarchiveTask(ctx *gin.Context) {
+	h.archiveTask(ctx, false)
+}
+
+func (h *TaskHandler) archiveTask(ctx *gin.Context, archived bool) {
+	taskID := ctx.Param("taskID")
+	task, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+
+	if !h.authorizeBoardWrite(ctx, task.BoardID, models.RoleEditor) {
+		return
+	}
+
+	version, ok := etag.IfMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	updated, err := h.service.ArchiveTask(ctx, taskID, archived, version)
+	if errors.Is(err, repositories.ErrVersionConflict) {
+		h.respondVersionConflict(ctx, taskID)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "No se pudo archivar la tarea"})
+		return
+	}
+
+	key := models.TaskArchivedEvent
+	if !archived {
+		key = models.TaskUnarchivedEvent
+	}
+	err = h.service.PublishTaskEvent(key, models.TaskEvent{Task: *updated, ActorID: ctx.GetString("userID")})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error enviando evento de " + key + " a Kafka"})
+		return
+	}
+
+	etag.Set(ctx, updated.Version)
+	ctx.JSON(http.StatusOK, gin.H{"task": updated})
+}
+
 func (h *TaskHandler) GetAllUsers(ctx *gin.Context) {
 	tasks, err := h.service.GetAllTasks()
 	if err != nil {
@@ -270,3 +579,85 @@ func (h *TaskHandler) GetAllUsers(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, tasks)
 
 }
+
+// routeTask obtiene la tarea de la ruta y valida que el usuario tenga al menos el rol indicado en su board,
+// con write además responde 409 si el board está archivado
+func (h *TaskHandler) routeTask(ctx *gin.Context, role models.BoardRole, write bool) (*models.Task, bool) {
+	task, err := h.service.GetTaskByID(ctx, ctx.Param("taskID"))
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return nil, false
+	}
+
+	authorized := false
+	if write {
+		authorized = h.authorizeBoardWrite(ctx, task.BoardID, role)
+	} else {
+		authorized = h.authorizeBoard(ctx, task.BoardID, role)
+	}
+	return task, authorized
+}
+
+// routeVersionedTask es routeTask para las modificaciones que exigen If-Match, devuelve además la versión que editó el cliente
+func (h *TaskHandler) routeVersionedTask(ctx *gin.Context, role models.BoardRole) (*models.Task, int64, bool) {
+	task, ok := h.routeTask(ctx, role, true)
+	if !ok {
+		return nil, 0, false
+	}
+	version, ok := etag.IfMatchVersion(ctx)
+	return task, version, ok
+}
+
+// authorizeBoard responde 404 o 403 y devuelve false si el usuario autenticado no es miembro del board
+// con al menos el rol indicado
+func (h *TaskHandler) authorizeBoard(ctx *gin.Context, boardID string, role models.BoardRole) bool {
+	return respondBoardAccess(ctx, h.service.Authorize(ctx, boardID, ctx.GetString("userID"), role))
+}
+
+// authorizeBoardWrite es authorizeBoard para las operaciones que modifican tareas, responde 409 si el board está archivado
+func (h *TaskHandler) authorizeBoardWrite(ctx *gin.Context, boardID string, role models.BoardRole) bool {
+	return respondBoardAccess(ctx, h.service.AuthorizeWrite(ctx, boardID, ctx.GetString("userID"), role))
+}
+
+func respondBoardAccess(ctx *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, services.ErrBoardNotFound):
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "El Board no existe"})
+		return false
+	case errors.Is(err, services.ErrForbidden):
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "No tienes permisos sobre el board"})
+		return false
+	case errors.Is(err, services.ErrBoardArchived):
+		ctx.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return false
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Error al validar el BoardID"})
+		return false
+	}
+	return true
+}
+
+// queryList junta los valores del parámetro, repetido o separado por comas, sin valores vacíos
+func queryList(ctx *gin.Context, name string) []string {
+	var values []string
+	for _, param := range ctx.QueryArray(name) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
+// respondVersionConflict responde 412 con la versión actual de la tarea para que el cliente combine los cambios
+func (h *TaskHandler) respondVersionConflict(ctx *gin.Context, taskID string) {
+	current, err := h.service.GetTaskByID(ctx, taskID)
+	if err != nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "Tarea no encontrada"})
+		return
+	}
+
+	etag.Set(ctx, current.Version)
+	ctx.JSON(http.StatusPreconditionFailed, gin.H{"error": "La tarea fue modificada por otro usuario", "task": current})
+}
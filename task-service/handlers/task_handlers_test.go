@@ -0,0 +1,319 @@
+package handlers_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/handlers"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// setupRouter registra las rutas versionadas de tareas y checklists con el usuario "editor" ya autenticado
+func setupRouter(mockRepo *repomocks.MockTaskRepo) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(services.NewTaskService(mockRepo))
+
+	r := gin.New()
+	r.Use(func(ctx *gin.Context) {
+		ctx.Set("userID", "editor")
+		ctx.Next()
+	})
+	r.PUT("/tasks/:taskID", handler.UpdateTask)
+	r.PUT("/tasks/:taskID/move", handler.MoveTask)
+	r.PUT("/tasks/:taskID/assign", handler.AssignTask)
+	r.PUT("/tasks/:taskID/status", handler.UpdateTaskStatus)
+	r.PUT("/tasks/:taskID/archive", handler.ArchiveTask)
+	r.PUT("/tasks/:taskID/unarchive", handler.UnarchiveTask)
+	r.DELETE("/tasks/:taskID", handler.DeleteTask)
+	r.PUT("/tasks/:taskID/checklists/:checklistID", handler.RenameChecklist)
+	r.PUT("/tasks/:taskID/checklists/:checklistID/items/:itemID/toggle", handler.ToggleChecklistItem)
+	r.DELETE("/tasks/:taskID/checklists/:checklistID/items/:itemID", handler.DeleteChecklistItem)
+	return r
+}
+
+// versionedTask prepara una tarea en la versión 3 de un board donde "editor" y "assignee" son miembros
+func versionedTask(mockRepo *repomocks.MockTaskRepo, boardArchived bool) *models.Task {
+	board := &models.Board{
+		ID:       primitive.NewObjectID(),
+		OwnerID:  "owner",
+		Archived: boardArchived,
+		Members: []models.BoardMember{
+			{UserID: "owner", Role: models.RoleOwner},
+			{UserID: "editor", Role: models.RoleEditor},
+			{UserID: "assignee", Role: models.RoleViewer},
+		},
+	}
+	task := &models.Task{
+		ID:      primitive.NewObjectID(),
+		Title:   "Tarea",
+		BoardID: board.ID.Hex(),
+		ListID:  primitive.NewObjectID().Hex(),
+		Status:  "TODO",
+		Version: 3,
+	}
+	mockRepo.On("GetBoardByID", board.ID.Hex()).Return(board, nil)
+	return task
+}
+
+func sendVersioned(router *gin.Engine, method, path, body, ifMatch string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+// versionedRoutes son las mutaciones que exigen If-Match, con la llamada al repositorio que aplica el cambio
+var versionedRoutes = []struct {
+	name   string
+	method string
+	path   string
+	body   string
+	expect func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error)
+}{
+	{
+		name:   "update",
+		method: http.MethodPut,
+		body:   `{"title": "Nuevo título"}`,
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("UpdateTask", mock.Anything, task.ID.Hex(), mock.Anything, int64(3)).Return(result)
+		},
+	},
+	{
+		name:   "move",
+		method: http.MethodPut,
+		path:   "/move",
+		body:   `{}`,
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("GetNeighborTask", mock.Anything, task.BoardID, task.ListID, mock.Anything, false, task.ID).Return(nil, nil)
+			mockRepo.On("UpdateTaskPlacement", mock.Anything, task.ID, int64(3), task.BoardID, task.ListID, services.PositionStep, task.Status, false).Return(result)
+			mockRepo.On("GetTaskIDsAtPosition", mock.Anything, task.BoardID, task.ListID, services.PositionStep, task.ID).Return([]primitive.ObjectID{}, nil)
+		},
+	},
+	{
+		name:   "assign",
+		method: http.MethodPut,
+		path:   "/assign",
+		body:   `{"user_id": "assignee"}`,
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("GetUserByID", "assignee").Return(&models.User{}, nil)
+			mockRepo.On("UpdateTaskAssignee", mock.Anything, task.ID.Hex(), "assignee", int64(3)).Return(result)
+		},
+	},
+	{
+		name:   "status",
+		method: http.MethodPut,
+		path:   "/status",
+		body:   `{"status": "IN_PROGRESS"}`,
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("UpdateTaskStatus", mock.Anything, task.ID.Hex(), models.TaskStatus("IN_PROGRESS"), int64(3)).Return(result)
+		},
+	},
+	{
+		name:   "archive",
+		method: http.MethodPut,
+		path:   "/archive",
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("SetTaskArchived", mock.Anything, task.ID.Hex(), true, int64(3)).Return(result)
+		},
+	},
+	{
+		name:   "unarchive",
+		method: http.MethodPut,
+		path:   "/unarchive",
+		expect: func(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+			mockRepo.On("SetTaskArchived", mock.Anything, task.ID.Hex(), false, int64(3)).Return(result)
+		},
+	},
+	{
+		name:   "rename checklist",
+		method: http.MethodPut,
+		path:   "/checklists/" + checklistID.Hex(),
+		body:   `{"name": "Pendientes"}`,
+		expect: expectChecklists,
+	},
+	{
+		name:   "toggle checklist item",
+		method: http.MethodPut,
+		path:   "/checklists/" + checklistID.Hex() + "/items/" + itemID.Hex() + "/toggle",
+		body:   `{"done": true}`,
+		expect: expectChecklists,
+	},
+	{
+		name:   "delete checklist item",
+		method: http.MethodDelete,
+		path:   "/checklists/" + checklistID.Hex() + "/items/" + itemID.Hex(),
+		expect: expectChecklists,
+	},
+}
+
+// checklistID e itemID identifican la checklist que expectChecklists agrega a la tarea
+var checklistID, itemID = primitive.NewObjectID(), primitive.NewObjectID()
+
+// expectChecklists agrega una checklist a la tarea y espera que se guarde con la versión del If-Match, sin reintentos
+func expectChecklists(mockRepo *repomocks.MockTaskRepo, task *models.Task, result error) {
+	task.Checklists = []models.Checklist{{ID: checklistID, Name: "Checklist", Items: []models.ChecklistItem{{ID: itemID, Text: "Elemento"}}}}
+	mockRepo.On("SetTaskChecklists", mock.Anything, task.ID, mock.Anything, int64(3)).Return(result).Once()
+}
+
+func TestTaskHandler_VersionedMutations_MissingIfMatch(t *testing.T) {
+	for _, route := range versionedRoutes {
+		mockRepo := new(repomocks.MockTaskRepo)
+		task := versionedTask(mockRepo, false)
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil)
+		mockRepo.On("GetUserByID", mock.Anything).Return(&models.User{}, nil)
+
+		resp := sendVersioned(setupRouter(mockRepo), route.method, "/tasks/"+task.ID.Hex()+route.path, route.body, "")
+
+		// Sin If-Match no se aplica ningún cambio
+		assert.Equal(t, http.StatusPreconditionRequired, resp.Code, route.name)
+		assertNoMutation(t, mockRepo, route.name)
+	}
+}
+
+func TestTaskHandler_VersionedMutations_StaleIfMatch(t *testing.T) {
+	for _, route := range versionedRoutes {
+		mockRepo := new(repomocks.MockTaskRepo)
+		task := versionedTask(mockRepo, false)
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		route.expect(mockRepo, task, repositories.ErrVersionConflict)
+
+		// Otro usuario ya dejó la tarea en la versión 4
+		current := *task
+		current.Title, current.Version = "Título de otro usuario", 4
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&current, nil)
+
+		resp := sendVersioned(setupRouter(mockRepo), route.method, "/tasks/"+task.ID.Hex()+route.path, route.body, `"3"`)
+
+		assert.Equal(t, http.StatusPreconditionFailed, resp.Code, route.name)
+		assert.Equal(t, `"4"`, resp.Header().Get("ETag"), route.name)
+		assert.Contains(t, resp.Body.String(), "Título de otro usuario", route.name)
+	}
+}
+
+func TestTaskHandler_VersionedMutations_MatchingIfMatch(t *testing.T) {
+	for _, route := range versionedRoutes {
+		mockRepo := new(repomocks.MockTaskRepo)
+		task := versionedTask(mockRepo, false)
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		route.expect(mockRepo, task, nil)
+
+		updated := *task
+		updated.Version = 4
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&updated, nil)
+
+		resp := sendVersioned(setupRouter(mockRepo), route.method, "/tasks/"+task.ID.Hex()+route.path, route.body, `"3"`)
+
+		assert.Equal(t, http.StatusOK, resp.Code, route.name)
+		assert.Equal(t, `"4"`, resp.Header().Get("ETag"), route.name)
+		mockRepo.AssertExpectations(t)
+	}
+}
+
+// mutationMethods son las llamadas al repositorio que modifican la tarea
+var mutationMethods = []string{"UpdateTask", "UpdateTaskPlacement", "UpdateTaskAssignee", "UpdateTaskStatus", "SetTaskArchived", "SetTaskChecklists", "DeleteTask"}
+
+// assertNoMutation falla si la petición llegó a modificar la tarea en el repositorio
+func assertNoMutation(t *testing.T, mockRepo *repomocks.MockTaskRepo, route string) {
+	for _, call := range mockRepo.Calls {
+		assert.NotContains(t, mutationMethods, call.Method, route)
+	}
+}
+
+func TestTaskHandler_Mutations_ArchivedBoard(t *testing.T) {
+	routes := []struct{ name, method, path, body string }{{"delete", http.MethodDelete, "", ""}}
+	for _, route := range versionedRoutes {
+		routes = append(routes, struct{ name, method, path, body string }{route.name, route.method, route.path, route.body})
+	}
+
+	for _, route := range routes {
+		mockRepo := new(repomocks.MockTaskRepo)
+		task := versionedTask(mockRepo, true)
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil)
+		mockRepo.On("GetUserByID", mock.Anything).Return(&models.User{}, nil)
+
+		resp := sendVersioned(setupRouter(mockRepo), route.method, "/tasks/"+task.ID.Hex()+route.path, route.body, `"3"`)
+
+		// Las tareas de un board archivado solo se consultan, aunque el If-Match coincida
+		assert.Equal(t, http.StatusConflict, resp.Code, route.name)
+		assert.Contains(t, resp.Body.String(), services.ErrBoardArchived.Error(), route.name)
+		assertNoMutation(t, mockRepo, route.name)
+	}
+}
+
+func TestTaskHandler_ArchiveTask(t *testing.T) {
+	for _, archived := range []bool{true, false} {
+		mockRepo := new(repomocks.MockTaskRepo)
+		task := versionedTask(mockRepo, false)
+		task.Archived = !archived
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+		mockRepo.On("SetTaskArchived", mock.Anything, task.ID.Hex(), archived, int64(3)).Return(nil)
+
+		updated := *task
+		updated.Archived, updated.Version = archived, 4
+		mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&updated, nil)
+
+		path := "/archive"
+		if !archived {
+			path = "/unarchive"
+		}
+		resp := sendVersioned(setupRouter(mockRepo), http.MethodPut, "/tasks/"+task.ID.Hex()+path, "", `"3"`)
+
+		assert.Equal(t, http.StatusOK, resp.Code, path)
+		assert.Equal(t, `"4"`, resp.Header().Get("ETag"), path)
+		mockRepo.AssertExpectations(t)
+	}
+}
+
+func TestTaskHandler_DeleteTask_MissingIfMatch(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	task := versionedTask(mockRepo, false)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil)
+
+	resp := sendVersioned(setupRouter(mockRepo), http.MethodDelete, "/tasks/"+task.ID.Hex(), "", "")
+
+	assert.Equal(t, http.StatusPreconditionRequired, resp.Code)
+	assertNoMutation(t, mockRepo, "delete")
+}
+
+func TestTaskHandler_DeleteTask_StaleIfMatch(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	task := versionedTask(mockRepo, false)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil).Once()
+	mockRepo.On("DeleteTask", mock.Anything, task.ID.Hex(), int64(3)).Return(repositories.ErrVersionConflict)
+
+	// Otro usuario editó la tarea después de que el cliente la viera, no se elimina sin que conozca ese cambio
+	current := *task
+	current.Title, current.Version = "Título de otro usuario", 4
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&current, nil)
+
+	resp := sendVersioned(setupRouter(mockRepo), http.MethodDelete, "/tasks/"+task.ID.Hex(), "", `"3"`)
+
+	assert.Equal(t, http.StatusPreconditionFailed, resp.Code)
+	assert.Equal(t, `"4"`, resp.Header().Get("ETag"))
+	assert.Contains(t, resp.Body.String(), "Título de otro usuario")
+}
+
+func TestTaskHandler_DeleteTask_MatchingIfMatch(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	task := versionedTask(mockRepo, false)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(task, nil)
+	mockRepo.On("DeleteTask", mock.Anything, task.ID.Hex(), int64(3)).Return(nil)
+
+	resp := sendVersioned(setupRouter(mockRepo), http.MethodDelete, "/tasks/"+task.ID.Hex(), "", `"3"`)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	mockRepo.AssertExpectations(t)
+}
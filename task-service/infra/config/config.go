@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,6 +16,19 @@ import (
 var DB *mongo.Database
 var JWTSecret string
 
+// Papelera de tareas
+var (
+	TrashRetention     time.Duration // Tiempo que una tarea permanece en la papelera antes de eliminarse definitivamente
+	TrashPurgeInterval time.Duration // Cada cuánto se buscan tareas con la retención cumplida
+)
+
+// Recordatorios de fecha límite
+var (
+	ReminderOffsets  []time.Duration // Antelaciones con las que se publica task-due-soon, por ejemplo 24h y 1h
+	ReminderInterval time.Duration   // Cada cuánto se buscan tareas con recordatorios pendientes
+	ReminderLease    time.Duration   // Tiempo que una réplica aparta una tarea mientras publica su recordatorio
+)
+
 type JWTClaims struct {
 	jwt.RegisteredClaims
 	UserID   string `json:"user_id"`
@@ -33,6 +47,12 @@ func InitConfig() {
 		log.Fatal("Faltan variables de entorno necesarias")
 	}
 
+	TrashRetention = durationEnv("TRASH_RETENTION", 30*24*time.Hour)
+	TrashPurgeInterval = durationEnv("TRASH_PURGE_INTERVAL", time.Hour)
+	ReminderOffsets = durationListEnv("REMINDER_OFFSETS", []time.Duration{24 * time.Hour, time.Hour})
+	ReminderInterval = durationEnv("REMINDER_INTERVAL", time.Minute)
+	ReminderLease = durationEnv("REMINDER_LEASE", 2*time.Minute)
+
 	// Configurar conexión a MongoDB
 	clientOptions := options.Client().ApplyURI(mongoURI)
 	client, err := mongo.Connect(context.TODO(), clientOptions)
@@ -52,3 +72,25 @@ func InitConfig() {
 	DB = client.Database(dbName)
 	fmt.Println("✅ Conectado a MongoDB desde Task-service:", dbName)
 }
+
+// durationEnv obtiene una duración (por ejemplo "720h") de las variables de entorno o el valor por defecto
+func durationEnv(name string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// durationListEnv obtiene una lista de duraciones separadas por comas (por ejemplo "24h,1h") o el valor por defecto
+func durationListEnv(name string, defaultValue []time.Duration) []time.Duration {
+	var values []time.Duration
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		value, err := time.ParseDuration(strings.TrimSpace(item))
+		if err != nil || value <= 0 {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
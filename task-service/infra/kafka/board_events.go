@@ -0,0 +1,182 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/infra/metrics"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BoardTrashEvent es el contenido de los eventos trashed-board, restored-board y drop-board de board-service
+type BoardTrashEvent struct {
+	ID        primitive.ObjectID `json:"id"`
+	ActorID   string             `json:"actor_id"`
+	Version   int64              `json:"version"`
+	DeletedAt *time.Time         `json:"deleted_at,omitempty"`
+}
+
+// BoardArchiveEvent es el contenido de los eventos archived-board y unarchived-board de board-service
+type BoardArchiveEvent struct {
+	ID       primitive.ObjectID `json:"id"`
+	Version  int64              `json:"version"`
+	Archived bool               `json:"archived"`
+}
+
+// BoardTasksTrashedEvent es la key con la que se informa en task-events cuántas tareas están en la papelera con el tablero
+const BoardTasksTrashedEvent = "trashed-board-tasks"
+
+// BoardTasksEvent es el contenido de trashed-board-tasks, Version es la del trashed-board que lo originó
+// para que board-service descarte el conteo si el tablero ya se restauró
+type BoardTasksEvent struct {
+	BoardID string `json:"board_id"`
+	Version int64  `json:"version"`
+	Tasks   int64  `json:"tasks"`
+}
+
+// TrashBoard envía a la papelera las tareas del tablero eliminado y devuelve cuántas se enviaron.
+// Cada tarea se marca y notifica por separado: si el evento se vuelve a recibir, las tareas que ya están
+// en la papelera no se notifican otra vez. Un trashed-board anterior a un restored-board ya aplicado se ignora.
+// Al terminar informa a board-service el total de tareas en la papelera con el tablero, que no cambia si el evento se repite.
+func TrashBoard(repo repositories.BoardEventsRepositoryInterface, board BoardTrashEvent) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	deletedAt := time.Now()
+	if board.DeletedAt != nil {
+		deletedAt = *board.DeletedAt
+	}
+	applied, err := repo.SetBoardTrashed(ctx, board.ID, &deletedAt, board.Version)
+	if err != nil || !applied {
+		return 0, err
+	}
+
+	boardID := board.ID.Hex()
+	tasks, err := repo.GetTasksByBoard(ctx, boardID)
+	if err != nil {
+		return 0, err
+	}
+
+	trashed, err := publishEach(tasks, board.ActorID, models.TaskDeletedEvent, func(task models.Task) (bool, error) {
+		return repo.TrashBoardTask(ctx, boardID, task.ID, deletedAt)
+	})
+	metrics.BoardCleanupTasksTotal.Add(float64(trashed))
+	if err != nil {
+		return trashed, err
+	}
+
+	total, err := repo.CountTasksTrashedWithBoard(ctx, boardID)
+	if err != nil {
+		return trashed, err
+	}
+	eventJSON, err := json.Marshal(BoardTasksEvent{BoardID: boardID, Version: board.Version, Tasks: total})
+	if err != nil {
+		return trashed, err
+	}
+	if err := ProduceMessage(board.ActorID, string(eventJSON), "task-events", BoardTasksTrashedEvent); err != nil {
+		log.Printf("⚠️ Error enviando evento de %s para el board %s: %v\n", BoardTasksTrashedEvent, boardID, err)
+	}
+	return trashed, nil
+}
+
+// RestoreBoard saca de la papelera las tareas que se eliminaron junto con el tablero y devuelve cuántas se restauraron.
+// Un restored-board anterior a un trashed-board ya aplicado se ignora.
+func RestoreBoard(repo repositories.BoardEventsRepositoryInterface, board BoardTrashEvent) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	applied, err := repo.SetBoardTrashed(ctx, board.ID, nil, board.Version)
+	if err != nil || !applied {
+		return 0, err
+	}
+
+	tasks, err := repo.GetTasksTrashedWithBoard(ctx, board.ID.Hex())
+	if err != nil {
+		return 0, err
+	}
+
+	return publishEach(tasks, board.ActorID, models.TaskRestoredEvent, func(task models.Task) (bool, error) {
+		return repo.RestoreTask(ctx, task.ID, true)
+	})
+}
+
+// DeleteLabel marca la etiqueta como eliminada en la copia local y la quita de todas las tareas del tablero,
+// devuelve cuántas tareas cambiaron. Si el evento se vuelve a recibir no queda ninguna tarea por cambiar.
+func DeleteLabel(repo repositories.BoardEventsRepositoryInterface, label models.Label) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	label.Deleted = true
+	if err := repo.SaveLabel(ctx, &label); err != nil {
+		return 0, err
+	}
+	return repo.DetachLabel(ctx, label.BoardID, label.ID)
+}
+
+// CleanupBoard elimina definitivamente las tareas, listas, etiquetas y la copia local de un tablero purgado de la papelera
+// y devuelve cuántas tareas se eliminaron. Solo se notifican las tareas que no estaban en la papelera,
+// las demás ya se notificaron al enviarse a la papelera.
+func CleanupBoard(repo repositories.BoardEventsRepositoryInterface, board BoardTrashEvent) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	boardID := board.ID.Hex()
+	tasks, err := repo.GetTasksByBoard(ctx, boardID)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := publishEach(tasks, board.ActorID, models.TaskDeletedEvent, func(task models.Task) (bool, error) {
+		return repo.DeleteBoardTask(ctx, boardID, task.ID)
+	})
+	metrics.BoardCleanupTasksTotal.Add(float64(deleted))
+	if err != nil {
+		return deleted, err
+	}
+
+	trashed, err := repo.DeleteTasksByBoard(ctx, boardID)
+	deleted += int(trashed)
+	if err != nil {
+		return deleted, err
+	}
+
+	if err := repo.DeleteListsByBoard(boardID); err != nil {
+		return deleted, err
+	}
+	if err := repo.DeleteLabelsByBoard(ctx, boardID); err != nil {
+		return deleted, err
+	}
+	if err := repo.DeleteBoard(ctx, board.ID); err != nil {
+		return deleted, fmt.Errorf("error eliminando el tablero %s: %w", boardID, err)
+	}
+	return deleted, nil
+}
+
+// publishEach aplica apply a cada tarea y publica key solo para las que cambiaron, devuelve cuántas cambiaron
+func publishEach(tasks []models.Task, actorID, key string, apply func(models.Task) (bool, error)) (int, error) {
+	changed := 0
+	for _, task := range tasks {
+		ok, err := apply(task)
+		if err != nil {
+			return changed, err
+		}
+		if !ok {
+			continue
+		}
+		changed++
+
+		eventJSON, err := json.Marshal(models.TaskEvent{Task: task, ActorID: actorID})
+		if err != nil {
+			return changed, err
+		}
+		if err := ProduceMessage(actorID, string(eventJSON), "task-events", key); err != nil {
+			log.Printf("⚠️ Error enviando evento de %s para la tarea %s: %v\n", key, task.ID.Hex(), err)
+		}
+	}
+	return changed, nil
+}
@@ -0,0 +1,138 @@
+package kafka_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// trashEvent es un trashed-board, restored-board o drop-board de un tablero nuevo
+func trashEvent() kafka.BoardTrashEvent {
+	deletedAt := time.Now()
+	return kafka.BoardTrashEvent{ID: primitive.NewObjectID(), ActorID: "owner", Version: 3, DeletedAt: &deletedAt}
+}
+
+func boardTasks(boardID string, n int) []models.Task {
+	tasks := make([]models.Task, n)
+	for i := range tasks {
+		tasks[i] = models.Task{ID: primitive.NewObjectID(), BoardID: boardID}
+	}
+	return tasks
+}
+
+func TestTrashBoard_TrashesBoardTasks(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+	boardID := board.ID.Hex()
+	tasks := boardTasks(boardID, 3)
+
+	mockRepo.On("SetBoardTrashed", mock.Anything, board.ID, board.DeletedAt, board.Version).Return(true, nil)
+	mockRepo.On("GetTasksByBoard", mock.Anything, boardID).Return(tasks, nil)
+	mockRepo.On("TrashBoardTask", mock.Anything, boardID, tasks[0].ID, *board.DeletedAt).Return(true, nil)
+	mockRepo.On("TrashBoardTask", mock.Anything, boardID, tasks[1].ID, *board.DeletedAt).Return(true, nil)
+	// Otra entrega del evento ya la envió a la papelera, no cuenta ni se notifica otra vez
+	mockRepo.On("TrashBoardTask", mock.Anything, boardID, tasks[2].ID, *board.DeletedAt).Return(false, nil)
+	mockRepo.On("CountTasksTrashedWithBoard", mock.Anything, boardID).Return(int64(3), nil)
+
+	trashed, err := kafka.TrashBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, trashed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTrashBoard_WithoutBoardProjection(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+	boardID := board.ID.Hex()
+	tasks := boardTasks(boardID, 2)
+
+	// El trashed-board llegó antes que new-board: SetBoardTrashed crea la copia local y las tareas igual se envían a la papelera
+	mockRepo.On("SetBoardTrashed", mock.Anything, board.ID, board.DeletedAt, board.Version).Return(true, nil)
+	mockRepo.On("GetTasksByBoard", mock.Anything, boardID).Return(tasks, nil)
+	mockRepo.On("TrashBoardTask", mock.Anything, boardID, tasks[0].ID, *board.DeletedAt).Return(true, nil)
+	mockRepo.On("TrashBoardTask", mock.Anything, boardID, tasks[1].ID, *board.DeletedAt).Return(true, nil)
+	mockRepo.On("CountTasksTrashedWithBoard", mock.Anything, boardID).Return(int64(2), nil)
+
+	trashed, err := kafka.TrashBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, trashed)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTrashBoard_IgnoresStaleEvent(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+
+	// Un restored-board más nuevo ya se aplicó, las tareas no se tocan ni se informa el conteo
+	mockRepo.On("SetBoardTrashed", mock.Anything, board.ID, board.DeletedAt, board.Version).Return(false, nil)
+
+	trashed, err := kafka.TrashBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Zero(t, trashed)
+	mockRepo.AssertNotCalled(t, "GetTasksByBoard", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "CountTasksTrashedWithBoard", mock.Anything, mock.Anything)
+}
+
+func TestRestoreBoard_RestoresTasksTrashedWithBoard(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+	board.DeletedAt = nil
+	boardID := board.ID.Hex()
+	tasks := boardTasks(boardID, 2)
+
+	mockRepo.On("SetBoardTrashed", mock.Anything, board.ID, (*time.Time)(nil), board.Version).Return(true, nil)
+	mockRepo.On("GetTasksTrashedWithBoard", mock.Anything, boardID).Return(tasks, nil)
+	mockRepo.On("RestoreTask", mock.Anything, tasks[0].ID, true).Return(true, nil)
+	mockRepo.On("RestoreTask", mock.Anything, tasks[1].ID, true).Return(true, nil)
+
+	restored, err := kafka.RestoreBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, restored)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreBoard_IgnoresStaleEvent(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+	board.DeletedAt = nil
+
+	mockRepo.On("SetBoardTrashed", mock.Anything, board.ID, (*time.Time)(nil), board.Version).Return(false, nil)
+
+	restored, err := kafka.RestoreBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Zero(t, restored)
+	mockRepo.AssertNotCalled(t, "GetTasksTrashedWithBoard", mock.Anything, mock.Anything)
+}
+
+func TestCleanupBoard_DeletesTasksListsLabelsAndBoard(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	board := trashEvent()
+	boardID := board.ID.Hex()
+	tasks := boardTasks(boardID, 2)
+
+	mockRepo.On("GetTasksByBoard", mock.Anything, boardID).Return(tasks, nil)
+	mockRepo.On("DeleteBoardTask", mock.Anything, boardID, tasks[0].ID).Return(true, nil)
+	mockRepo.On("DeleteBoardTask", mock.Anything, boardID, tasks[1].ID).Return(true, nil)
+	// Las tareas que estaban en la papelera se eliminan juntas al final
+	mockRepo.On("DeleteTasksByBoard", mock.Anything, boardID).Return(int64(4), nil)
+	mockRepo.On("DeleteListsByBoard", boardID).Return(nil)
+	mockRepo.On("DeleteLabelsByBoard", mock.Anything, boardID).Return(nil)
+	mockRepo.On("DeleteBoard", mock.Anything, board.ID).Return(nil)
+
+	deleted, err := kafka.CleanupBoard(mockRepo, board)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, deleted)
+	mockRepo.AssertExpectations(t)
+}
@@ -1,6 +1,7 @@
 package kafka
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 
@@ -74,22 +75,149 @@ func StartConsumer() {
 					} else {
 						log.Printf("✅ Board almacenado en task-service: %v\n", board)
 					}
+				case "trashed-board":
+					// Parsear JSON del mensaje
+					var board BoardTrashEvent
+					if err := json.Unmarshal(msg.Value, &board); err != nil {
+						log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
+						continue
+					}
+
+					// Enviar a la papelera las tareas del board en task-mongo
+					trashed, err := TrashBoard(repositories.NewTaskRepository(), board)
+					if err != nil {
+						log.Printf("⚠️ Error enviando board a la papelera en task-service (%d tareas): %v\n", trashed, err)
+					} else {
+						log.Printf("✅ Board enviado a la papelera en task-service: %s | Tareas: %d\n", board.ID.Hex(), trashed)
+					}
+				case "restored-board":
+					// Parsear JSON del mensaje
+					var board BoardTrashEvent
+					if err := json.Unmarshal(msg.Value, &board); err != nil {
+						log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
+						continue
+					}
+
+					// Restaurar las tareas que se eliminaron con el board en task-mongo
+					restored, err := RestoreBoard(repositories.NewTaskRepository(), board)
+					if err != nil {
+						log.Printf("⚠️ Error restaurando board en task-service (%d tareas): %v\n", restored, err)
+					} else {
+						log.Printf("✅ Board restaurado en task-service: %s | Tareas: %d\n", board.ID.Hex(), restored)
+					}
+				case "archived-board", "unarchived-board":
+					// Parsear JSON del mensaje
+					var board BoardArchiveEvent
+					if err := json.Unmarshal(msg.Value, &board); err != nil {
+						log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
+						continue
+					}
+
+					// Un board archivado deja sus tareas en solo lectura, un evento atrasado se ignora
+					boardRepo := repositories.NewTaskRepository()
+					applied, err := boardRepo.SetBoardArchived(context.Background(), board.ID, board.Archived, board.Version)
+					if err != nil {
+						log.Printf("⚠️ Error archivando board en task-service: %v\n", err)
+					} else if applied {
+						log.Printf("✅ Board archivado en task-service: %s | Archivado: %t\n", board.ID.Hex(), board.Archived)
+					}
 				case "drop-board":
 					// Parsear JSON del mensaje
-					var board models.Board
+					var board BoardTrashEvent
+
+					if err := json.Unmarshal(msg.Value, &board); err != nil {
+						log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
+						continue
+					}
 
+					// Eliminar definitivamente board, listas y tareas en task-mongo
+					deleted, err := CleanupBoard(repositories.NewTaskRepository(), board)
+					if err != nil {
+						log.Printf("⚠️ Error eliminando board en task-service (%d tareas eliminadas): %v\n", deleted, err)
+					} else {
+						log.Printf("✅ Board eliminado en task-service: %s | Tareas eliminadas: %d\n", board.ID.Hex(), deleted)
+					}
+				case "updated-members":
+					// Parsear JSON del mensaje
+					var board models.Board
 					if err := json.Unmarshal(msg.Value, &board); err != nil {
 						log.Printf("⚠️ Error parseando JSON de board: %v\n", err)
 						continue
 					}
 
-					// Eliminar board en task-mongo
+					// Actualizar los miembros del board en task-mongo
 					boardRepo := repositories.NewTaskRepository()
-					err = boardRepo.DeleteBoard(&board)
+					err = boardRepo.SaveBoardMembers(&board)
+					if err != nil {
+						log.Printf("⚠️ Error actualizando miembros en task-service: %v\n", err)
+					} else {
+						log.Printf("✅ Miembros actualizados en task-service | BoardID: %s\n", board.ID.Hex())
+					}
+				case "updated-workflow":
+					// Parsear JSON del mensaje
+					var event struct {
+						BoardID  string          `json:"board_id"`
+						Workflow models.Workflow `json:"workflow"`
+					}
+					if err := json.Unmarshal(msg.Value, &event); err != nil {
+						log.Printf("⚠️ Error parseando JSON de flujo: %v\n", err)
+						continue
+					}
+
+					// Actualizar el flujo del board en task-mongo
+					boardRepo := repositories.NewTaskRepository()
+					err = boardRepo.UpdateBoardWorkflow(event.BoardID, &event.Workflow)
+					if err != nil {
+						log.Printf("⚠️ Error actualizando flujo en task-service: %v\n", err)
+					} else {
+						log.Printf("✅ Flujo actualizado en task-service | BoardID: %s\n", event.BoardID)
+					}
+				case "new-list", "updated-list":
+					// Parsear JSON del mensaje
+					var list models.List
+					if err := json.Unmarshal(msg.Value, &list); err != nil {
+						log.Printf("⚠️ Error parseando JSON de lista: %v\n", err)
+						continue
+					}
+
+					// Guardar lista en task-mongo, los eventos atrasados se ignoran por updated_at
+					listRepo := repositories.NewTaskRepository()
+					err = listRepo.SaveList(&list)
+					if err != nil {
+						log.Printf("⚠️ Error guardando lista en task-service: %v\n", err)
+					} else {
+						log.Printf("✅ Lista almacenada en task-service: %v\n", list)
+					}
+				case "new-label", "updated-label":
+					// Parsear JSON del mensaje
+					var label models.Label
+					if err := json.Unmarshal(msg.Value, &label); err != nil {
+						log.Printf("⚠️ Error parseando JSON de etiqueta: %v\n", err)
+						continue
+					}
+
+					// Guardar etiqueta en task-mongo, los eventos atrasados se ignoran por updated_at
+					labelRepo := repositories.NewTaskRepository()
+					err = labelRepo.SaveLabel(context.Background(), &label)
+					if err != nil {
+						log.Printf("⚠️ Error guardando etiqueta en task-service: %v\n", err)
+					} else {
+						log.Printf("✅ Etiqueta almacenada en task-service: %v\n", label)
+					}
+				case "deleted-label":
+					// Parsear JSON del mensaje
+					var label models.Label
+					if err := json.Unmarshal(msg.Value, &label); err != nil {
+						log.Printf("⚠️ Error parseando JSON de etiqueta: %v\n", err)
+						continue
+					}
+
+					// Quitar la etiqueta de las tareas del board en task-mongo
+					detached, err := DeleteLabel(repositories.NewTaskRepository(), label)
 					if err != nil {
-						log.Printf("⚠️ Error eliminando board en task-service: %v\n", err)
+						log.Printf("⚠️ Error eliminando etiqueta en task-service: %v\n", err)
 					} else {
-						log.Printf("✅ Board eliminado en task-service: %v\n", board)
+						log.Printf("✅ Etiqueta eliminada en task-service: %s | Tareas: %d\n", label.ID, detached)
 					}
 				}
 			}
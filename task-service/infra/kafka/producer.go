@@ -32,3 +32,36 @@ func ProduceMessage(userID, message, topic, key string) error {
 
 	return nil
 }
+
+// ProduceMessageAndWait envía el mensaje y espera a que Kafka confirme que lo recibió,
+// se usa para los mensajes que no se pueden perder si el servicio se detiene justo después
+func ProduceMessageAndWait(message, topic, key string) error {
+	p, err := kafka.NewProducer(&kafka.ConfigMap{
+		"bootstrap.servers": "kafka:9092",
+	})
+	if err != nil {
+		return fmt.Errorf("❌ Error al crear productor de Kafka: %v", err)
+	}
+	defer p.Close()
+
+	delivery := make(chan kafka.Event, 1)
+	err = p.Produce(&kafka.Message{
+		TopicPartition: kafka.TopicPartition{Topic: &topic, Partition: kafka.PartitionAny},
+		Key:            []byte(key),
+		Value:          []byte(message),
+	}, delivery)
+	if err != nil {
+		return fmt.Errorf("❌ Error enviando mensaje a Kafka: %w", err)
+	}
+
+	report, ok := (<-delivery).(*kafka.Message)
+	if !ok {
+		return fmt.Errorf("❌ Kafka no confirmó el mensaje | Topic: %s | Key: %s", topic, key)
+	}
+	if report.TopicPartition.Error != nil {
+		return fmt.Errorf("❌ Kafka rechazó el mensaje: %w", report.TopicPartition.Error)
+	}
+
+	log.Printf("✅ Mensaje confirmado por Kafka | Topic: %s | Key: %s | Message: %s", topic, key, message)
+	return nil
+}
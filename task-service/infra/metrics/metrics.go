@@ -16,11 +16,28 @@ var (
 		},
 		[]string{"method", "endpoint"},
 	)
+
+	// Tareas eliminadas en cascada al borrar su tablero
+	BoardCleanupTasksTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "board_cleanup_tasks_total",
+			Help: "Número total de tareas eliminadas al borrar su tablero",
+		},
+	)
+
+	// Recordatorios de fecha límite publicados, por tipo de evento
+	TaskRemindersTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "task_reminders_total",
+			Help: "Número total de recordatorios de fecha límite publicados",
+		},
+		[]string{"event"},
+	)
 )
 
 // InitMetrics registra las métricas en Prometheus
 func InitMetrics() {
-	prometheus.MustRegister(HttpRequestsTotal)
+	prometheus.MustRegister(HttpRequestsTotal, BoardCleanupTasksTotal, TaskRemindersTotal)
 }
 
 // Handler para exponer métricas en /metrics
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"time"
 
 	"github.com/gin-contrib/cors"
@@ -13,6 +14,7 @@ import (
 	"github.com/vadgun/gotrelloclone/task-service/repositories"
 	"github.com/vadgun/gotrelloclone/task-service/routes"
 	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.uber.org/zap"
 )
 
 func main() {
@@ -27,9 +29,39 @@ func main() {
 
 	// Inicializar repositorio y servicio
 	taskRepo := repositories.NewTaskRepository()
+	if err := taskRepo.EnsureIndexes(context.Background()); err != nil {
+		logger.Log.Error("❌ Error creando índices de tareas", zap.Error(err))
+	}
+
+	// Las tareas creadas sin estado pasan al estado inicial del flujo por defecto
+	if migrated, err := taskRepo.MigrateTaskStatuses(context.Background()); err != nil {
+		logger.Log.Error("❌ Error migrando estados de tareas", zap.Error(err))
+	} else if migrated > 0 {
+		logger.Log.Info("✅ Tareas migradas al flujo por defecto", zap.Int64("tasks", migrated))
+	}
+
+	// Las tareas anteriores a la prioridad se ordenan como tareas sin prioridad
+	if migrated, err := taskRepo.MigratePriorityRanks(context.Background()); err != nil {
+		logger.Log.Error("❌ Error migrando prioridades de tareas", zap.Error(err))
+	} else if migrated > 0 {
+		logger.Log.Info("✅ Tareas migradas al orden por prioridad", zap.Int64("tasks", migrated))
+	}
+
+	// Las tareas anteriores a no_priority y no_due_at se marcan para que las que no tienen valor queden al final
+	if migrated, err := taskRepo.MigrateMissingSortKeys(context.Background()); err != nil {
+		logger.Log.Error("❌ Error migrando las claves de orden de tareas", zap.Error(err))
+	} else if migrated > 0 {
+		logger.Log.Info("✅ Tareas migradas al orden sin valores al final", zap.Int64("tasks", migrated))
+	}
 	taskService := services.NewTaskService(taskRepo)
 	taskHandler := handlers.NewTaskHandler(taskService)
 
+	// Las tareas en la papelera se eliminan definitivamente al cumplirse la retención
+	go taskService.StartTrashPurge(context.Background(), config.TrashRetention, config.TrashPurgeInterval)
+
+	// Los recordatorios de fecha límite se reparten entre las réplicas, cada tarea la procesa una a la vez
+	go taskService.StartReminderScheduler(context.Background(), config.ReminderOffsets, config.ReminderInterval, config.ReminderLease)
+
 	// Configurar el servicio en modo producción
 	gin.SetMode(gin.ReleaseMode)
 
@@ -40,8 +72,8 @@ func main() {
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173"},
 		AllowMethods:     []string{"POST", "OPTIONS", "GET", "PUT", "PATCH", "DELETE"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -1,7 +1,41 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
 
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BoardRole es el rol de un miembro en el board, se recibe de board-service
+type BoardRole string
+
+const (
+	RoleOwner  BoardRole = "owner"
+	RoleAdmin  BoardRole = "admin"
+	RoleEditor BoardRole = "editor"
+	RoleViewer BoardRole = "viewer"
+)
+
+// BoardMember es un usuario con acceso al board
+type BoardMember struct {
+	UserID string    `bson:"user_id" json:"user_id"`
+	Role   BoardRole `bson:"role" json:"role"`
+}
+
+// Board es la copia local de los boards de board-service, se mantiene con los eventos de board-events
 type Board struct {
-	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Workflow *Workflow          `bson:"workflow,omitempty" json:"workflow,omitempty"`
+	OwnerID  string             `bson:"owner_id" json:"owner_id"`
+	Members  []BoardMember      `bson:"members" json:"members"`
+	// MembersVersion permite descartar eventos de miembros atrasados
+	MembersVersion int64 `bson:"members_version" json:"members_version"`
+	// DeletedAt indica que el tablero está en la papelera, sus tareas no se pueden consultar ni modificar
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// TrashVersion es la versión del último trashed-board o restored-board aplicado
+	TrashVersion int64 `bson:"trash_version,omitempty" json:"-"`
+	// Archived indica que el tablero está archivado, sus tareas solo se pueden consultar
+	Archived bool `bson:"archived,omitempty" json:"archived,omitempty"`
+	// ArchiveVersion es la versión del último archived-board o unarchived-board aplicado
+	ArchiveVersion int64 `bson:"archive_version,omitempty" json:"-"`
 }
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Checklist es una lista de sub-elementos de la tarea, los elementos se guardan en orden
+type Checklist struct {
+	ID    primitive.ObjectID `bson:"_id" json:"id"`
+	Name  string             `bson:"name" json:"name"`
+	Items []ChecklistItem    `bson:"items" json:"items"`
+}
+
+// ChecklistItem es un elemento de una checklist, se puede marcar, asignar y tener fecha límite
+type ChecklistItem struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Text       string             `bson:"text" json:"text"`
+	Done       bool               `bson:"done" json:"done"`
+	AssigneeID string             `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"`
+	DueAt      *time.Time         `bson:"due_at,omitempty" json:"due_at,omitempty"`
+}
+
+// ChecklistProgress es el avance de todas las checklists de la tarea, no se guarda sino que se calcula al leerla
+type ChecklistProgress struct {
+	Done  int     `json:"done"`
+	Total int     `json:"total"`
+	Ratio float64 `json:"ratio"` // Entre 0 y 1, 0 si la tarea no tiene elementos
+}
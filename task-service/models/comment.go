@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Comment es un comentario de una tarea. Las respuestas tienen ParentID y solo hay un nivel de respuestas
+type Comment struct {
+	ID       primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TaskID   string             `bson:"task_id" json:"task_id"`
+	BoardID  string             `bson:"board_id" json:"board_id"`
+	ParentID string             `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	AuthorID string             `bson:"author_id" json:"author_id"`
+	Body     string             `bson:"body" json:"body"`
+	// Mentions son los IDs de los usuarios mencionados con @ en el cuerpo
+	Mentions  []string   `bson:"mentions" json:"mentions"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	EditedAt  *time.Time `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+}
+
+// CommentThread es un comentario de primer nivel con sus respuestas en orden
+type CommentThread struct {
+	Comment
+	Replies []Comment `json:"replies"`
+}
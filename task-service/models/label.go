@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Label es la copia local de las etiquetas de board-service, se mantiene con los eventos de board-events
+type Label struct {
+	ID        string    `bson:"_id" json:"id"`
+	BoardID   string    `bson:"board_id" json:"board_id"`
+	Name      string    `bson:"name" json:"name"`
+	Color     string    `bson:"color" json:"color"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+	// Deleted marca una etiqueta eliminada, se conserva para descartar eventos atrasados de la misma etiqueta
+	Deleted bool `bson:"deleted" json:"deleted,omitempty"`
+}
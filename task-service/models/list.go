@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// List es la copia local de las listas de board-service, se mantiene con los eventos de board-events
+type List struct {
+	ID        string    `bson:"_id" json:"id"`
+	BoardID   string    `bson:"board_id" json:"board_id"`
+	Name      string    `bson:"name" json:"name"`
+	Position  float64   `bson:"position" json:"position"`
+	Archived  bool      `bson:"archived" json:"archived"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
@@ -19,9 +19,43 @@ type Task struct {
 	Title       string             `bson:"title" json:"title" binding:"required"`
 	Description string             `bson:"description" json:"description" binding:"required"`
 	BoardID     string             `bson:"board_id" json:"board_id" binding:"required"`
+	ListID      string             `bson:"list_id" json:"list_id"`   // Si se omite al crear, la tarea va a la primera lista del board
+	Position    float64            `bson:"position" json:"position"` // Orden dentro de la lista, de menor a mayor
 	UserID      string             `bson:"user_id" json:"user_id"`
 	AssigneeID  string             `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"`
 	Status      TaskStatus         `bson:"status" json:"status"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
+	// Version aumenta con cada cambio de la tarea, se expone como ETag para detectar ediciones simultáneas
+	Version int64 `bson:"version" json:"version"`
+	// Archived oculta la tarea del listado del board salvo con include_archived
+	Archived bool `bson:"archived" json:"archived"`
+	// DeletedAt indica que la tarea está en la papelera, se elimina definitivamente al cumplirse la retención
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// DeletedWithBoard indica que la tarea se envió a la papelera junto con su tablero y se restaura con él
+	DeletedWithBoard bool `bson:"deleted_with_board,omitempty" json:"deleted_with_board,omitempty"`
+	// StartAt y DueAt son opcionales, al acercarse DueAt el programador de recordatorios avisa a los responsables
+	StartAt     *time.Time `bson:"start_at,omitempty" json:"start_at"`
+	DueAt       *time.Time `bson:"due_at,omitempty" json:"due_at"`
+	Completed   bool       `bson:"completed" json:"completed"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	// Estado interno de los recordatorios: el último recordatorio publicado para DueAt y cuándo volver a revisar la tarea.
+	// Se reinicia al cambiar DueAt.
+	RemindedAt     *time.Time `bson:"reminded_at,omitempty" json:"-"`
+	NextReminderAt *time.Time `bson:"next_reminder_at,omitempty" json:"-"`
+	RemindersDone  bool       `bson:"reminders_done,omitempty" json:"-"`
+	// Priority y Estimate (story points) son opcionales. PriorityRank guarda el orden de la prioridad para
+	// ordenar el listado con índice, las tareas sin prioridad tienen el mayor
+	Priority     TaskPriority `bson:"priority,omitempty" json:"priority,omitempty"`
+	PriorityRank int          `bson:"priority_rank" json:"-"`
+	// NoPriority y NoDueAt marcan las tareas sin prioridad o sin fecha límite, el listado ordena primero por ellas
+	// para dejar esas tareas al final tanto en orden ascendente como descendente
+	NoPriority bool     `bson:"no_priority" json:"-"`
+	NoDueAt    bool     `bson:"no_due_at" json:"-"`
+	Estimate   *float64 `bson:"estimate,omitempty" json:"estimate,omitempty"`
+	// Labels son los IDs de las etiquetas del board que tiene la tarea
+	Labels []string `bson:"labels,omitempty" json:"labels"`
+	// Checklists se editan con sus propios endpoints, Progress se calcula a partir de ellas al leer la tarea
+	Checklists []Checklist       `bson:"checklists,omitempty" json:"checklists"`
+	Progress   ChecklistProgress `bson:"-" json:"progress"`
 }
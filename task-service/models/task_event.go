@@ -0,0 +1,33 @@
+package models
+
+// TaskEvent es el payload que task-service publica en el tópico task-events,
+// la key del mensaje indica el tipo de evento.
+type TaskEvent struct {
+	Task       Task       `json:"task"`                   // Estado de la tarea después del cambio
+	ActorID    string     `json:"actor_id"`               // Usuario que realizó la acción
+	OldStatus  TaskStatus `json:"old_status,omitempty"`   // Solo en update-task-status
+	OldBoardID string     `json:"old_board_id,omitempty"` // Solo en moved-task
+	OldListID  string     `json:"old_list_id,omitempty"`  // Solo en moved-task
+	// Comment y MentionedIDs solo en new-comment y mentioned-in-comment, los mencionados reciben su propia notificación
+	Comment      *Comment `json:"comment,omitempty"`
+	MentionedIDs []string `json:"mentioned_ids,omitempty"`
+	// EventID solo en task-due-soon y task-overdue, es el mismo si el recordatorio se publica más de una vez
+	EventID string `json:"event_id,omitempty"`
+}
+
+// Keys de los eventos publicados en task-events
+const (
+	TaskCreatedEvent       = "new-task"
+	TaskUpdatedEvent       = "updated-task"
+	TaskStatusUpdatedEvent = "update-task-status"
+	TaskMovedEvent         = "moved-task"
+	TaskAssignedEvent      = "assigned-task"
+	TaskDeletedEvent       = "deleted-task"
+	TaskRestoredEvent      = "restored-task"
+	TaskArchivedEvent      = "archived-task"
+	TaskUnarchivedEvent    = "unarchived-task"
+	TaskCommentedEvent     = "new-comment"
+	TaskMentionedEvent     = "mentioned-in-comment"
+	TaskDueSoonEvent       = "task-due-soon"
+	TaskOverdueEvent       = "task-overdue"
+)
@@ -0,0 +1,30 @@
+package models
+
+// TaskFilter son los filtros del listado de tareas de un board, los campos vacíos no filtran
+type TaskFilter struct {
+	ListID          string
+	IncludeArchived bool
+	// Labels deja solo las tareas con alguna de las etiquetas, o con todas si MatchAllLabels es true
+	Labels         []string
+	MatchAllLabels bool
+	// Sort es el orden del listado, por defecto por lista y posición
+	Sort TaskSort
+}
+
+// Campos por los que se puede ordenar el listado de tareas de un board
+const (
+	SortByPosition = "position"
+	SortByPriority = "priority"
+	SortByDue      = "due"
+	SortByCreated  = "created"
+	SortByUpdated  = "updated"
+)
+
+// TaskSortFields son los valores aceptados en el parámetro sort
+var TaskSortFields = []string{SortByPosition, SortByPriority, SortByDue, SortByCreated, SortByUpdated}
+
+// TaskSort es el campo y la dirección del orden, el campo vacío equivale a SortByPosition
+type TaskSort struct {
+	Field      string
+	Descending bool
+}
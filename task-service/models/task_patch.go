@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// TaskPatch son los campos editables con PUT /tasks/:taskID, los campos en nil no cambian
+type TaskPatch struct {
+	Title       *string
+	Description *string
+	Labels      *[]string // IDs de etiquetas del board de la tarea, reemplazan a las actuales
+	// StartAt y DueAt con valor cero borran la fecha
+	StartAt   *time.Time
+	DueAt     *time.Time
+	Completed *bool
+	// Priority vacía quita la prioridad, ClearEstimate quita la estimación porque cero es una estimación válida
+	Priority      *TaskPriority
+	Estimate      *float64
+	ClearEstimate bool
+}
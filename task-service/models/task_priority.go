@@ -0,0 +1,29 @@
+package models
+
+import "slices"
+
+// TaskPriority va de P0 (la más urgente) a P3, una tarea sin prioridad la tiene vacía
+type TaskPriority string
+
+const (
+	PriorityP0 TaskPriority = "P0"
+	PriorityP1 TaskPriority = "P1"
+	PriorityP2 TaskPriority = "P2"
+	PriorityP3 TaskPriority = "P3"
+)
+
+// TaskPriorities son las prioridades válidas de la más a la menos urgente
+var TaskPriorities = []TaskPriority{PriorityP0, PriorityP1, PriorityP2, PriorityP3}
+
+// Rank devuelve el orden de la prioridad para ordenar las tareas, las tareas sin prioridad van al final
+func (p TaskPriority) Rank() int {
+	if rank := slices.Index(TaskPriorities, p); rank >= 0 {
+		return rank
+	}
+	return len(TaskPriorities)
+}
+
+// Valid indica si es una de las prioridades válidas, la prioridad vacía también es válida
+func (p TaskPriority) Valid() bool {
+	return p == "" || slices.Contains(TaskPriorities, p)
+}
@@ -4,6 +4,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// User es la copia local de los usuarios de user-service, el email permite resolver las menciones
 type User struct {
-	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ID    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name  string             `bson:"name" json:"name"`
+	Email string             `bson:"email" json:"email"`
 }
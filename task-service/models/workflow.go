@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// WorkflowStatus es un estado del flujo de trabajo del board
+type WorkflowStatus struct {
+	Key  TaskStatus `bson:"key" json:"key"`
+	Name string     `bson:"name" json:"name"`
+}
+
+// Workflow es la copia local del flujo del board definido en board-service, se mantiene con los eventos de board-events.
+// Las tareas nuevas empiezan en el primer estado y Transitions indica a qué estados se puede pasar desde cada uno.
+type Workflow struct {
+	Statuses    []WorkflowStatus            `bson:"statuses" json:"statuses"`
+	Transitions map[TaskStatus][]TaskStatus `bson:"transitions" json:"transitions"`
+	UpdatedAt   time.Time                   `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultWorkflow es el flujo de los boards que no definieron uno propio, igual al de board-service
+func DefaultWorkflow() *Workflow {
+	return &Workflow{
+		Statuses: []WorkflowStatus{
+			{Key: TODO, Name: "Por hacer"},
+			{Key: INPROGRESS, Name: "En progreso"},
+			{Key: DONE, Name: "Hecho"},
+		},
+		Transitions: map[TaskStatus][]TaskStatus{
+			TODO:       {INPROGRESS, DONE},
+			INPROGRESS: {TODO, DONE},
+			DONE:       {TODO, INPROGRESS},
+		},
+	}
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Reemplaza las checklists de la tarea solo si sigue en la versión leída, si no devuelve ErrVersionConflict
+func (r *TaskRepository) SetTaskChecklists(ctx context.Context, taskID primitive.ObjectID, checklists []models.Checklist, version int64) error {
+	filter := bson.M{"_id": taskID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"checklists": checklists, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCommentNotFound indica que el comentario no existe
+var ErrCommentNotFound = errors.New("comentario no encontrado")
+
+// Crea un comentario o una respuesta
+func (r *TaskRepository) CreateComment(ctx context.Context, comment *models.Comment) error {
+	result, err := r.commentCollection.InsertOne(ctx, comment)
+	if err != nil {
+		return err
+	}
+	comment.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Obtiene un comentario por ID
+func (r *TaskRepository) GetCommentByID(ctx context.Context, commentID string) (*models.Comment, error) {
+	objID, err := primitive.ObjectIDFromHex(commentID)
+	if err != nil {
+		return nil, ErrCommentNotFound
+	}
+
+	var comment models.Comment
+	err = r.commentCollection.FindOne(ctx, bson.M{"_id": objID}).Decode(&comment)
+	if err == mongo.ErrNoDocuments {
+		return nil, ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Obtiene una página de los comentarios de primer nivel de la tarea, del más antiguo al más reciente, y el total
+func (r *TaskRepository) GetComments(ctx context.Context, taskID string, page, limit int64) ([]models.Comment, int64, error) {
+	filter := bson.M{"task_id": taskID, "parent_id": bson.M{"$exists": false}}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit)
+
+	comments, err := r.findComments(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := r.commentCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// Obtiene las respuestas de los comentarios indicados, de la más antigua a la más reciente
+func (r *TaskRepository) GetReplies(ctx context.Context, parentIDs []string) ([]models.Comment, error) {
+	if len(parentIDs) == 0 {
+		return []models.Comment{}, nil
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	return r.findComments(ctx, bson.M{"parent_id": bson.M{"$in": parentIDs}}, findOptions)
+}
+
+func (r *TaskRepository) findComments(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]models.Comment, error) {
+	cursor, err := r.commentCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// Cambia el cuerpo y las menciones de un comentario
+func (r *TaskRepository) UpdateComment(ctx context.Context, commentID primitive.ObjectID, body string, mentions []string, editedAt time.Time) error {
+	update := bson.M{"$set": bson.M{"body": body, "mentions": mentions, "edited_at": editedAt}}
+	result, err := r.commentCollection.UpdateOne(ctx, bson.M{"_id": commentID}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Elimina un comentario junto con sus respuestas
+func (r *TaskRepository) DeleteComment(ctx context.Context, commentID primitive.ObjectID) error {
+	filter := bson.M{"$or": bson.A{bson.M{"_id": commentID}, bson.M{"parent_id": commentID.Hex()}}}
+	result, err := r.commentCollection.DeleteMany(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Busca en la copia local de usuarios los que coinciden con alguno de los IDs o emails
+func (r *TaskRepository) FindUsers(ctx context.Context, ids []primitive.ObjectID, emails []string) ([]models.User, error) {
+	if len(ids) == 0 && len(emails) == 0 {
+		return []models.User{}, nil
+	}
+	conditions := bson.A{}
+	if len(ids) > 0 {
+		conditions = append(conditions, bson.M{"_id": bson.M{"$in": ids}})
+	}
+	if len(emails) > 0 {
+		conditions = append(conditions, bson.M{"email": bson.M{"$in": emails}})
+	}
+	filter := bson.M{"$or": conditions}
+	cursor, err := r.userCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []models.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
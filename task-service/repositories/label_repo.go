@@ -0,0 +1,56 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Guarda, actualiza o marca como eliminada la etiqueta al recibir un evento de Kafka,
+// un evento atrasado no sobrescribe uno más reciente
+func (r *TaskRepository) SaveLabel(ctx context.Context, label *models.Label) error {
+	filter := bson.M{"_id": label.ID, "updated_at": bson.M{"$lte": label.UpdatedAt}}
+	_, err := r.labelCollection.ReplaceOne(ctx, filter, label, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Ya existe una versión más reciente de la etiqueta
+		return nil
+	}
+	return err
+}
+
+// Obtiene las etiquetas no eliminadas del board con los IDs indicados
+func (r *TaskRepository) GetBoardLabels(ctx context.Context, boardID string, labelIDs []string) ([]models.Label, error) {
+	filter := bson.M{"_id": bson.M{"$in": labelIDs}, "board_id": boardID, "deleted": false}
+	cursor, err := r.labelCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	labels := []models.Label{}
+	if err := cursor.All(ctx, &labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// Quita la etiqueta de todas las tareas del board, incluidas las de la papelera, y devuelve cuántas cambiaron
+func (r *TaskRepository) DetachLabel(ctx context.Context, boardID, labelID string) (int64, error) {
+	filter := bson.M{"board_id": boardID, "labels": labelID}
+	update := bson.M{"$pull": bson.M{"labels": labelID}, "$set": bson.M{"updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
+
+// Elimina las etiquetas de un board eliminado
+func (r *TaskRepository) DeleteLabelsByBoard(ctx context.Context, boardID string) error {
+	_, err := r.labelCollection.DeleteMany(ctx, bson.M{"board_id": boardID})
+	return err
+}
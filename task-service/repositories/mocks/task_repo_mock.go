@@ -0,0 +1,345 @@
+package repomocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockTaskRepo es el mock del repositorio de tareas para probar los servicios sin MongoDB
+type MockTaskRepo struct {
+	mock.Mock
+}
+
+func (m *MockTaskRepo) ClaimReminderTask(ctx context.Context, now, horizon time.Time, lease time.Duration) (*models.Task, error) {
+	args := m.Called(ctx, now, horizon, lease)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) CreateComment(ctx context.Context, comment *models.Comment) error {
+	args := m.Called(ctx, comment)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) CreateTask(ctx context.Context, task *models.Task, userID string) (any, error) {
+	args := m.Called(ctx, task, userID)
+	return args.Get(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) DeleteComment(ctx context.Context, commentID primitive.ObjectID) error {
+	args := m.Called(ctx, commentID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) DeleteTask(ctx context.Context, taskID string, version int64) error {
+	args := m.Called(ctx, taskID, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) FindUsers(ctx context.Context, ids []primitive.ObjectID, emails []string) ([]models.User, error) {
+	args := m.Called(ctx, ids, emails)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.User), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetAllTasks() ([]models.Task, error) {
+	args := m.Called()
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetBoardByID(id string) (*models.Board, error) {
+	args := m.Called(id)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Board), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetBoardLabels(ctx context.Context, boardID string, labelIDs []string) ([]models.Label, error) {
+	args := m.Called(ctx, boardID, labelIDs)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Label), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetCommentByID(ctx context.Context, commentID string) (*models.Comment, error) {
+	args := m.Called(ctx, commentID)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Comment), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetComments(ctx context.Context, taskID string, page, limit int64) ([]models.Comment, int64, error) {
+	args := m.Called(ctx, taskID, page, limit)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Comment), args.Get(1).(int64), args.Error(2)
+	}
+
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepo) GetFirstList(ctx context.Context, boardID string) (*models.List, error) {
+	args := m.Called(ctx, boardID)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.List), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetListByID(ctx context.Context, id string) (*models.List, error) {
+	args := m.Called(ctx, id)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.List), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetNeighborTask(ctx context.Context, boardID, listID string, position float64, next bool, excludeID primitive.ObjectID) (*models.Task, error) {
+	args := m.Called(ctx, boardID, listID, position, next, excludeID)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetReplies(ctx context.Context, parentIDs []string) ([]models.Comment, error) {
+	args := m.Called(ctx, parentIDs)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Comment), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTaskByID(ctx context.Context, taskID string) (*models.Task, error) {
+	args := m.Called(ctx, taskID)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTaskIDsAtPosition(ctx context.Context, boardID, listID string, position float64, excludeID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	args := m.Called(ctx, boardID, listID, position, excludeID)
+
+	if value := args.Get(0); value != nil {
+		return value.([]primitive.ObjectID), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTasksByBoardID(ctx context.Context, boardID string, taskFilter models.TaskFilter, page, limit int64) ([]models.Task, int64, error) {
+	args := m.Called(ctx, boardID, taskFilter, page, limit)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Get(1).(int64), args.Error(2)
+	}
+
+	return nil, args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepo) GetTasksByList(ctx context.Context, boardID, listID string) ([]models.Task, error) {
+	args := m.Called(ctx, boardID, listID)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTrashedTaskByID(ctx context.Context, taskID string) (*models.Task, error) {
+	args := m.Called(ctx, taskID)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTrashedTasks(ctx context.Context, boardID string) ([]models.Task, error) {
+	args := m.Called(ctx, boardID)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetUserByID(id string) (*models.User, error) {
+	args := m.Called(id)
+
+	if value := args.Get(0); value != nil {
+		return value.(*models.User), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) PurgeTrashedTasks(ctx context.Context, cutoff time.Time) (int64, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepo) RestoreTask(ctx context.Context, taskID primitive.ObjectID, withBoard bool) (bool, error) {
+	args := m.Called(ctx, taskID, withBoard)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) SaveReminderState(ctx context.Context, id primitive.ObjectID, dueAt time.Time, remindedAt, next *time.Time) error {
+	args := m.Called(ctx, id, dueAt, remindedAt, next)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) SetTaskArchived(ctx context.Context, taskID string, archived bool, version int64) error {
+	args := m.Called(ctx, taskID, archived, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) SetTaskChecklists(ctx context.Context, taskID primitive.ObjectID, checklists []models.Checklist, version int64) error {
+	args := m.Called(ctx, taskID, checklists, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) SetTaskPositions(ctx context.Context, tasks []models.Task) error {
+	args := m.Called(ctx, tasks)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateComment(ctx context.Context, commentID primitive.ObjectID, body string, mentions []string, editedAt time.Time) error {
+	args := m.Called(ctx, commentID, body, mentions, editedAt)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateTask(ctx context.Context, taskID string, patch *models.TaskPatch, version int64) error {
+	args := m.Called(ctx, taskID, patch, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateTaskAssignee(ctx context.Context, taskID, userID string, version int64) error {
+	args := m.Called(ctx, taskID, userID, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateTaskPlacement(ctx context.Context, taskID primitive.ObjectID, version int64, boardID, listID string, position float64, status models.TaskStatus, clearLabels bool) error {
+	args := m.Called(ctx, taskID, version, boardID, listID, position, status, clearLabels)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateTaskPosition(ctx context.Context, taskID primitive.ObjectID, version int64, position float64) error {
+	args := m.Called(ctx, taskID, version, position)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) UpdateTaskStatus(ctx context.Context, taskID string, status models.TaskStatus, version int64) error {
+	args := m.Called(ctx, taskID, status, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) CountTasksTrashedWithBoard(ctx context.Context, boardID string) (int64, error) {
+	args := m.Called(ctx, boardID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepo) DeleteBoard(ctx context.Context, boardID primitive.ObjectID) error {
+	args := m.Called(ctx, boardID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) DeleteBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, boardID, taskID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) DeleteLabelsByBoard(ctx context.Context, boardID string) error {
+	args := m.Called(ctx, boardID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) DeleteListsByBoard(boardID string) error {
+	args := m.Called(boardID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) DeleteTasksByBoard(ctx context.Context, boardID string) (int64, error) {
+	args := m.Called(ctx, boardID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepo) DetachLabel(ctx context.Context, boardID, labelID string) (int64, error) {
+	args := m.Called(ctx, boardID, labelID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTasksByBoard(ctx context.Context, boardID string) ([]models.Task, error) {
+	args := m.Called(ctx, boardID)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) GetTasksTrashedWithBoard(ctx context.Context, boardID string) ([]models.Task, error) {
+	args := m.Called(ctx, boardID)
+
+	if value := args.Get(0); value != nil {
+		return value.([]models.Task), args.Error(1)
+	}
+
+	return nil, args.Error(1)
+}
+
+func (m *MockTaskRepo) SaveLabel(ctx context.Context, label *models.Label) error {
+	args := m.Called(ctx, label)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepo) SetBoardTrashed(ctx context.Context, boardID primitive.ObjectID, deletedAt *time.Time, version int64) (bool, error) {
+	args := m.Called(ctx, boardID, deletedAt, version)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepo) TrashBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID, deletedAt time.Time) (bool, error) {
+	args := m.Called(ctx, boardID, taskID, deletedAt)
+	return args.Bool(0), args.Error(1)
+}
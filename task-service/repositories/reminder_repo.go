@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ClaimReminderTask toma la siguiente tarea pendiente con fecha límite antes de horizon que toca revisar
+// y la aparta durante lease para que ninguna otra réplica la procese al mismo tiempo. Si la réplica se cae,
+// la tarea se vuelve a tomar al vencer lease. Devuelve mongo.ErrNoDocuments si no hay ninguna.
+func (r *TaskRepository) ClaimReminderTask(ctx context.Context, now, horizon time.Time, lease time.Duration) (*models.Task, error) {
+	filter := bson.M{
+		"due_at":         bson.M{"$lte": horizon},
+		"completed":      bson.M{"$ne": true},
+		"archived":       bson.M{"$ne": true},
+		"deleted_at":     nil,
+		"reminders_done": bson.M{"$ne": true},
+		"$or": bson.A{
+			bson.M{"next_reminder_at": nil},
+			bson.M{"next_reminder_at": bson.M{"$lte": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"next_reminder_at": now.Add(lease)}}
+	findOptions := options.FindOneAndUpdate().SetSort(bson.D{{Key: "due_at", Value: 1}})
+
+	var task models.Task
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// SaveReminderState guarda el último recordatorio publicado y cuándo revisar otra vez la tarea, sin next ya no quedan
+// recordatorios para dueAt. Si la fecha límite cambió mientras tanto no se guarda nada, los recordatorios ya se reiniciaron.
+// No cambia la versión de la tarea porque no es una edición del usuario.
+func (r *TaskRepository) SaveReminderState(ctx context.Context, id primitive.ObjectID, dueAt time.Time, remindedAt, next *time.Time) error {
+	set := bson.M{}
+	if remindedAt != nil {
+		set["reminded_at"] = *remindedAt
+	}
+	update := bson.M{}
+	if next != nil {
+		set["next_reminder_at"] = *next
+	} else {
+		set["reminders_done"] = true
+		update["$unset"] = bson.M{"next_reminder_at": ""}
+	}
+	update["$set"] = set
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "due_at": dueAt}, update)
+	return err
+}
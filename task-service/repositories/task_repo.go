@@ -13,40 +13,93 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// ErrVersionConflict indica que la tarea cambió desde la versión que editó el cliente
+var ErrVersionConflict = errors.New("la tarea fue modificada por otro usuario")
+
 type TaskRepository struct {
-	collection      *mongo.Collection
-	userCollection  *mongo.Collection
-	boardCollection *mongo.Collection
+	collection        *mongo.Collection
+	userCollection    *mongo.Collection
+	boardCollection   *mongo.Collection
+	listCollection    *mongo.Collection
+	commentCollection *mongo.Collection
+	labelCollection   *mongo.Collection
 }
 
 func NewTaskRepository() *TaskRepository {
 	return &TaskRepository{
-		collection:      config.DB.Collection("tasks"),
-		userCollection:  config.DB.Collection("users"),
-		boardCollection: config.DB.Collection("boards"),
+		collection:        config.DB.Collection("tasks"),
+		userCollection:    config.DB.Collection("users"),
+		boardCollection:   config.DB.Collection("boards"),
+		listCollection:    config.DB.Collection("lists"),
+		commentCollection: config.DB.Collection("comments"),
+		labelCollection:   config.DB.Collection("labels"),
 	}
 }
 
+// EnsureIndexes crea los índices para listar las tareas de un board y de una lista en orden,
+// filtrar las tareas por etiqueta, ordenarlas por prioridad y fechas, buscar las tareas con recordatorios pendientes y listar los comentarios de una tarea por fecha
+func (r *TaskRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "list_id", Value: 1}, {Key: "position", Value: 1}}},
+		{Keys: bson.D{{Key: "list_id", Value: 1}, {Key: "position", Value: 1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "labels", Value: 1}}},
+		{Keys: bson.D{{Key: "due_at", Value: 1}, {Key: "next_reminder_at", Value: 1}}},
+		// Un índice por cada orden del listado, Mongo los recorre al revés para el orden descendente.
+		// Por prioridad y fecha límite las tareas sin valor van al final en ambas direcciones, así que hay uno por dirección
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "no_priority", Value: 1}, {Key: "priority_rank", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "no_priority", Value: 1}, {Key: "priority_rank", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "no_due_at", Value: 1}, {Key: "due_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "no_due_at", Value: 1}, {Key: "due_at", Value: -1}, {Key: "_id", Value: -1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "board_id", Value: 1}, {Key: "updated_at", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.commentCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "task_id", Value: 1}, {Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	return err
+}
+
 // 1️⃣ Crear tarea
 func (r *TaskRepository) CreateTask(ctx context.Context, task *models.Task, userID string) (any, error) {
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
 	task.UserID = userID
-	task.Status = models.TODO
+	task.Version = 1
 	id, err := r.collection.InsertOne(ctx, task)
 	return id.InsertedID, err
 }
 
-// 2️⃣ Obtener todas las tareas de un board
-func (r *TaskRepository) GetTasksByBoardID(ctx context.Context, boardID string, page, limit int64) ([]models.Task, int64, error) {
+// 2️⃣ Obtener todas las tareas de un board que cumplen los filtros indicados, en el orden indicado
+func (r *TaskRepository) GetTasksByBoardID(ctx context.Context, boardID string, taskFilter models.TaskFilter, page, limit int64) ([]models.Task, int64, error) {
 	var tasks []models.Task
 
+	filter := bson.M{"board_id": boardID, "deleted_at": nil}
+	if taskFilter.ListID != "" {
+		filter["list_id"] = taskFilter.ListID
+	}
+	if !taskFilter.IncludeArchived {
+		filter["archived"] = bson.M{"$ne": true}
+	}
+	if len(taskFilter.Labels) > 0 {
+		operator := "$in"
+		if taskFilter.MatchAllLabels {
+			operator = "$all"
+		}
+		filter["labels"] = bson.M{operator: taskFilter.Labels}
+	}
+
 	skip := (page - 1) * limit
 	findOptions := options.Find().
+		SetSort(TaskSortKeys(taskFilter.Sort)).
 		SetSkip(skip).
 		SetLimit(limit)
 
-	cursor, err := r.collection.Find(ctx, bson.M{"board_id": boardID}, findOptions)
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -60,7 +113,7 @@ func (r *TaskRepository) GetTasksByBoardID(ctx context.Context, boardID string,
 		tasks = append(tasks, task)
 	}
 
-	count, err := r.collection.CountDocuments(ctx, bson.M{"board_id": boardID})
+	count, err := r.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -68,94 +121,394 @@ func (r *TaskRepository) GetTasksByBoardID(ctx context.Context, boardID string,
 	return tasks, count, nil
 }
 
-// 3️⃣ Obtener una tarea específica
+// TaskSortKeys convierte el orden pedido en las claves de ordenamiento. El _id al final desempata para que
+// la paginación sea estable, y todas las claves van en la misma dirección para aprovechar los índices,
+// salvo no_priority y no_due_at que siempre son ascendentes para dejar al final las tareas sin valor.
+func TaskSortKeys(sort models.TaskSort) bson.D {
+	direction := 1
+	if sort.Descending {
+		direction = -1
+	}
+
+	sortKeys := bson.D{}
+	var keys []string
+	switch sort.Field {
+	case models.SortByPriority:
+		sortKeys = append(sortKeys, bson.E{Key: "no_priority", Value: 1})
+		keys = []string{"priority_rank"}
+	case models.SortByDue:
+		sortKeys = append(sortKeys, bson.E{Key: "no_due_at", Value: 1})
+		keys = []string{"due_at"}
+	case models.SortByCreated:
+		keys = []string{"created_at"}
+	case models.SortByUpdated:
+		keys = []string{"updated_at"}
+	default:
+		keys = []string{"list_id", "position"}
+	}
+
+	for _, key := range append(keys, "_id") {
+		sortKeys = append(sortKeys, bson.E{Key: key, Value: direction})
+	}
+	return sortKeys
+}
+
+// 3️⃣ Obtener una tarea específica, las tareas en la papelera no se encuentran
 func (r *TaskRepository) GetTaskByID(ctx context.Context, taskID string) (*models.Task, error) {
 	var task models.Task
 	objID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return nil, err
 	}
-	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&task)
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID, "deleted_at": nil}).Decode(&task)
 	if err != nil {
 		return nil, err
 	}
 	return &task, nil
 }
 
-// 4️⃣ Actualizar tarea
-func (r *TaskRepository) UpdateTask(ctx context.Context, taskID string, updatedData bson.M) error {
+// 4️⃣ Actualizar tarea, solo si la tarea sigue en la versión que editó el cliente, si no devuelve ErrVersionConflict
+func (r *TaskRepository) UpdateTask(ctx context.Context, taskID string, patch *models.TaskPatch, version int64) error {
 	objID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return err
 	}
-	updatedData["updated_at"] = time.Now()
-	mongoResult, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, bson.M{"$set": updatedData})
+
+	// Solo se escriben los campos presentes en el patch
+	updatedData := bson.M{"updated_at": time.Now()}
+	if patch.Title != nil {
+		updatedData["title"] = *patch.Title
+	}
+	if patch.Description != nil {
+		updatedData["description"] = *patch.Description
+	}
+	if patch.Labels != nil {
+		updatedData["labels"] = *patch.Labels
+	}
+
+	// Las fechas en cero, la prioridad vacía y la estimación con ClearEstimate se borran,
+	// y al cambiar la fecha límite los recordatorios vuelven a empezar
+	unset := bson.M{}
+	setDate(updatedData, unset, "start_at", patch.StartAt)
+	if patch.DueAt != nil {
+		setDate(updatedData, unset, "due_at", patch.DueAt)
+		updatedData["no_due_at"] = patch.DueAt.IsZero()
+		unset["reminded_at"], unset["next_reminder_at"], unset["reminders_done"] = "", "", ""
+	}
+	if patch.Priority != nil {
+		updatedData["priority_rank"] = patch.Priority.Rank()
+		updatedData["no_priority"] = *patch.Priority == ""
+		if *patch.Priority == "" {
+			unset["priority"] = ""
+		} else {
+			updatedData["priority"] = *patch.Priority
+		}
+	}
+	if patch.ClearEstimate {
+		unset["estimate"] = ""
+	} else if patch.Estimate != nil {
+		updatedData["estimate"] = *patch.Estimate
+	}
+	if patch.Completed != nil {
+		updatedData["completed"] = *patch.Completed
+		if *patch.Completed {
+			updatedData["completed_at"] = updatedData["updated_at"]
+		} else {
+			unset["completed_at"] = ""
+		}
+	}
+
+	filter := bson.M{"_id": objID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": updatedData, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
 	if mongoResult.MatchedCount == 0 {
-		err = errors.New("id no encontrada")
+		return ErrVersionConflict
 	}
-	return err
+	return nil
+}
+
+// setDate agrega la fecha del patch a set, o a unset si es la fecha cero
+func setDate(set, unset bson.M, field string, date *time.Time) {
+	switch {
+	case date == nil:
+	case date.IsZero():
+		unset[field] = ""
+	default:
+		set[field] = *date
+	}
+}
+
+// versionFilter compara la versión, las tareas anteriores al versionado no tienen el campo y cuentan como versión 0
+func versionFilter(version int64) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
 }
 
-// 5️⃣ Eliminar tarea
-func (r *TaskRepository) DeleteTask(ctx context.Context, taskID string) error {
+// 5️⃣ Eliminar tarea, queda en la papelera hasta que se restaure o se cumpla la retención.
+// Solo si sigue en la versión que vio el cliente, si no devuelve ErrVersionConflict
+func (r *TaskRepository) DeleteTask(ctx context.Context, taskID string, version int64) error {
 	objID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return err
 	}
-	mongoResult, err := r.collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if mongoResult.DeletedCount == 0 {
-		err = errors.New("id no encontrada")
+	filter := bson.M{"_id": objID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now()}, "$inc": bson.M{"version": 1}}
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
 	}
-	return err
+	if mongoResult.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
 }
 
-// 6️⃣ Mover tarea
-func (r *TaskRepository) UpdateTaskBoard(ctx context.Context, taskID, newBoardID string) error {
-	objID, err := primitive.ObjectIDFromHex(taskID)
+// 6️⃣ Mover tarea a otra lista y/o posición en una sola actualización, el estado cambia si el board destino usa otro flujo
+// y clearLabels quita las etiquetas, que pertenecen al board anterior. Si la tarea ya no está en la versión indicada
+// devuelve ErrVersionConflict
+func (r *TaskRepository) UpdateTaskPlacement(ctx context.Context, taskID primitive.ObjectID, version int64, boardID, listID string, position float64, status models.TaskStatus, clearLabels bool) error {
+	filter := bson.M{"_id": taskID, "version": versionFilter(version), "deleted_at": nil}
+	fields := bson.M{"board_id": boardID, "list_id": listID, "position": position, "status": status, "updated_at": time.Now()}
+	if clearLabels {
+		fields["labels"] = []string{}
+	}
+	update := bson.M{"$set": fields, "$inc": bson.M{"version": 1}}
+
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"_id": objID}
-	update := bson.M{"$set": bson.M{"board_id": newBoardID, "updated_at": time.Now()}}
+	if mongoResult.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
 
+// Cambia solo la posición de la tarea si sigue en la versión indicada, si no devuelve ErrVersionConflict
+func (r *TaskRepository) UpdateTaskPosition(ctx context.Context, taskID primitive.ObjectID, version int64, position float64) error {
+	filter := bson.M{"_id": taskID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"position": position, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
 	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
 	if mongoResult.MatchedCount == 0 {
-		err = errors.New("id no encontrada")
+		return ErrVersionConflict
 	}
-	return err
+	return nil
+}
+
+// Obtiene los IDs de las otras tareas de la lista que están exactamente en position
+func (r *TaskRepository) GetTaskIDsAtPosition(ctx context.Context, boardID, listID string, position float64, excludeID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := bson.M{"board_id": boardID, "list_id": listID, "position": position, "_id": bson.M{"$ne": excludeID}, "deleted_at": nil}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tasks []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids, nil
+}
+
+// Obtiene la tarea más cercana a position dentro de la lista, hacia abajo (next) o hacia arriba,
+// sin contar excludeID. Devuelve nil si no hay ninguna.
+func (r *TaskRepository) GetNeighborTask(ctx context.Context, boardID, listID string, position float64, next bool, excludeID primitive.ObjectID) (*models.Task, error) {
+	operator, order := "$lt", -1
+	if next {
+		operator, order = "$gt", 1
+	}
+	filter := bson.M{"board_id": boardID, "list_id": listID, "position": bson.M{operator: position}, "_id": bson.M{"$ne": excludeID}, "deleted_at": nil}
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "position", Value: order}})
+
+	var task models.Task
+	err := r.collection.FindOne(ctx, filter, findOptions).Decode(&task)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Obtiene todas las tareas de la lista en orden, se usa para reasignar posiciones
+func (r *TaskRepository) GetTasksByList(ctx context.Context, boardID, listID string) ([]models.Task, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"board_id": boardID, "list_id": listID, "deleted_at": nil}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Obtiene todas las tareas de un tablero que no están en la papelera, sin paginar
+func (r *TaskRepository) GetTasksByBoard(ctx context.Context, boardID string) ([]models.Task, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"board_id": boardID, "deleted_at": nil})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Elimina una tarea del tablero, devuelve false si ya no existía
+func (r *TaskRepository) DeleteBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID) (bool, error) {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": taskID, "board_id": boardID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount == 1, nil
 }
 
-// 7️⃣ Asignar una tarea
-func (r *TaskRepository) UpdateTaskAssignee(ctx context.Context, taskID, userID string) error {
+// Guarda la posición de varias tareas en una sola operación, cada una solo si sigue en la versión leída.
+// Devuelve ErrVersionConflict si alguna cambió mientras tanto; las demás quedan guardadas.
+func (r *TaskRepository) SetTaskPositions(ctx context.Context, tasks []models.Task) error {
+	now := time.Now()
+	updates := make([]mongo.WriteModel, 0, len(tasks))
+	for _, task := range tasks {
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": task.ID, "version": versionFilter(task.Version), "deleted_at": nil}).
+			SetUpdate(bson.M{"$set": bson.M{"position": task.Position, "updated_at": now}, "$inc": bson.M{"version": 1}}))
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	result, err := r.collection.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount < int64(len(updates)) {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// 7️⃣ Asignar una tarea, solo si sigue en la versión que editó el cliente
+func (r *TaskRepository) UpdateTaskAssignee(ctx context.Context, taskID, userID string, version int64) error {
 	objID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return err
 	}
-	filter := bson.M{"_id": objID}
-	update := bson.M{"$set": bson.M{"assignee_id": userID, "updated_at": time.Now()}}
+	filter := bson.M{"_id": objID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"assignee_id": userID, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
 
 	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
 	if mongoResult.MatchedCount == 0 {
-		err = errors.New("id no encontrada")
+		return ErrVersionConflict
 	}
-	return err
+	return nil
 }
 
-// 8️⃣ Cmbiar el estado de una tarea
-func (r *TaskRepository) UpdateTaskStatus(ctx context.Context, taskID string, status models.TaskStatus) error {
+// 8️⃣ Cmbiar el estado de una tarea, solo si sigue en la versión con la que se validó la transición
+func (r *TaskRepository) UpdateTaskStatus(ctx context.Context, taskID string, status models.TaskStatus, version int64) error {
 	objID, err := primitive.ObjectIDFromHex(taskID)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": objID}
-	update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}}
+	filter := bson.M{"_id": objID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"status": status, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
 
 	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
 	if mongoResult.MatchedCount == 0 {
-		err = errors.New("id no encontrada")
+		return ErrVersionConflict
 	}
-	return err
+	return nil
+}
+
+// Archiva o desarchiva una tarea, solo si sigue en la versión que vio el cliente
+func (r *TaskRepository) SetTaskArchived(ctx context.Context, taskID string, archived bool, version int64) error {
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objID, "version": versionFilter(version), "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"archived": archived, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+	mongoResult, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if mongoResult.MatchedCount == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// Asigna el estado inicial del flujo por defecto a las tareas que se crearon sin estado
+func (r *TaskRepository) MigrateTaskStatuses(ctx context.Context) (int64, error) {
+	filter := bson.M{"status": bson.M{"$in": bson.A{nil, ""}}}
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"status": models.TODO}})
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
+
+// Asigna el orden de prioridad a las tareas creadas antes de que existiera, sin prioridad van al final del orden
+func (r *TaskRepository) MigratePriorityRanks(ctx context.Context) (int64, error) {
+	filter := bson.M{"priority_rank": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"priority_rank": models.TaskPriority("").Rank()}}
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
+}
+
+// Calcula no_priority y no_due_at en las tareas creadas antes de que existieran,
+// sin los campos esas tareas quedarían antes que las demás al ordenar por prioridad o fecha límite
+func (r *TaskRepository) MigrateMissingSortKeys(ctx context.Context) (int64, error) {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"no_priority": bson.M{"$exists": false}},
+		bson.M{"no_due_at": bson.M{"$exists": false}},
+	}}
+	missing := bson.A{"missing", "null"}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"no_priority": bson.M{"$in": bson.A{bson.M{"$type": "$priority"}, missing}},
+		"no_due_at":   bson.M{"$in": bson.A{bson.M{"$type": "$due_at"}, missing}},
+	}}}}
+	mongoResult, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return mongoResult.ModifiedCount, nil
 }
 
 // 9️⃣ Guarda el usuario al recibir un evento de Kafka
@@ -174,27 +527,40 @@ func (r *TaskRepository) GetUserByID(id string) (*models.User, error) {
 	return &user, nil
 }
 
-// 1️⃣1️⃣ Guarda el tablero ar recibir un evento de Kafka
+// 1️⃣1️⃣ Guarda el tablero ar recibir un evento de Kafka, los eventos repetidos o atrasados se ignoran por members_version.
+// No reemplaza el documento: conserva la papelera y el archivado si sus eventos llegaron antes que new-board.
 func (r *TaskRepository) SaveBoard(board *models.Board) error {
-	_, err := r.boardCollection.InsertOne(context.Background(), board)
+	filter := bson.M{"_id": board.ID, "members_version": bson.M{"$not": bson.M{"$gte": board.MembersVersion}}}
+	fields := bson.M{"owner_id": board.OwnerID, "members": board.Members, "members_version": board.MembersVersion}
+	if board.Workflow != nil {
+		fields["workflow"] = board.Workflow
+	}
+	_, err := r.boardCollection.UpdateOne(context.Background(), filter, bson.M{"$set": fields}, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// El board ya tiene una versión de miembros igual o más reciente
+		return nil
+	}
 	return err
 }
 
-// 1️⃣2️⃣ Elimina un tablero al recibir un evento de Kafka
-func (r *TaskRepository) DeleteBoard(board *models.Board) error {
-	mongoResult, err := r.boardCollection.DeleteOne(context.Background(), board)
-	if err != nil {
-		return err
-	}
-
-	if mongoResult.DeletedCount == 0 {
-		return errors.New("tablero no encontrado")
+// Actualiza los miembros del tablero al recibir un evento de Kafka, un evento atrasado no sobrescribe uno más reciente
+func (r *TaskRepository) SaveBoardMembers(board *models.Board) error {
+	filter := bson.M{"_id": board.ID, "members_version": bson.M{"$not": bson.M{"$gte": board.MembersVersion}}}
+	update := bson.M{"$set": bson.M{"owner_id": board.OwnerID, "members": board.Members, "members_version": board.MembersVersion}}
+	_, err := r.boardCollection.UpdateOne(context.Background(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
 	}
+	return err
+}
 
-	return nil
+// 1️⃣2️⃣ Elimina un tablero al recibir un evento de Kafka, si ya no existe no es error para tolerar eventos repetidos
+func (r *TaskRepository) DeleteBoard(ctx context.Context, boardID primitive.ObjectID) error {
+	_, err := r.boardCollection.DeleteOne(ctx, bson.M{"_id": boardID})
+	return err
 }
 
-// 1️⃣3️⃣ Obtiene el tablero por ID en la base de datos de mongo-task
+// 1️⃣3️⃣ Obtiene el tablero por ID en la base de datos de mongo-task, los tableros en la papelera no se encuentran
 func (r *TaskRepository) GetBoardByID(id string) (*models.Board, error) {
 	var board models.Board
 	objID, err := primitive.ObjectIDFromHex(id)
@@ -202,13 +568,73 @@ func (r *TaskRepository) GetBoardByID(id string) (*models.Board, error) {
 		return &board, err
 	}
 
-	err = r.boardCollection.FindOne(context.Background(), bson.M{"_id": objID}).Decode(&board)
+	err = r.boardCollection.FindOne(context.Background(), bson.M{"_id": objID, "deleted_at": nil}).Decode(&board)
 	if err != nil {
 		return &board, err
 	}
 	return &board, nil
 }
 
+// Actualiza el flujo del tablero al recibir un evento de Kafka, un evento atrasado no sobrescribe uno más reciente
+func (r *TaskRepository) UpdateBoardWorkflow(boardID string, workflow *models.Workflow) error {
+	objID, err := primitive.ObjectIDFromHex(boardID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objID, "workflow.updated_at": bson.M{"$not": bson.M{"$gt": workflow.UpdatedAt}}}
+	_, err = r.boardCollection.UpdateOne(context.Background(), filter, bson.M{"$set": bson.M{"workflow": workflow}})
+	return err
+}
+
+// Archiva o desarchiva el tablero al recibir un evento de Kafka, devuelve false si ya se aplicó un evento más reciente
+func (r *TaskRepository) SetBoardArchived(ctx context.Context, boardID primitive.ObjectID, archived bool, version int64) (bool, error) {
+	update := bson.M{"$set": bson.M{"archived": archived, "archive_version": version}}
+	return r.updateBoardState(ctx, boardID, "archive_version", version, update)
+}
+
+// Guarda o actualiza la lista al recibir un evento de Kafka, un evento atrasado no sobrescribe uno más reciente
+func (r *TaskRepository) SaveList(list *models.List) error {
+	filter := bson.M{"_id": list.ID, "updated_at": bson.M{"$lte": list.UpdatedAt}}
+	_, err := r.listCollection.ReplaceOne(context.Background(), filter, list, options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// Ya existe una versión más reciente de la lista
+		return nil
+	}
+	return err
+}
+
+// Obtiene la lista por ID en la base de datos de mongo-task
+func (r *TaskRepository) GetListByID(ctx context.Context, id string) (*models.List, error) {
+	var list models.List
+	err := r.listCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&list)
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Obtiene la primera lista no archivada del board, nil si el board no tiene listas
+func (r *TaskRepository) GetFirstList(ctx context.Context, boardID string) (*models.List, error) {
+	findOptions := options.FindOne().SetSort(bson.D{{Key: "position", Value: 1}})
+
+	var list models.List
+	err := r.listCollection.FindOne(ctx, bson.M{"board_id": boardID, "archived": false}, findOptions).Decode(&list)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Elimina las listas de un board eliminado
+func (r *TaskRepository) DeleteListsByBoard(boardID string) error {
+	_, err := r.listCollection.DeleteMany(context.Background(), bson.M{"board_id": boardID})
+	return err
+}
+
 // Obtiene todas las tareas disponibles para el
 func (r *TaskRepository) GetAllTasks() ([]models.Task, error) {
 	var tasks []models.Task
@@ -216,7 +642,7 @@ func (r *TaskRepository) GetAllTasks() ([]models.Task, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := r.collection.Find(context.TODO(), bson.M{})
+	cursor, err := r.collection.Find(context.TODO(), bson.M{"deleted_at": nil})
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskRepositoryInterface son las operaciones de TaskRepository que usa TaskService, los tests usan repomocks.MockTaskRepo
+type TaskRepositoryInterface interface {
+	ClaimReminderTask(ctx context.Context, now, horizon time.Time, lease time.Duration) (*models.Task, error)
+	CreateComment(ctx context.Context, comment *models.Comment) error
+	CreateTask(ctx context.Context, task *models.Task, userID string) (any, error)
+	DeleteComment(ctx context.Context, commentID primitive.ObjectID) error
+	DeleteTask(ctx context.Context, taskID string, version int64) error
+	FindUsers(ctx context.Context, ids []primitive.ObjectID, emails []string) ([]models.User, error)
+	GetAllTasks() ([]models.Task, error)
+	GetBoardByID(id string) (*models.Board, error)
+	GetBoardLabels(ctx context.Context, boardID string, labelIDs []string) ([]models.Label, error)
+	GetCommentByID(ctx context.Context, commentID string) (*models.Comment, error)
+	GetComments(ctx context.Context, taskID string, page, limit int64) ([]models.Comment, int64, error)
+	GetFirstList(ctx context.Context, boardID string) (*models.List, error)
+	GetListByID(ctx context.Context, id string) (*models.List, error)
+	GetNeighborTask(ctx context.Context, boardID, listID string, position float64, next bool, excludeID primitive.ObjectID) (*models.Task, error)
+	GetReplies(ctx context.Context, parentIDs []string) ([]models.Comment, error)
+	GetTaskByID(ctx context.Context, taskID string) (*models.Task, error)
+	GetTaskIDsAtPosition(ctx context.Context, boardID, listID string, position float64, excludeID primitive.ObjectID) ([]primitive.ObjectID, error)
+	GetTasksByBoardID(ctx context.Context, boardID string, taskFilter models.TaskFilter, page, limit int64) ([]models.Task, int64, error)
+	GetTasksByList(ctx context.Context, boardID, listID string) ([]models.Task, error)
+	GetTrashedTaskByID(ctx context.Context, taskID string) (*models.Task, error)
+	GetTrashedTasks(ctx context.Context, boardID string) ([]models.Task, error)
+	GetUserByID(id string) (*models.User, error)
+	PurgeTrashedTasks(ctx context.Context, cutoff time.Time) (int64, error)
+	RestoreTask(ctx context.Context, taskID primitive.ObjectID, withBoard bool) (bool, error)
+	SaveReminderState(ctx context.Context, id primitive.ObjectID, dueAt time.Time, remindedAt, next *time.Time) error
+	SetTaskArchived(ctx context.Context, taskID string, archived bool, version int64) error
+	SetTaskChecklists(ctx context.Context, taskID primitive.ObjectID, checklists []models.Checklist, version int64) error
+	SetTaskPositions(ctx context.Context, tasks []models.Task) error
+	UpdateComment(ctx context.Context, commentID primitive.ObjectID, body string, mentions []string, editedAt time.Time) error
+	UpdateTask(ctx context.Context, taskID string, patch *models.TaskPatch, version int64) error
+	UpdateTaskAssignee(ctx context.Context, taskID, userID string, version int64) error
+	UpdateTaskPlacement(ctx context.Context, taskID primitive.ObjectID, version int64, boardID, listID string, position float64, status models.TaskStatus, clearLabels bool) error
+	UpdateTaskPosition(ctx context.Context, taskID primitive.ObjectID, version int64, position float64) error
+	UpdateTaskStatus(ctx context.Context, taskID string, status models.TaskStatus, version int64) error
+}
+
+// BoardEventsRepositoryInterface son las operaciones de TaskRepository que aplican los eventos de board-service,
+// los tests usan repomocks.MockTaskRepo
+type BoardEventsRepositoryInterface interface {
+	CountTasksTrashedWithBoard(ctx context.Context, boardID string) (int64, error)
+	DeleteBoard(ctx context.Context, boardID primitive.ObjectID) error
+	DeleteBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID) (bool, error)
+	DeleteLabelsByBoard(ctx context.Context, boardID string) error
+	DeleteListsByBoard(boardID string) error
+	DeleteTasksByBoard(ctx context.Context, boardID string) (int64, error)
+	DetachLabel(ctx context.Context, boardID, labelID string) (int64, error)
+	GetTasksByBoard(ctx context.Context, boardID string) ([]models.Task, error)
+	GetTasksTrashedWithBoard(ctx context.Context, boardID string) ([]models.Task, error)
+	RestoreTask(ctx context.Context, taskID primitive.ObjectID, withBoard bool) (bool, error)
+	SaveLabel(ctx context.Context, label *models.Label) error
+	SetBoardTrashed(ctx context.Context, boardID primitive.ObjectID, deletedAt *time.Time, version int64) (bool, error)
+	TrashBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID, deletedAt time.Time) (bool, error)
+}
@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// trashedTaskFilter selecciona las tareas que se enviaron a la papelera una por una, sin su tablero
+func trashedTaskFilter() bson.M {
+	return bson.M{"deleted_at": bson.M{"$ne": nil}, "deleted_with_board": bson.M{"$ne": true}}
+}
+
+// Obtiene las tareas del tablero que están en la papelera, las eliminadas más recientemente primero
+func (r *TaskRepository) GetTrashedTasks(ctx context.Context, boardID string) ([]models.Task, error) {
+	filter := trashedTaskFilter()
+	filter["board_id"] = boardID
+	return r.findTasks(ctx, filter, options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}}))
+}
+
+// Obtiene una tarea de la papelera
+func (r *TaskRepository) GetTrashedTaskByID(ctx context.Context, taskID string) (*models.Task, error) {
+	objID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := trashedTaskFilter()
+	filter["_id"] = objID
+	var task models.Task
+	if err := r.collection.FindOne(ctx, filter).Decode(&task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// Obtiene las tareas que se enviaron a la papelera junto con el tablero
+func (r *TaskRepository) GetTasksTrashedWithBoard(ctx context.Context, boardID string) ([]models.Task, error) {
+	return r.findTasks(ctx, bson.M{"board_id": boardID, "deleted_with_board": true}, options.Find())
+}
+
+// Cuenta las tareas que están en la papelera porque se eliminaron junto con el tablero
+func (r *TaskRepository) CountTasksTrashedWithBoard(ctx context.Context, boardID string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"board_id": boardID, "deleted_with_board": true})
+}
+
+func (r *TaskRepository) findTasks(ctx context.Context, filter bson.M, findOptions *options.FindOptions) ([]models.Task, error) {
+	cursor, err := r.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tasks := []models.Task{}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Envía a la papelera una tarea del tablero eliminado, devuelve false si ya estaba en la papelera
+func (r *TaskRepository) TrashBoardTask(ctx context.Context, boardID string, taskID primitive.ObjectID, deletedAt time.Time) (bool, error) {
+	filter := bson.M{"_id": taskID, "board_id": boardID, "deleted_at": nil}
+	update := bson.M{"$set": bson.M{"deleted_at": deletedAt, "deleted_with_board": true}, "$inc": bson.M{"version": 1}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// Saca la tarea de la papelera, withBoard indica si se restaura junto con su tablero.
+// Devuelve false si la tarea ya no estaba en la papelera.
+func (r *TaskRepository) RestoreTask(ctx context.Context, taskID primitive.ObjectID, withBoard bool) (bool, error) {
+	filter := trashedTaskFilter()
+	if withBoard {
+		filter["deleted_with_board"] = true
+	}
+	filter["_id"] = taskID
+
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": "", "deleted_with_board": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$inc":   bson.M{"version": 1},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// Elimina definitivamente las tareas que están en la papelera desde antes de cutoff.
+// Las que se eliminaron con su tablero se purgan cuando board-service purga el tablero.
+func (r *TaskRepository) PurgeTrashedTasks(ctx context.Context, cutoff time.Time) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lte": cutoff}, "deleted_with_board": bson.M{"$ne": true}}
+	tasks, err := r.findTasks(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil || len(tasks) == 0 {
+		return 0, err
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(tasks))
+	taskIDs := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+		taskIDs = append(taskIDs, task.ID.Hex())
+	}
+
+	filter["_id"] = bson.M{"$in": ids}
+	result, err := r.collection.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	// Los comentarios de las tareas eliminadas ya no se pueden consultar
+	if _, err := r.commentCollection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}}); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// Elimina definitivamente todas las tareas del tablero y sus comentarios, incluidas las que están en la papelera
+func (r *TaskRepository) DeleteTasksByBoard(ctx context.Context, boardID string) (int64, error) {
+	result, err := r.collection.DeleteMany(ctx, bson.M{"board_id": boardID})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.commentCollection.DeleteMany(ctx, bson.M{"board_id": boardID}); err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// Marca el tablero como eliminado (deletedAt) o restaurado (nil) al recibir un evento de Kafka.
+// Devuelve false si ya se aplicó un evento más reciente, el mismo evento repetido se vuelve a aplicar.
+// Sin copia local del tablero se crea una, así sus tareas se envían a la papelera aunque new-board no haya llegado.
+func (r *TaskRepository) SetBoardTrashed(ctx context.Context, boardID primitive.ObjectID, deletedAt *time.Time, version int64) (bool, error) {
+	update := bson.M{"$set": bson.M{"trash_version": version}, "$unset": bson.M{"deleted_at": ""}}
+	if deletedAt != nil {
+		update = bson.M{"$set": bson.M{"trash_version": version, "deleted_at": *deletedAt}}
+	}
+	return r.updateBoardState(ctx, boardID, "trash_version", version, update)
+}
+
+// updateBoardState aplica update al tablero si versionField no es mayor que version, devuelve false si no se aplicó.
+// Si aún no existe la copia local del tablero (su new-board no ha llegado) se crea con el estado recibido,
+// SaveBoard la completa después sin perderlo.
+func (r *TaskRepository) updateBoardState(ctx context.Context, boardID primitive.ObjectID, versionField string, version int64, update bson.M) (bool, error) {
+	filter := bson.M{"_id": boardID, versionField: bson.M{"$not": bson.M{"$gt": version}}}
+	result, err := r.boardCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		// El tablero ya tiene una versión más reciente
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1 || result.UpsertedCount == 1, nil
+}
@@ -10,14 +10,33 @@ func SetupTaskRoutes(router *gin.Engine, taskHandler *handlers.TaskHandler) {
 	taskGroup := router.Group("/tasks")
 	taskGroup.Use(middlewares.AuthMiddleware())
 
-	taskGroup.POST("", taskHandler.CreateTask)                      // Crear tarea - Kafka producer implementado
-	taskGroup.GET("/board/:boardID", taskHandler.GetTasksByBoardID) // Obtener todas las tareas de un board - Implementar producer
-	taskGroup.GET("/:taskID", taskHandler.GetTaskByID)              // Obtener una tarea específica - Implementar producer
-	taskGroup.PUT("/:taskID", taskHandler.UpdateTask)               // Actualizar tarea - Implementar producer
-	taskGroup.PUT("/:taskID/move", taskHandler.MoveTask)            // Mueve una tarea entre boards - Implementar producer
-	taskGroup.PUT("/:taskID/assign", taskHandler.AssignTask)        // Asigna la tarea a un usuario - Implementar producer
-	taskGroup.PUT("/:taskID/status", taskHandler.UpdateTaskStatus)  // Cambia el estado de una tarea - Implementar producer
-	taskGroup.DELETE("/:taskID", taskHandler.DeleteTask)            // Eliminar tarea - Implementar producer
+	taskGroup.POST("", taskHandler.CreateTask)                                  // Crear tarea - Kafka producer implementado
+	taskGroup.GET("/board/:boardID", taskHandler.GetTasksByBoardID)             // Obtener todas las tareas de un board - Implementar producer
+	taskGroup.GET("/:taskID", taskHandler.GetTaskByID)                          // Obtener una tarea específica - Implementar producer
+	taskGroup.PUT("/:taskID", taskHandler.UpdateTask)                           // Actualizar tarea - Implementar producer
+	taskGroup.PUT("/:taskID/move", taskHandler.MoveTask)                        // Mueve una tarea entre boards - Implementar producer
+	taskGroup.PUT("/:taskID/assign", taskHandler.AssignTask)                    // Asigna la tarea a un usuario - Implementar producer
+	taskGroup.PUT("/:taskID/status", taskHandler.UpdateTaskStatus)              // Cambia el estado de una tarea - Implementar producer
+	taskGroup.DELETE("/:taskID", taskHandler.DeleteTask)                        // Enviar tarea a la papelera - Kafka producer implementado
+	taskGroup.GET("/board/:boardID/trash", taskHandler.GetTrash)                // Papelera de un board, solo el owner
+	taskGroup.POST("/:taskID/restore", taskHandler.RestoreTask)                 // Restaurar tarea de la papelera - Kafka producer implementado
+	taskGroup.PUT("/:taskID/archive", taskHandler.ArchiveTask)                  // Archivar tarea - Kafka producer implementado
+	taskGroup.PUT("/:taskID/unarchive", taskHandler.UnarchiveTask)              // Desarchivar tarea - Kafka producer implementado
+	taskGroup.GET("/:taskID/comments", taskHandler.GetComments)                 // Comentarios de una tarea con sus respuestas
+	taskGroup.POST("/:taskID/comments", taskHandler.CreateComment)              // Comentar o responder - Kafka producer implementado
+	taskGroup.PUT("/:taskID/comments/:commentID", taskHandler.UpdateComment)    // Editar comentario, solo el autor - Kafka producer implementado
+	taskGroup.DELETE("/:taskID/comments/:commentID", taskHandler.DeleteComment) // Eliminar comentario y sus respuestas
+
+	// Checklists de la tarea, cada cambio devuelve la tarea con su avance
+	taskGroup.POST("/:taskID/checklists", taskHandler.AddChecklist)                                            // Agregar checklist
+	taskGroup.PUT("/:taskID/checklists/:checklistID", taskHandler.RenameChecklist)                             // Renombrar checklist
+	taskGroup.DELETE("/:taskID/checklists/:checklistID", taskHandler.DeleteChecklist)                          // Eliminar checklist
+	taskGroup.POST("/:taskID/checklists/:checklistID/items", taskHandler.AddChecklistItem)                     // Agregar elemento
+	taskGroup.PUT("/:taskID/checklists/:checklistID/items/:itemID", taskHandler.UpdateChecklistItem)           // Editar texto, asignado y fecha límite
+	taskGroup.PUT("/:taskID/checklists/:checklistID/items/:itemID/toggle", taskHandler.ToggleChecklistItem)    // Marcar o desmarcar elemento
+	taskGroup.PUT("/:taskID/checklists/:checklistID/items/:itemID/move", taskHandler.MoveChecklistItem)        // Reordenar elemento
+	taskGroup.DELETE("/:taskID/checklists/:checklistID/items/:itemID", taskHandler.DeleteChecklistItem)        // Eliminar elemento
+	taskGroup.POST("/:taskID/checklists/:checklistID/items/:itemID/convert", taskHandler.ConvertChecklistItem) // Convertir elemento en tarea - Kafka producer implementado
 
 	adminGroup := router.Group("/admin")
 	{
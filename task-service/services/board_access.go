@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrBoardNotFound indica que el board no existe en la copia local de task-service
+	ErrBoardNotFound = errors.New("el board no existe")
+	// ErrForbidden indica que el usuario no es miembro del board o su rol no alcanza para la operación
+	ErrForbidden = errors.New("no tienes permisos sobre el board")
+	// ErrBoardArchived indica que el board está archivado y sus tareas solo se pueden consultar
+	ErrBoardArchived = errors.New("el board está archivado, desarchívalo para modificar sus tareas")
+)
+
+// roleRank ordena los roles de menor a mayor permiso, un rol desconocido vale 0
+var roleRank = map[models.BoardRole]int{
+	models.RoleViewer: 1,
+	models.RoleEditor: 2,
+	models.RoleAdmin:  3,
+	models.RoleOwner:  4,
+}
+
+// MemberRole devuelve el rol del usuario en el board, vacío si no es miembro
+func MemberRole(board *models.Board, userID string) models.BoardRole {
+	if userID != "" && board.OwnerID == userID {
+		return models.RoleOwner
+	}
+	for _, member := range board.Members {
+		if member.UserID == userID {
+			return member.Role
+		}
+	}
+	return ""
+}
+
+// RoleAllows indica si role tiene al menos los permisos de min
+func RoleAllows(role, min models.BoardRole) bool {
+	return roleRank[role] > 0 && roleRank[role] >= roleRank[min]
+}
+
+// Authorize verifica que el usuario sea miembro del board con al menos el rol min.
+// Los viewers solo consultan, crear o modificar tareas requiere editor.
+func (s *TaskService) Authorize(ctx context.Context, boardID, userID string, min models.BoardRole) error {
+	_, err := s.authorize(boardID, userID, min)
+	return err
+}
+
+// AuthorizeWrite es Authorize para las operaciones que modifican tareas, en un board archivado devuelve ErrBoardArchived
+func (s *TaskService) AuthorizeWrite(ctx context.Context, boardID, userID string, min models.BoardRole) error {
+	board, err := s.authorize(boardID, userID, min)
+	if err != nil {
+		return err
+	}
+	if board.Archived {
+		return ErrBoardArchived
+	}
+	return nil
+}
+
+func (s *TaskService) authorize(boardID, userID string, min models.BoardRole) (*models.Board, error) {
+	board, err := s.board(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if !RoleAllows(MemberRole(board, userID), min) {
+		return nil, ErrForbidden
+	}
+	return board, nil
+}
+
+// IsBoardMember indica si el usuario tiene cualquier rol en el board
+func (s *TaskService) IsBoardMember(ctx context.Context, boardID, userID string) (bool, error) {
+	board, err := s.board(boardID)
+	if err != nil {
+		return false, err
+	}
+	return MemberRole(board, userID) != "", nil
+}
+
+func (s *TaskService) board(boardID string) (*models.Board, error) {
+	if !primitive.IsValidObjectID(boardID) {
+		return nil, ErrBoardNotFound
+	}
+	board, err := s.repo.GetBoardByID(boardID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrBoardNotFound
+	}
+	return board, err
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// memberBoard es un board de la copia local con un miembro por rol
+func memberBoard() *models.Board {
+	return &models.Board{
+		ID:      primitive.NewObjectID(),
+		OwnerID: "owner",
+		Members: []models.BoardMember{
+			{UserID: "owner", Role: models.RoleOwner},
+			{UserID: "editor", Role: models.RoleEditor},
+			{UserID: "viewer", Role: models.RoleViewer},
+		},
+	}
+}
+
+func TestTaskService_Authorize_Members(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	board := memberBoard()
+	boardID := board.ID.Hex()
+
+	mockRepo.On("GetBoardByID", boardID).Return(board, nil)
+
+	// Los viewers consultan pero no modifican, quien no es miembro no hace ninguna de las dos
+	assert.NoError(t, service.Authorize(context.Background(), boardID, "viewer", models.RoleViewer))
+	assert.ErrorIs(t, service.Authorize(context.Background(), boardID, "viewer", models.RoleEditor), services.ErrForbidden)
+	assert.NoError(t, service.Authorize(context.Background(), boardID, "editor", models.RoleEditor))
+	assert.NoError(t, service.Authorize(context.Background(), boardID, "owner", models.RoleEditor))
+	assert.ErrorIs(t, service.Authorize(context.Background(), boardID, "stranger", models.RoleViewer), services.ErrForbidden)
+	assert.ErrorIs(t, service.Authorize(context.Background(), boardID, "", models.RoleViewer), services.ErrForbidden)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_Authorize_UnknownBoard(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	boardID := primitive.NewObjectID().Hex()
+
+	mockRepo.On("GetBoardByID", boardID).Return(nil, mongo.ErrNoDocuments)
+
+	assert.ErrorIs(t, service.Authorize(context.Background(), boardID, "owner", models.RoleViewer), services.ErrBoardNotFound)
+
+	// Un ID inválido no llega a consultar la copia local
+	assert.ErrorIs(t, service.Authorize(context.Background(), "no-es-un-id", "owner", models.RoleViewer), services.ErrBoardNotFound)
+	mockRepo.AssertNumberOfCalls(t, "GetBoardByID", 1)
+}
+
+func TestTaskService_IsBoardMember(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	board := memberBoard()
+
+	mockRepo.On("GetBoardByID", board.ID.Hex()).Return(board, nil)
+
+	member, err := service.IsBoardMember(context.Background(), board.ID.Hex(), "viewer")
+	assert.NoError(t, err)
+	assert.True(t, member)
+
+	member, err = service.IsBoardMember(context.Background(), board.ID.Hex(), "stranger")
+	assert.NoError(t, err)
+	assert.False(t, member)
+}
@@ -0,0 +1,74 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+func TestTaskService_ArchiveTask(t *testing.T) {
+	for _, archived := range []bool{true, false} {
+		mockRepo := new(repomocks.MockTaskRepo)
+		service := services.NewTaskService(mockRepo)
+
+		mockRepo.On("SetTaskArchived", mock.Anything, "task", archived, int64(3)).Return(nil)
+		mockRepo.On("GetTaskByID", mock.Anything, "task").Return(&models.Task{Archived: archived}, nil)
+
+		task, err := service.ArchiveTask(context.Background(), "task", archived, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, archived, task.Archived)
+		// La tarea se devuelve con sus campos calculados como en GET /tasks/:taskID
+		assert.NotNil(t, task.Labels)
+		mockRepo.AssertExpectations(t)
+	}
+}
+
+func TestTaskService_ArchiveTask_Error(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	failure := errors.New("mongo caído")
+
+	mockRepo.On("SetTaskArchived", mock.Anything, "task", true, int64(3)).Return(failure)
+
+	task, err := service.ArchiveTask(context.Background(), "task", true, 3)
+
+	assert.Nil(t, task)
+	assert.ErrorIs(t, err, failure)
+	mockRepo.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_AuthorizeWrite_ArchivedBoard(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	board := memberBoard()
+	board.Archived = true
+	boardID := board.ID.Hex()
+
+	mockRepo.On("GetBoardByID", boardID).Return(board, nil)
+
+	// Un board archivado se sigue consultando, pero ni el owner modifica sus tareas
+	assert.NoError(t, service.Authorize(context.Background(), boardID, "viewer", models.RoleViewer))
+	assert.ErrorIs(t, service.AuthorizeWrite(context.Background(), boardID, "editor", models.RoleEditor), services.ErrBoardArchived)
+	assert.ErrorIs(t, service.AuthorizeWrite(context.Background(), boardID, "owner", models.RoleEditor), services.ErrBoardArchived)
+	// Quien no tiene permisos recibe ErrForbidden, no se le revela que el board está archivado
+	assert.ErrorIs(t, service.AuthorizeWrite(context.Background(), boardID, "viewer", models.RoleEditor), services.ErrForbidden)
+	assert.ErrorIs(t, service.AuthorizeWrite(context.Background(), boardID, "stranger", models.RoleEditor), services.ErrForbidden)
+}
+
+func TestTaskService_AuthorizeWrite_ActiveBoard(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	board := memberBoard()
+	boardID := board.ID.Hex()
+
+	mockRepo.On("GetBoardByID", boardID).Return(board, nil)
+
+	assert.NoError(t, service.AuthorizeWrite(context.Background(), boardID, "editor", models.RoleEditor))
+}
@@ -0,0 +1,357 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vadgun/gotrelloclone/task-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxChecklistText es el largo máximo del nombre de una checklist y del texto de sus elementos
+	MaxChecklistText = 500
+	// checklistRetries son los intentos de devolver un elemento a su checklist cuando otro usuario modificó la tarea al mismo tiempo
+	checklistRetries = 3
+)
+
+var (
+	ErrChecklistNotFound     = errors.New("la checklist no existe en la tarea")
+	ErrChecklistItemNotFound = errors.New("el elemento no existe en la checklist")
+	ErrInvalidChecklistText  = errors.New("el texto no puede estar vacío ni superar los 500 caracteres")
+	ErrInvalidItemNeighbor   = errors.New("el elemento vecino no está en la checklist destino")
+	ErrItemNeighborConflict  = errors.New("los elementos vecinos cambiaron de posición")
+	ErrAssigneeNotMember     = errors.New("el usuario asignado no es miembro del board")
+)
+
+// ChecklistItemInput son los datos editables de un elemento, AssigneeID y DueAt vacíos lo dejan sin asignar y sin fecha
+type ChecklistItemInput struct {
+	Text       string
+	AssigneeID string
+	DueAt      *time.Time
+}
+
+// ItemPlacement indica a qué checklist mover un elemento y entre qué elementos queda.
+// Sin ChecklistID se queda en su checklist; sin vecinos pasa al final.
+type ItemPlacement struct {
+	ChecklistID string
+	BeforeID    string // Elemento que queda inmediatamente arriba
+	AfterID     string // Elemento que queda inmediatamente abajo
+}
+
+// ChecklistProgressOf calcula cuántos elementos de todas las checklists están marcados
+func ChecklistProgressOf(checklists []models.Checklist) models.ChecklistProgress {
+	var progress models.ChecklistProgress
+	for _, checklist := range checklists {
+		for _, item := range checklist.Items {
+			progress.Total++
+			if item.Done {
+				progress.Done++
+			}
+		}
+	}
+	if progress.Total > 0 {
+		progress.Ratio = float64(progress.Done) / float64(progress.Total)
+	}
+	return progress
+}
+
+// ReorderChecklistItem devuelve una copia de las checklists con el elemento en la posición indicada por target,
+// dentro de su checklist o en otra
+func ReorderChecklistItem(checklists []models.Checklist, checklistID, itemID string, target ItemPlacement) ([]models.Checklist, error) {
+	checklists = cloneChecklists(checklists)
+	from, index, err := findChecklistItem(checklists, checklistID, itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	to := from
+	if target.ChecklistID != "" {
+		if to, err = findChecklist(checklists, target.ChecklistID); err != nil {
+			return nil, err
+		}
+	}
+
+	item := checklists[from].Items[index]
+	checklists[from].Items = slices.Delete(checklists[from].Items, index, index+1)
+	items := checklists[to].Items
+
+	position := len(items)
+	if target.BeforeID != "" {
+		before := findItem(items, target.BeforeID)
+		if before < 0 {
+			return nil, ErrInvalidItemNeighbor
+		}
+		position = before + 1
+	}
+	if target.AfterID != "" {
+		after := findItem(items, target.AfterID)
+		if after < 0 {
+			return nil, ErrInvalidItemNeighbor
+		}
+		if target.BeforeID != "" && after != position {
+			return nil, ErrItemNeighborConflict
+		}
+		position = after
+	}
+
+	checklists[to].Items = slices.Insert(items, position, item)
+	return checklists, nil
+}
+
+// AddChecklist agrega una checklist vacía al final de las de la tarea
+func (s *TaskService) AddChecklist(ctx context.Context, task *models.Task, name string, version int64) (*models.Task, error) {
+	name, err := validChecklistText(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		checklist := models.Checklist{ID: primitive.NewObjectID(), Name: name, Items: []models.ChecklistItem{}}
+		return append(checklists, checklist), nil
+	})
+}
+
+// RenameChecklist cambia el nombre de la checklist
+func (s *TaskService) RenameChecklist(ctx context.Context, task *models.Task, checklistID, name string, version int64) (*models.Task, error) {
+	name, err := validChecklistText(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		index, err := findChecklist(checklists, checklistID)
+		if err != nil {
+			return nil, err
+		}
+		checklists[index].Name = name
+		return checklists, nil
+	})
+}
+
+// DeleteChecklist elimina la checklist con todos sus elementos
+func (s *TaskService) DeleteChecklist(ctx context.Context, task *models.Task, checklistID string, version int64) (*models.Task, error) {
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		index, err := findChecklist(checklists, checklistID)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Delete(checklists, index, index+1), nil
+	})
+}
+
+// AddChecklistItem agrega un elemento sin marcar al final de la checklist
+func (s *TaskService) AddChecklistItem(ctx context.Context, task *models.Task, checklistID string, input ChecklistItemInput, version int64) (*models.Task, error) {
+	input, err := s.validChecklistItem(task.BoardID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		index, err := findChecklist(checklists, checklistID)
+		if err != nil {
+			return nil, err
+		}
+		item := models.ChecklistItem{ID: primitive.NewObjectID(), Text: input.Text, AssigneeID: input.AssigneeID, DueAt: input.DueAt}
+		checklists[index].Items = append(checklists[index].Items, item)
+		return checklists, nil
+	})
+}
+
+// UpdateChecklistItem reemplaza el texto, el asignado y la fecha límite del elemento, conserva si está marcado
+func (s *TaskService) UpdateChecklistItem(ctx context.Context, task *models.Task, checklistID, itemID string, input ChecklistItemInput, version int64) (*models.Task, error) {
+	input, err := s.validChecklistItem(task.BoardID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		checklist, index, err := findChecklistItem(checklists, checklistID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		item := &checklists[checklist].Items[index]
+		item.Text, item.AssigneeID, item.DueAt = input.Text, input.AssigneeID, input.DueAt
+		return checklists, nil
+	})
+}
+
+// ToggleChecklistItem marca o desmarca el elemento
+func (s *TaskService) ToggleChecklistItem(ctx context.Context, task *models.Task, checklistID, itemID string, done bool, version int64) (*models.Task, error) {
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		checklist, index, err := findChecklistItem(checklists, checklistID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		checklists[checklist].Items[index].Done = done
+		return checklists, nil
+	})
+}
+
+// MoveChecklistItem cambia el orden del elemento o lo pasa a otra checklist de la misma tarea
+func (s *TaskService) MoveChecklistItem(ctx context.Context, task *models.Task, checklistID, itemID string, target ItemPlacement, version int64) (*models.Task, error) {
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		return ReorderChecklistItem(checklists, checklistID, itemID, target)
+	})
+}
+
+// DeleteChecklistItem elimina el elemento de la checklist
+func (s *TaskService) DeleteChecklistItem(ctx context.Context, task *models.Task, checklistID, itemID string, version int64) (*models.Task, error) {
+	return s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		checklist, index, err := findChecklistItem(checklists, checklistID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		checklists[checklist].Items = slices.Delete(checklists[checklist].Items, index, index+1)
+		return checklists, nil
+	})
+}
+
+// ConvertChecklistItem saca el elemento de la checklist y lo convierte en una tarea al final de la lista de la tarea
+// original, con el mismo asignado y fecha límite. Devuelve la tarea creada y la tarea original actualizada.
+func (s *TaskService) ConvertChecklistItem(ctx context.Context, task *models.Task, checklistID, itemID, userID string, version int64) (*models.Task, *models.Task, error) {
+	var item models.ChecklistItem
+	var position int
+	source, err := s.updateChecklists(ctx, task, version, func(checklists []models.Checklist) ([]models.Checklist, error) {
+		checklist, index, err := findChecklistItem(checklists, checklistID, itemID)
+		if err != nil {
+			return nil, err
+		}
+		item, position = checklists[checklist].Items[index], index
+		checklists[checklist].Items = slices.Delete(checklists[checklist].Items, index, index+1)
+		return checklists, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	converted := &models.Task{Title: item.Text, BoardID: source.BoardID, ListID: source.ListID, AssigneeID: item.AssigneeID, DueAt: item.DueAt}
+	id, err := s.CreateTask(ctx, converted, userID)
+	if err != nil {
+		// La tarea no se creó, el elemento vuelve a su lugar en la checklist si aún existe
+		_, rollbackErr := s.retryChecklists(ctx, source, func(checklists []models.Checklist) ([]models.Checklist, error) {
+			index, err := findChecklist(checklists, checklistID)
+			if err != nil {
+				return nil, err
+			}
+			items := checklists[index].Items
+			checklists[index].Items = slices.Insert(items, min(position, len(items)), item)
+			return checklists, nil
+		})
+		if rollbackErr != nil {
+			// El elemento ya no está en la checklist ni se convirtió en tarea, se registra su texto para poder recuperarlo
+			logger.Log.Error("❌ Error devolviendo el elemento a la checklist tras fallar su conversión",
+				zap.String("taskID", source.ID.Hex()), zap.String("checklistID", checklistID), zap.String("itemID", itemID),
+				zap.String("text", item.Text), zap.Error(rollbackErr))
+		}
+		return nil, nil, err
+	}
+
+	converted.ID = id.(primitive.ObjectID)
+	return prepareTask(converted), source, nil
+}
+
+// updateChecklists aplica change sobre una copia de las checklists y la guarda solo si la tarea sigue en la versión
+// que editó el cliente, si otro cambio se adelantó devuelve repositories.ErrVersionConflict.
+// Devuelve la tarea actualizada.
+func (s *TaskService) updateChecklists(ctx context.Context, task *models.Task, version int64, change func([]models.Checklist) ([]models.Checklist, error)) (*models.Task, error) {
+	checklists, err := change(cloneChecklists(task.Checklists))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.SetTaskChecklists(ctx, task.ID, checklists, version); err != nil {
+		return nil, err
+	}
+	return s.GetTaskByID(ctx, task.ID.Hex())
+}
+
+// retryChecklists es updateChecklists para los cambios que no vienen de una versión que vio el cliente,
+// como devolver un elemento a su checklist: si otro cambio se adelantó recarga la tarea y lo vuelve a intentar
+func (s *TaskService) retryChecklists(ctx context.Context, task *models.Task, change func([]models.Checklist) ([]models.Checklist, error)) (*models.Task, error) {
+	for attempt := 1; ; attempt++ {
+		updated, err := s.updateChecklists(ctx, task, task.Version, change)
+		if !errors.Is(err, repositories.ErrVersionConflict) || attempt == checklistRetries {
+			return updated, err
+		}
+
+		if task, err = s.repo.GetTaskByID(ctx, task.ID.Hex()); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// validChecklistItem limpia el texto y verifica que el asignado sea miembro del board
+func (s *TaskService) validChecklistItem(boardID string, input ChecklistItemInput) (ChecklistItemInput, error) {
+	text, err := validChecklistText(input.Text)
+	if err != nil {
+		return input, err
+	}
+	input.Text = text
+
+	if input.AssigneeID != "" {
+		board, err := s.board(boardID)
+		if err != nil {
+			return input, err
+		}
+		if MemberRole(board, input.AssigneeID) == "" {
+			return input, ErrAssigneeNotMember
+		}
+	}
+	return input, nil
+}
+
+func validChecklistText(text string) (string, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || utf8.RuneCountInString(text) > MaxChecklistText {
+		return "", ErrInvalidChecklistText
+	}
+	return text, nil
+}
+
+func cloneChecklists(checklists []models.Checklist) []models.Checklist {
+	cloned := make([]models.Checklist, len(checklists))
+	for i, checklist := range checklists {
+		checklist.Items = slices.Clone(checklist.Items)
+		if checklist.Items == nil {
+			checklist.Items = []models.ChecklistItem{}
+		}
+		cloned[i] = checklist
+	}
+	return cloned
+}
+
+func findChecklist(checklists []models.Checklist, checklistID string) (int, error) {
+	index := slices.IndexFunc(checklists, func(checklist models.Checklist) bool {
+		return checklist.ID.Hex() == checklistID
+	})
+	if index < 0 {
+		return 0, ErrChecklistNotFound
+	}
+	return index, nil
+}
+
+func findItem(items []models.ChecklistItem, itemID string) int {
+	return slices.IndexFunc(items, func(item models.ChecklistItem) bool {
+		return item.ID.Hex() == itemID
+	})
+}
+
+func findChecklistItem(checklists []models.Checklist, checklistID, itemID string) (int, int, error) {
+	checklist, err := findChecklist(checklists, checklistID)
+	if err != nil {
+		return 0, 0, err
+	}
+	index := findItem(checklists[checklist].Items, itemID)
+	if index < 0 {
+		return 0, 0, ErrChecklistItemNotFound
+	}
+	return checklist, index, nil
+}
@@ -0,0 +1,154 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func checklist(texts ...string) models.Checklist {
+	items := []models.ChecklistItem{}
+	for _, text := range texts {
+		items = append(items, models.ChecklistItem{ID: primitive.NewObjectID(), Text: text})
+	}
+	return models.Checklist{ID: primitive.NewObjectID(), Items: items}
+}
+
+func itemTexts(checklist models.Checklist) []string {
+	texts := []string{}
+	for _, item := range checklist.Items {
+		texts = append(texts, item.Text)
+	}
+	return texts
+}
+
+func TestChecklistProgressOf(t *testing.T) {
+	first, second := checklist("a", "b", "c"), checklist("d")
+	first.Items[0].Done, second.Items[0].Done = true, true
+
+	progress := services.ChecklistProgressOf([]models.Checklist{first, second})
+
+	assert.Equal(t, models.ChecklistProgress{Done: 2, Total: 4, Ratio: 0.5}, progress)
+	assert.Equal(t, models.ChecklistProgress{}, services.ChecklistProgressOf(nil))
+}
+
+func TestReorderChecklistItem(t *testing.T) {
+	list := checklist("a", "b", "c", "d")
+	id := func(i int) string { return list.Items[i].ID.Hex() }
+
+	// "d" entre "a" y "b"
+	reordered, err := services.ReorderChecklistItem([]models.Checklist{list}, list.ID.Hex(), id(3), services.ItemPlacement{BeforeID: id(0), AfterID: id(1)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "d", "b", "c"}, itemTexts(reordered[0]))
+
+	// "a" al final
+	reordered, err = services.ReorderChecklistItem([]models.Checklist{list}, list.ID.Hex(), id(0), services.ItemPlacement{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "d", "a"}, itemTexts(reordered[0]))
+
+	// Los vecinos indicados ya no son consecutivos
+	_, err = services.ReorderChecklistItem([]models.Checklist{list}, list.ID.Hex(), id(3), services.ItemPlacement{BeforeID: id(0), AfterID: id(2)})
+	assert.ErrorIs(t, err, services.ErrItemNeighborConflict)
+}
+
+func TestReorderChecklistItem_ToOtherChecklist(t *testing.T) {
+	from, to := checklist("a", "b"), checklist("c")
+
+	reordered, err := services.ReorderChecklistItem([]models.Checklist{from, to}, from.ID.Hex(), from.Items[1].ID.Hex(),
+		services.ItemPlacement{ChecklistID: to.ID.Hex(), AfterID: to.Items[0].ID.Hex()})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, itemTexts(reordered[0]))
+	assert.Equal(t, []string{"b", "c"}, itemTexts(reordered[1]))
+}
+
+func TestConvertChecklistItem_LogsFailedRollback(t *testing.T) {
+	core, logs := observer.New(zapcore.ErrorLevel)
+	logger.Log = zap.New(core)
+	defer func() { logger.Log = nil }()
+
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	list := checklist("a", "b")
+	task := &models.Task{ID: primitive.NewObjectID(), BoardID: primitive.NewObjectID().Hex(), Checklists: []models.Checklist{list}, Version: 1}
+	source := *task
+	source.Checklists = []models.Checklist{{ID: list.ID, Items: list.Items[:1]}}
+	source.Version = 2
+
+	// El elemento sale de la checklist, la tarea no se puede crear y al devolverlo Mongo falla
+	mockRepo.On("SetTaskChecklists", mock.Anything, task.ID, mock.Anything, int64(1)).Return(nil)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&source, nil)
+	createErr := errors.New("board no encontrado")
+	mockRepo.On("GetBoardByID", task.BoardID).Return(nil, createErr)
+	rollbackErr := errors.New("mongo caído")
+	mockRepo.On("SetTaskChecklists", mock.Anything, task.ID, mock.Anything, int64(2)).Return(rollbackErr)
+
+	converted, updated, err := service.ConvertChecklistItem(context.Background(), task, list.ID.Hex(), list.Items[1].ID.Hex(), "editor", 1)
+
+	assert.Nil(t, converted)
+	assert.Nil(t, updated)
+	assert.ErrorIs(t, err, createErr)
+	if assert.Equal(t, 1, logs.Len()) {
+		fields := logs.All()[0].ContextMap()
+		assert.Equal(t, list.Items[1].ID.Hex(), fields["itemID"])
+		assert.Equal(t, "b", fields["text"])
+		assert.Equal(t, rollbackErr.Error(), fields["error"])
+	}
+}
+
+func TestToggleChecklistItem_StaleVersion(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	list := checklist("a")
+	task := &models.Task{ID: primitive.NewObjectID(), Checklists: []models.Checklist{list}, Version: 4}
+
+	// El cliente editó la versión 3, el cambio de otro usuario no se sobrescribe ni se reintenta
+	mockRepo.On("SetTaskChecklists", mock.Anything, task.ID, mock.Anything, int64(3)).Return(repositories.ErrVersionConflict)
+
+	updated, err := service.ToggleChecklistItem(context.Background(), task, list.ID.Hex(), list.Items[0].ID.Hex(), true, 3)
+
+	assert.Nil(t, updated)
+	assert.ErrorIs(t, err, repositories.ErrVersionConflict)
+	mockRepo.AssertNumberOfCalls(t, "SetTaskChecklists", 1)
+	mockRepo.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.Anything)
+}
+
+func TestConvertChecklistItem_KeepsAssigneeAndDueAt(t *testing.T) {
+	board := memberBoard()
+	boardID := board.ID.Hex()
+	mockRepo := createTaskRepo(boardID)
+	service := services.NewTaskService(mockRepo)
+	dueAt := time.Now().Add(48 * time.Hour).Truncate(time.Second)
+	list := checklist("a")
+	list.Items[0].AssigneeID, list.Items[0].DueAt = "viewer", &dueAt
+	task := &models.Task{ID: primitive.NewObjectID(), BoardID: boardID, Checklists: []models.Checklist{list}, Version: 1}
+	source := *task
+	source.Checklists = []models.Checklist{{ID: list.ID, Items: []models.ChecklistItem{}}}
+	source.Version = 2
+
+	mockRepo.On("SetTaskChecklists", mock.Anything, task.ID, mock.Anything, int64(1)).Return(nil)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&source, nil)
+	mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(created *models.Task) bool {
+		return created.Title == "a" && created.AssigneeID == "viewer" && created.DueAt != nil && created.DueAt.Equal(dueAt) && !created.NoDueAt
+	}), "editor").Return(primitive.NewObjectID(), nil)
+
+	converted, updated, err := service.ConvertChecklistItem(context.Background(), task, list.ID.Hex(), list.Items[0].ID.Hex(), "editor", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &dueAt, converted.DueAt)
+	assert.Equal(t, int64(2), updated.Version)
+	mockRepo.AssertExpectations(t)
+}
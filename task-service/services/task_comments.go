@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const MaxCommentLength = 5000
+
+var (
+	// ErrInvalidComment indica un comentario vacío o demasiado largo
+	ErrInvalidComment = errors.New("el comentario no puede estar vacío ni superar los 5000 caracteres")
+	// ErrNestedReply indica que se intentó responder a una respuesta, solo hay un nivel de respuestas
+	ErrNestedReply = errors.New("solo se puede responder a comentarios de primer nivel")
+	// ErrNotCommentAuthor indica que solo el autor puede editar el comentario
+	ErrNotCommentAuthor = errors.New("solo el autor puede editar el comentario")
+)
+
+// mentionPattern reconoce @email y @id de usuario, precedidos por inicio de texto o un carácter que no sea de palabra
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+|[0-9a-fA-F]{24})\b`)
+
+// ParseMentions devuelve sin duplicados los emails y los IDs de usuario mencionados con @ en el texto
+func ParseMentions(body string) []string {
+	mentions := []string{}
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		mention := match[1]
+		if !slices.Contains(mentions, mention) {
+			mentions = append(mentions, mention)
+		}
+	}
+	return mentions
+}
+
+// GetComments devuelve una página de comentarios de primer nivel de la tarea con sus respuestas
+func (s *TaskService) GetComments(ctx context.Context, taskID string, page, limit int64) ([]models.CommentThread, int64, error) {
+	comments, total, err := s.repo.GetComments(ctx, taskID, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	parentIDs := make([]string, 0, len(comments))
+	for _, comment := range comments {
+		parentIDs = append(parentIDs, comment.ID.Hex())
+	}
+	replies, err := s.repo.GetReplies(ctx, parentIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	threads := make([]models.CommentThread, 0, len(comments))
+	for _, comment := range comments {
+		thread := models.CommentThread{Comment: comment, Replies: []models.Comment{}}
+		for _, reply := range replies {
+			if reply.ParentID == comment.ID.Hex() {
+				thread.Replies = append(thread.Replies, reply)
+			}
+		}
+		threads = append(threads, thread)
+	}
+	return threads, total, nil
+}
+
+// GetComment obtiene un comentario de la tarea, si pertenece a otra tarea devuelve repositories.ErrCommentNotFound
+func (s *TaskService) GetComment(ctx context.Context, taskID, commentID string) (*models.Comment, error) {
+	comment, err := s.repo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.TaskID != taskID {
+		return nil, repositories.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// CreateComment comenta la tarea, o responde al comentario parentID si no es vacío
+func (s *TaskService) CreateComment(ctx context.Context, task *models.Task, authorID, parentID, body string) (*models.Comment, error) {
+	body, err := validComment(body)
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID != "" {
+		parent, err := s.GetComment(ctx, task.ID.Hex(), parentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ParentID != "" {
+			return nil, ErrNestedReply
+		}
+	}
+
+	mentions, err := s.resolveMentions(ctx, task.BoardID, body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		TaskID:    task.ID.Hex(),
+		BoardID:   task.BoardID,
+		ParentID:  parentID,
+		AuthorID:  authorID,
+		Body:      body,
+		Mentions:  mentions,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// UpdateComment cambia el cuerpo del comentario, solo su autor puede editarlo.
+// Devuelve el comentario actualizado y los usuarios mencionados por primera vez.
+func (s *TaskService) UpdateComment(ctx context.Context, comment *models.Comment, actorID, body string) (*models.Comment, []string, error) {
+	if comment.AuthorID != actorID {
+		return nil, nil, ErrNotCommentAuthor
+	}
+	body, err := validComment(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mentions, err := s.resolveMentions(ctx, comment.BoardID, body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	editedAt := time.Now()
+	if err := s.repo.UpdateComment(ctx, comment.ID, body, mentions, editedAt); err != nil {
+		return nil, nil, err
+	}
+
+	newMentions := []string{}
+	for _, userID := range mentions {
+		if !slices.Contains(comment.Mentions, userID) {
+			newMentions = append(newMentions, userID)
+		}
+	}
+
+	updated := *comment
+	updated.Body, updated.Mentions, updated.EditedAt = body, mentions, &editedAt
+	return &updated, newMentions, nil
+}
+
+// DeleteComment elimina el comentario y sus respuestas, lo puede hacer su autor o un admin del board
+func (s *TaskService) DeleteComment(ctx context.Context, comment *models.Comment, actorID string) error {
+	if comment.AuthorID != actorID {
+		if _, err := s.authorize(comment.BoardID, actorID, models.RoleAdmin); err != nil {
+			return err
+		}
+	}
+	return s.repo.DeleteComment(ctx, comment.ID)
+}
+
+// resolveMentions busca en la copia local de usuarios a los mencionados en el texto y devuelve los IDs
+// de los que son miembros del board, las menciones que no corresponden a ningún miembro se ignoran
+func (s *TaskService) resolveMentions(ctx context.Context, boardID, body string) ([]string, error) {
+	mentions := ParseMentions(body)
+	if len(mentions) == 0 {
+		return []string{}, nil
+	}
+
+	var ids []primitive.ObjectID
+	var emails []string
+	for _, mention := range mentions {
+		if id, err := primitive.ObjectIDFromHex(mention); err == nil {
+			ids = append(ids, id)
+		} else {
+			emails = append(emails, mention)
+		}
+	}
+
+	users, err := s.repo.FindUsers(ctx, ids, emails)
+	if err != nil {
+		return nil, err
+	}
+	board, err := s.board(boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := []string{}
+	for _, user := range users {
+		userID := user.ID.Hex()
+		if MemberRole(board, userID) != "" && !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs, nil
+}
+
+func validComment(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > MaxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
@@ -0,0 +1,188 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseMentions(t *testing.T) {
+	body := "Hola @ana@correo.com, revisa esto con @64b7f0c2a1b2c3d4e5f60718. @ana@correo.com otra vez"
+
+	assert.Equal(t, []string{"ana@correo.com", "64b7f0c2a1b2c3d4e5f60718"}, services.ParseMentions(body))
+}
+
+func TestParseMentions_IgnoresEmailsAndShortIDs(t *testing.T) {
+	body := "escribe a soporte@correo.com o a @123abc, sin mencionar a nadie"
+
+	assert.Empty(t, services.ParseMentions(body))
+}
+
+// commentedTask prepara una tarea de un board donde ana y beto son miembros, carla es usuaria pero no es miembro
+func commentedTask(mockRepo *repomocks.MockTaskRepo) (task *models.Task, ana, beto, carla models.User) {
+	ana = models.User{ID: primitive.NewObjectID(), Email: "ana@correo.com"}
+	beto = models.User{ID: primitive.NewObjectID(), Email: "beto@correo.com"}
+	carla = models.User{ID: primitive.NewObjectID(), Email: "carla@correo.com"}
+	board := &models.Board{
+		ID:      primitive.NewObjectID(),
+		OwnerID: ana.ID.Hex(),
+		Members: []models.BoardMember{
+			{UserID: ana.ID.Hex(), Role: models.RoleOwner},
+			{UserID: beto.ID.Hex(), Role: models.RoleViewer},
+		},
+	}
+	mockRepo.On("GetBoardByID", board.ID.Hex()).Return(board, nil)
+	return &models.Task{ID: primitive.NewObjectID(), BoardID: board.ID.Hex()}, ana, beto, carla
+}
+
+func TestTaskService_CreateComment_ResolvesMentionsOfMembers(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, beto, carla := commentedTask(mockRepo)
+	unknown := primitive.NewObjectID()
+
+	body := "@ana@correo.com y @" + beto.ID.Hex() + " revisen, también @" + ana.ID.Hex() +
+		", @carla@correo.com, @nadie@correo.com y @" + unknown.Hex()
+	// Los IDs y los emails se buscan juntos, la copia local no tiene a nadie@correo.com ni al ID desconocido
+	mockRepo.On("FindUsers", mock.Anything, []primitive.ObjectID{beto.ID, ana.ID, unknown}, []string{"ana@correo.com", "carla@correo.com", "nadie@correo.com"}).
+		Return([]models.User{ana, beto, carla}, nil)
+	mockRepo.On("CreateComment", mock.Anything, mock.Anything).Return(nil)
+
+	comment, err := service.CreateComment(context.Background(), task, ana.ID.Hex(), "", body)
+
+	// carla no es miembro del board y ana se menciona dos veces pero se notifica una
+	assert.NoError(t, err)
+	assert.Equal(t, []string{ana.ID.Hex(), beto.ID.Hex()}, comment.Mentions)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_CreateComment_WithoutMentions(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, _, _ := commentedTask(mockRepo)
+
+	mockRepo.On("CreateComment", mock.Anything, mock.Anything).Return(nil)
+
+	comment, err := service.CreateComment(context.Background(), task, ana.ID.Hex(), "", "  escribe a soporte@correo.com  ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "escribe a soporte@correo.com", comment.Body)
+	assert.Equal(t, []string{}, comment.Mentions)
+	mockRepo.AssertNotCalled(t, "FindUsers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTaskService_CreateComment_InvalidBody(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, _, _ := commentedTask(mockRepo)
+
+	for _, body := range []string{"", "   ", strings.Repeat("a", services.MaxCommentLength+1)} {
+		_, err := service.CreateComment(context.Background(), task, ana.ID.Hex(), "", body)
+		assert.ErrorIs(t, err, services.ErrInvalidComment)
+	}
+	mockRepo.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_CreateComment_Reply(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, _, _ := commentedTask(mockRepo)
+	parent := &models.Comment{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), BoardID: task.BoardID}
+
+	mockRepo.On("GetCommentByID", mock.Anything, parent.ID.Hex()).Return(parent, nil)
+	mockRepo.On("CreateComment", mock.Anything, mock.MatchedBy(func(comment *models.Comment) bool {
+		return comment.ParentID == parent.ID.Hex()
+	})).Return(nil)
+
+	comment, err := service.CreateComment(context.Background(), task, ana.ID.Hex(), parent.ID.Hex(), "De acuerdo")
+
+	assert.NoError(t, err)
+	assert.Equal(t, parent.ID.Hex(), comment.ParentID)
+	mockRepo.AssertCalled(t, "CreateComment", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_CreateComment_InvalidParent(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent func(task *models.Task) *models.Comment
+		err    error
+	}{
+		{
+			name: "respuesta a una respuesta",
+			parent: func(task *models.Task) *models.Comment {
+				return &models.Comment{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), ParentID: primitive.NewObjectID().Hex()}
+			},
+			err: services.ErrNestedReply,
+		},
+		{
+			name: "comentario de otra tarea",
+			parent: func(task *models.Task) *models.Comment {
+				return &models.Comment{ID: primitive.NewObjectID(), TaskID: primitive.NewObjectID().Hex()}
+			},
+			err: repositories.ErrCommentNotFound,
+		},
+		{
+			name:   "comentario inexistente",
+			parent: func(task *models.Task) *models.Comment { return nil },
+			err:    repositories.ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		mockRepo := new(repomocks.MockTaskRepo)
+		service := services.NewTaskService(mockRepo)
+		task, ana, _, _ := commentedTask(mockRepo)
+
+		parentID := primitive.NewObjectID().Hex()
+		if parent := tt.parent(task); parent != nil {
+			parentID = parent.ID.Hex()
+			mockRepo.On("GetCommentByID", mock.Anything, parentID).Return(parent, nil)
+		} else {
+			mockRepo.On("GetCommentByID", mock.Anything, parentID).Return(nil, repositories.ErrCommentNotFound)
+		}
+
+		_, err := service.CreateComment(context.Background(), task, ana.ID.Hex(), parentID, "Respuesta")
+
+		assert.ErrorIs(t, err, tt.err, tt.name)
+		mockRepo.AssertNotCalled(t, "CreateComment", mock.Anything, mock.Anything)
+	}
+}
+
+func TestTaskService_UpdateComment_ReturnsNewMentions(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, beto, _ := commentedTask(mockRepo)
+	comment := &models.Comment{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), BoardID: task.BoardID, AuthorID: ana.ID.Hex(), Mentions: []string{ana.ID.Hex()}}
+
+	mockRepo.On("FindUsers", mock.Anything, []primitive.ObjectID(nil), []string{"ana@correo.com", "beto@correo.com"}).
+		Return([]models.User{ana, beto}, nil)
+	mockRepo.On("UpdateComment", mock.Anything, comment.ID, "@ana@correo.com y @beto@correo.com", []string{ana.ID.Hex(), beto.ID.Hex()}, mock.Anything).Return(nil)
+
+	updated, newMentions, err := service.UpdateComment(context.Background(), comment, ana.ID.Hex(), "@ana@correo.com y @beto@correo.com")
+
+	// ana ya estaba mencionada, solo beto recibe una notificación nueva
+	assert.NoError(t, err)
+	assert.Equal(t, []string{beto.ID.Hex()}, newMentions)
+	assert.NotNil(t, updated.EditedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_UpdateComment_NotAuthor(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, ana, beto, _ := commentedTask(mockRepo)
+	comment := &models.Comment{ID: primitive.NewObjectID(), TaskID: task.ID.Hex(), BoardID: task.BoardID, AuthorID: ana.ID.Hex()}
+
+	_, _, err := service.UpdateComment(context.Background(), comment, beto.ID.Hex(), "Otro texto")
+
+	assert.ErrorIs(t, err, services.ErrNotCommentAuthor)
+	mockRepo.AssertNotCalled(t, "UpdateComment", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrUnknownLabel indica una etiqueta que no existe en el board de la tarea
+var ErrUnknownLabel = errors.New("la etiqueta no existe en el board de la tarea")
+
+// validateLabels quita los IDs repetidos y verifica que todas las etiquetas existan en el board
+func (s *TaskService) validateLabels(ctx context.Context, boardID string, labelIDs []string) ([]string, error) {
+	unique := []string{}
+	for _, labelID := range labelIDs {
+		if !slices.Contains(unique, labelID) {
+			unique = append(unique, labelID)
+		}
+	}
+	if len(unique) == 0 {
+		return unique, nil
+	}
+
+	labels, err := s.repo.GetBoardLabels(ctx, boardID, unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(labels) != len(unique) {
+		return nil, ErrUnknownLabel
+	}
+	return unique, nil
+}
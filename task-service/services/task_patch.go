@@ -0,0 +1,155 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+)
+
+const (
+	MaxTaskTitleLength       = 200
+	MaxTaskDescriptionLength = 10000
+)
+
+// FieldErrors son los errores de validación por campo, se responden con 422
+type FieldErrors map[string]string
+
+// taskPatchFields son los campos que se pueden editar con PUT /tasks/:taskID. Cada uno valida su valor
+// y lo asigna al patch, devolviendo el mensaje de error si no es válido
+var taskPatchFields = map[string]func(raw json.RawMessage, patch *models.TaskPatch) string{
+	"title": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		title, message := textField(raw, MaxTaskTitleLength, true)
+		if message == "" {
+			patch.Title = &title
+		}
+		return message
+	},
+	"description": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		description, message := textField(raw, MaxTaskDescriptionLength, false)
+		if message == "" {
+			patch.Description = &description
+		}
+		return message
+	},
+	"labels": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		var labels []string
+		if err := json.Unmarshal(raw, &labels); err != nil || labels == nil {
+			return "Debe ser una lista de IDs de etiquetas"
+		}
+		patch.Labels = &labels
+		return ""
+	},
+	"start_at": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		date, message := dateField(raw)
+		if message == "" {
+			patch.StartAt = &date
+		}
+		return message
+	},
+	"due_at": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		date, message := dateField(raw)
+		if message == "" {
+			patch.DueAt = &date
+		}
+		return message
+	},
+	"priority": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		var priority *models.TaskPriority
+		if err := json.Unmarshal(raw, &priority); err != nil {
+			return ErrInvalidPriority.Error()
+		}
+		if priority == nil {
+			priority = new(models.TaskPriority)
+		}
+		if !priority.Valid() {
+			return ErrInvalidPriority.Error()
+		}
+		patch.Priority = priority
+		return ""
+	},
+	"estimate": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		var estimate *float64
+		if err := json.Unmarshal(raw, &estimate); err != nil || (estimate != nil && !validEstimate(*estimate)) {
+			return ErrInvalidEstimate.Error()
+		}
+		patch.Estimate, patch.ClearEstimate = estimate, estimate == nil
+		return ""
+	},
+	"completed": func(raw json.RawMessage, patch *models.TaskPatch) string {
+		var completed *bool
+		if err := json.Unmarshal(raw, &completed); err != nil || completed == nil {
+			return "Debe ser true o false"
+		}
+		patch.Completed = completed
+		return ""
+	},
+}
+
+// taskManagedFields son campos de la tarea que tienen su propio endpoint
+var taskManagedFields = map[string]string{
+	"status":      "se cambia con PUT /tasks/:taskID/status",
+	"assignee_id": "se cambia con PUT /tasks/:taskID/assign",
+	"board_id":    "se cambia con PUT /tasks/:taskID/move",
+	"list_id":     "se cambia con PUT /tasks/:taskID/move",
+	"position":    "se cambia con PUT /tasks/:taskID/move",
+}
+
+// ParseTaskPatch convierte el JSON recibido en un TaskPatch. Solo acepta los campos editables,
+// cualquier otro campo (user_id, created_at, operadores de mongo...) se rechaza con su error.
+func ParseTaskPatch(body map[string]json.RawMessage) (*models.TaskPatch, FieldErrors) {
+	patch := &models.TaskPatch{}
+	errs := FieldErrors{}
+
+	for field, raw := range body {
+		apply, ok := taskPatchFields[field]
+		if !ok {
+			if hint, managed := taskManagedFields[field]; managed {
+				errs[field] = "El campo " + hint
+			} else {
+				errs[field] = "El campo no se puede editar"
+			}
+			continue
+		}
+		if message := apply(raw, patch); message != "" {
+			errs[field] = message
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return patch, nil
+}
+
+// dateField valida una fecha RFC 3339, null devuelve la fecha cero para borrarla
+func dateField(raw json.RawMessage) (time.Time, string) {
+	var value *time.Time
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return time.Time{}, "Debe ser una fecha RFC 3339 o null"
+	}
+	if value == nil {
+		return time.Time{}, ""
+	}
+	return value.UTC(), ""
+}
+
+// textField valida un texto sin espacios al inicio ni al final con un máximo de caracteres
+func textField(raw json.RawMessage, maxLength int, required bool) (string, string) {
+	var value *string
+	if err := json.Unmarshal(raw, &value); err != nil || value == nil {
+		return "", "Debe ser texto"
+	}
+
+	text := strings.TrimSpace(*value)
+	if required && text == "" {
+		return "", "No puede estar vacío"
+	}
+	if utf8.RuneCountInString(text) > maxLength {
+		return "", fmt.Sprintf("No puede tener más de %d caracteres", maxLength)
+	}
+	return text, ""
+}
@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+func parseBody(t *testing.T, body string) map[string]json.RawMessage {
+	var raw map[string]json.RawMessage
+	assert.NoError(t, json.Unmarshal([]byte(body), &raw))
+	return raw
+}
+
+func TestParseTaskPatch(t *testing.T) {
+	patch, errs := services.ParseTaskPatch(parseBody(t, `{"title": "  Diseño  "}`))
+
+	assert.Nil(t, errs)
+	assert.Equal(t, "Diseño", *patch.Title)
+	assert.Nil(t, patch.Description)
+}
+
+func TestParseTaskPatch_RejectsFields(t *testing.T) {
+	body := `{
+		"title": "",
+		"description": {"$set": "x"},
+		"user_id": "otro",
+		"status": "DONE"
+	}`
+
+	patch, errs := services.ParseTaskPatch(parseBody(t, body))
+
+	assert.Nil(t, patch)
+	assert.Equal(t, "No puede estar vacío", errs["title"])
+	assert.Equal(t, "Debe ser texto", errs["description"])
+	assert.Equal(t, "El campo no se puede editar", errs["user_id"])
+	assert.Contains(t, errs["status"], "/status")
+}
+
+func TestParseTaskPatch_MaxLength(t *testing.T) {
+	title, _ := json.Marshal(strings.Repeat("a", services.MaxTaskTitleLength+1))
+
+	_, errs := services.ParseTaskPatch(map[string]json.RawMessage{"title": title})
+
+	assert.Contains(t, errs, "title")
+}
+
+func TestParseTaskPatch_Labels(t *testing.T) {
+	patch, errs := services.ParseTaskPatch(parseBody(t, `{"labels": ["a", "b"]}`))
+
+	assert.Nil(t, errs)
+	assert.Equal(t, []string{"a", "b"}, *patch.Labels)
+
+	_, errs = services.ParseTaskPatch(parseBody(t, `{"labels": null}`))
+	assert.Contains(t, errs, "labels")
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// PositionStep es la separación entre posiciones al agregar al final o al reordenar toda la lista
+const PositionStep = 1024.0
+
+// minPositionGap es el espacio mínimo entre vecinos, por debajo se reordena la lista para no perder precisión
+const minPositionGap = 1e-9
+
+// maxTieRetries es cuántas veces se intenta separar una tarea que quedó empatada con otra
+const maxTieRetries = 3
+
+// maxRebalanceRetries es cuántas veces se relee la lista si otra petición la modifica durante el reordenamiento
+const maxRebalanceRetries = 3
+
+var (
+	ErrListNotFound     = errors.New("la lista no existe en el board")
+	ErrInvalidNeighbor  = errors.New("la tarea vecina no está en la lista destino")
+	ErrNeighborConflict = errors.New("las tareas vecinas cambiaron de posición")
+)
+
+// TaskPlacement indica a dónde mover una tarea: board, lista y entre qué tareas queda.
+// Los campos vacíos conservan el valor actual; sin vecinos la tarea pasa al final de la lista.
+type TaskPlacement struct {
+	BoardID  string
+	ListID   string
+	BeforeID string // Tarea que queda inmediatamente arriba
+	AfterID  string // Tarea que queda inmediatamente abajo
+}
+
+// PositionBetween calcula una posición entre before y after (cualquiera puede ser nil para los extremos).
+// Devuelve false si ya no hay espacio suficiente entre ambos y hay que reasignar las posiciones.
+func PositionBetween(before, after *float64) (float64, bool) {
+	switch {
+	case before == nil && after == nil:
+		return PositionStep, true
+	case before == nil:
+		return *after - PositionStep, true
+	case after == nil:
+		return *before + PositionStep, true
+	}
+
+	if *after-*before < minPositionGap {
+		return 0, false
+	}
+	return *before + (*after-*before)/2, true
+}
+
+// validateList verifica que la lista exista, pertenezca al board y no esté archivada
+func (s *TaskService) validateList(ctx context.Context, boardID, listID string) error {
+	list, err := s.repo.GetListByID(ctx, listID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return ErrListNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if list.BoardID != boardID || list.Archived {
+		return ErrListNotFound
+	}
+	return nil
+}
+
+// defaultListID devuelve la primera lista del board, vacío si el board aún no tiene listas
+func (s *TaskService) defaultListID(ctx context.Context, boardID string) (string, error) {
+	list, err := s.repo.GetFirstList(ctx, boardID)
+	if err != nil || list == nil {
+		return "", err
+	}
+	return list.ID, nil
+}
+
+// placeNewTask asigna la lista (la indicada o la primera del board) y la posición al final de ella
+func (s *TaskService) placeNewTask(ctx context.Context, task *models.Task) error {
+	var err error
+	if task.ListID == "" {
+		task.ListID, err = s.defaultListID(ctx, task.BoardID)
+	} else {
+		err = s.validateList(ctx, task.BoardID, task.ListID)
+	}
+	if err != nil {
+		return err
+	}
+
+	task.Position, err = s.resolvePosition(ctx, task.BoardID, task.ListID, primitive.NilObjectID, "", "", true)
+	return err
+}
+
+// MoveTask mueve la tarea de board, de lista y/o de posición en una sola actualización del documento.
+// Solo cambia la posición de la tarea movida y solo si sigue en la versión que editó el cliente, si otro cambio la modificó
+// devuelve repositories.ErrVersionConflict; si los vecinos que vio el cliente ya no son consecutivos devuelve ErrNeighborConflict.
+// Dos tareas movidas al mismo hueco a la vez calculan la misma posición, el empate se resuelve después con separateTie.
+func (s *TaskService) MoveTask(ctx context.Context, task *models.Task, target TaskPlacement, version int64) error {
+	boardID := target.BoardID
+	if boardID == "" {
+		boardID = task.BoardID
+	}
+
+	listID := target.ListID
+	var err error
+	switch {
+	case listID != "":
+		err = s.validateList(ctx, boardID, listID)
+	case boardID == task.BoardID:
+		listID = task.ListID
+	default:
+		listID, err = s.defaultListID(ctx, boardID)
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := s.movedTaskStatus(ctx, task, boardID)
+	if err != nil {
+		return err
+	}
+
+	position, err := s.resolvePosition(ctx, boardID, listID, task.ID, target.BeforeID, target.AfterID, true)
+	if err != nil {
+		return err
+	}
+
+	// Las etiquetas son del board, al cambiar de board la tarea se queda sin ellas
+	changedBoard := boardID != task.BoardID
+	if err := s.repo.UpdateTaskPlacement(ctx, task.ID, version, boardID, listID, position, status, changedBoard); err != nil {
+		return err
+	}
+	task.BoardID, task.ListID, task.Position, task.Status = boardID, listID, position, status
+	task.Version = version + 1
+	if changedBoard {
+		task.Labels = []string{}
+	}
+	return s.separateTie(ctx, task)
+}
+
+// YieldsPosition indica si la tarea debe dejar la posición que comparte con las tareas empatadas:
+// la conserva la de menor ID, así todas las tareas empatadas llegan a la misma decisión sin coordinarse
+func YieldsPosition(taskID primitive.ObjectID, tiedIDs []primitive.ObjectID) bool {
+	for _, id := range tiedIDs {
+		if id.Hex() < taskID.Hex() {
+			return true
+		}
+	}
+	return false
+}
+
+// separateTie mueve la tarea justo después de su posición si otra tarea de la lista quedó en la misma posición
+// por un movimiento simultáneo. Si se vuelve a empatar con otra tarea que también se separó se repite.
+func (s *TaskService) separateTie(ctx context.Context, task *models.Task) error {
+	for range maxTieRetries {
+		tiedIDs, err := s.repo.GetTaskIDsAtPosition(ctx, task.BoardID, task.ListID, task.Position, task.ID)
+		if err != nil || !YieldsPosition(task.ID, tiedIDs) {
+			return err
+		}
+
+		next, err := s.repo.GetNeighborTask(ctx, task.BoardID, task.ListID, task.Position, true, task.ID)
+		if err != nil {
+			return err
+		}
+		var nextPosition *float64
+		if next != nil {
+			nextPosition = &next.Position
+		}
+		position, ok := PositionBetween(&task.Position, nextPosition)
+		if !ok {
+			// Sin espacio se reordena la lista, el orden por posición e ID deja de tener empates
+			if err := s.rebalance(ctx, task.BoardID, task.ListID); err != nil {
+				return err
+			}
+			rebalanced, err := s.repo.GetTaskByID(ctx, task.ID.Hex())
+			if err != nil {
+				return err
+			}
+			task.Position = rebalanced.Position
+			task.Version = rebalanced.Version
+			task.UpdatedAt = rebalanced.UpdatedAt
+			return nil
+		}
+
+		err = s.repo.UpdateTaskPosition(ctx, task.ID, task.Version, position)
+		if errors.Is(err, repositories.ErrVersionConflict) {
+			// Otro cambio modificó la tarea después de moverla, el movimiento ya se aplicó y ese cambio queda como el último
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		task.Position = position
+		task.Version++
+	}
+	return nil
+}
+
+// neighbor obtiene la tarea vecina verificando que esté en la lista destino
+func (s *TaskService) neighbor(ctx context.Context, boardID, listID, neighborID string, taskID primitive.ObjectID) (*models.Task, error) {
+	neighbor, err := s.repo.GetTaskByID(ctx, neighborID)
+	if err != nil {
+		return nil, ErrInvalidNeighbor
+	}
+	if neighbor.ID == taskID || neighbor.BoardID != boardID || neighbor.ListID != listID {
+		return nil, ErrInvalidNeighbor
+	}
+	return neighbor, nil
+}
+
+func (s *TaskService) resolvePosition(ctx context.Context, boardID, listID string, taskID primitive.ObjectID, beforeID, afterID string, canRebalance bool) (float64, error) {
+	var before, after *models.Task
+	var err error
+
+	if beforeID != "" {
+		if before, err = s.neighbor(ctx, boardID, listID, beforeID, taskID); err != nil {
+			return 0, err
+		}
+	}
+	if afterID != "" {
+		if after, err = s.neighbor(ctx, boardID, listID, afterID, taskID); err != nil {
+			return 0, err
+		}
+	}
+
+	// Completar el vecino que falta con el que está actualmente junto al indicado
+	switch {
+	case before != nil && after == nil:
+		after, err = s.repo.GetNeighborTask(ctx, boardID, listID, before.Position, true, taskID)
+	case before == nil && after != nil:
+		before, err = s.repo.GetNeighborTask(ctx, boardID, listID, after.Position, false, taskID)
+	case before == nil && after == nil:
+		before, err = s.repo.GetNeighborTask(ctx, boardID, listID, math.Inf(1), false, taskID)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var beforePosition, afterPosition *float64
+	if before != nil {
+		beforePosition = &before.Position
+	}
+	if after != nil {
+		afterPosition = &after.Position
+	}
+	if beforePosition != nil && afterPosition != nil && *beforePosition >= *afterPosition {
+		return 0, ErrNeighborConflict
+	}
+
+	position, ok := PositionBetween(beforePosition, afterPosition)
+	if ok {
+		return position, nil
+	}
+	if !canRebalance {
+		return 0, ErrNeighborConflict
+	}
+
+	// Ya no hay espacio entre los vecinos, se reasignan las posiciones de la lista y se calcula de nuevo
+	if err := s.rebalance(ctx, boardID, listID); err != nil {
+		return 0, err
+	}
+	return s.resolvePosition(ctx, boardID, listID, taskID, beforeID, afterID, false)
+}
+
+// rebalance reasigna posiciones separadas por PositionStep conservando el orden actual de la lista.
+// Cada tarea solo se reescribe si sigue en la versión leída; si otra petición modificó alguna mientras tanto
+// se relee la lista y se vuelve a intentar.
+func (s *TaskService) rebalance(ctx context.Context, boardID, listID string) error {
+	for attempt := 1; ; attempt++ {
+		tasks, err := s.repo.GetTasksByList(ctx, boardID, listID)
+		if err != nil {
+			return err
+		}
+
+		for i := range tasks {
+			tasks[i].Position = float64(i+1) * PositionStep
+		}
+		err = s.repo.SetTaskPositions(ctx, tasks)
+		if !errors.Is(err, repositories.ErrVersionConflict) {
+			return err
+		}
+		if attempt == maxRebalanceRetries {
+			return ErrNeighborConflict
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package services_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// placeTied reproduce separateTie sobre una lista en memoria: cada tarea empatada que debe ceder
+// se mueve entre su posición y la siguiente tarea de la lista, hasta que no quedan empates
+func placeTied(t *testing.T, positions map[primitive.ObjectID]float64) {
+	for range 3 {
+		moved := false
+		for id, position := range positions {
+			var tied []primitive.ObjectID
+			var next *float64
+			for otherID, other := range positions {
+				switch {
+				case otherID == id:
+				case other == position:
+					tied = append(tied, otherID)
+				case other > position && (next == nil || other < *next):
+					next = &other
+				}
+			}
+			if !services.YieldsPosition(id, tied) {
+				continue
+			}
+			newPosition, ok := services.PositionBetween(&position, next)
+			assert.True(t, ok)
+			positions[id] = newPosition
+			moved = true
+		}
+		if !moved {
+			return
+		}
+	}
+}
+
+func TestMoveTask_TwoMovesIntoSameGap(t *testing.T) {
+	before, after := 1024.0, 2048.0
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+
+	// Ambos movimientos leen los mismos vecinos y calculan la misma posición
+	firstPosition, _ := services.PositionBetween(&before, &after)
+	secondPosition, _ := services.PositionBetween(&before, &after)
+	assert.Equal(t, firstPosition, secondPosition)
+
+	positions := map[primitive.ObjectID]float64{
+		primitive.NewObjectID(): before,
+		primitive.NewObjectID(): after,
+		first:                   firstPosition,
+		second:                  secondPosition,
+	}
+	placeTied(t, positions)
+
+	// La tarea de menor ID conserva la posición y la otra queda entre ella y el vecino de abajo
+	assert.Equal(t, firstPosition, positions[first])
+	assert.Greater(t, positions[second], positions[first])
+	assert.Less(t, positions[second], after)
+}
+
+func TestMoveTask_ThreeMovesIntoSameGapEndDistinct(t *testing.T) {
+	after := 2048.0
+	positions := map[primitive.ObjectID]float64{primitive.NewObjectID(): after}
+	for range 3 {
+		positions[primitive.NewObjectID()] = 1536
+	}
+
+	placeTied(t, positions)
+
+	values := make([]float64, 0, len(positions))
+	for _, position := range positions {
+		values = append(values, position)
+	}
+	sort.Float64s(values)
+	for i := 1; i < len(values); i++ {
+		assert.Less(t, values[i-1], values[i])
+	}
+	assert.Equal(t, after, values[len(values)-1])
+}
+
+func TestYieldsPosition(t *testing.T) {
+	low, high := primitive.NewObjectID(), primitive.NewObjectID()
+
+	assert.True(t, services.YieldsPosition(high, []primitive.ObjectID{low}))
+	assert.False(t, services.YieldsPosition(low, []primitive.ObjectID{high}))
+	assert.False(t, services.YieldsPosition(low, nil))
+}
+
+// crowdedList prepara una lista cuyos vecinos ya no tienen espacio entre sí, mover la tarea entre ellos reordena la lista
+func crowdedList(mockRepo *repomocks.MockTaskRepo) (task, before, after *models.Task) {
+	task = &models.Task{ID: primitive.NewObjectID(), BoardID: "board", ListID: "list", Position: 4096, Version: 1}
+	before = &models.Task{ID: primitive.NewObjectID(), BoardID: "board", ListID: "list", Position: 1024, Version: 2}
+	after = &models.Task{ID: primitive.NewObjectID(), BoardID: "board", ListID: "list", Position: 1024 + 1e-10, Version: 5}
+
+	mockRepo.On("GetTaskByID", mock.Anything, before.ID.Hex()).Return(before, nil).Once()
+	mockRepo.On("GetTaskByID", mock.Anything, after.ID.Hex()).Return(after, nil).Once()
+	return task, before, after
+}
+
+// rewritten verifica que el reordenamiento conserve el orden y envíe la versión leída de cada tarea
+func rewritten(versions ...int64) any {
+	return mock.MatchedBy(func(tasks []models.Task) bool {
+		if len(tasks) != len(versions) {
+			return false
+		}
+		for i, task := range tasks {
+			if task.Position != float64(i+1)*services.PositionStep || task.Version != versions[i] {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestMoveTask_RebalancesListWithReadVersions(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, before, after := crowdedList(mockRepo)
+
+	mockRepo.On("GetTasksByList", mock.Anything, "board", "list").Return([]models.Task{*before, *after, *task}, nil)
+	mockRepo.On("SetTaskPositions", mock.Anything, rewritten(2, 5, 1)).Return(nil)
+	// Después de reordenar los vecinos se leen con sus nuevas posiciones
+	mockRepo.On("GetTaskByID", mock.Anything, before.ID.Hex()).Return(&models.Task{ID: before.ID, BoardID: "board", ListID: "list", Position: 1024, Version: 3}, nil)
+	mockRepo.On("GetTaskByID", mock.Anything, after.ID.Hex()).Return(&models.Task{ID: after.ID, BoardID: "board", ListID: "list", Position: 2048, Version: 6}, nil)
+	mockRepo.On("UpdateTaskPlacement", mock.Anything, task.ID, int64(2), "board", "list", 1536.0, task.Status, false).Return(nil)
+	mockRepo.On("GetTaskIDsAtPosition", mock.Anything, "board", "list", 1536.0, task.ID).Return([]primitive.ObjectID{}, nil)
+
+	err := service.MoveTask(context.Background(), task, services.TaskPlacement{BeforeID: before.ID.Hex(), AfterID: after.ID.Hex()}, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1536.0, task.Position)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMoveTask_RebalanceRetriesOnConcurrentChange(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, before, after := crowdedList(mockRepo)
+
+	// Otra petición modifica la lista entre la lectura y el reordenamiento, se relee con las versiones nuevas
+	mockRepo.On("GetTasksByList", mock.Anything, "board", "list").Return([]models.Task{*before, *after}, nil).Once()
+	mockRepo.On("SetTaskPositions", mock.Anything, rewritten(2, 5)).Return(repositories.ErrVersionConflict).Once()
+	changedAfter := *after
+	changedAfter.Version = 6
+	mockRepo.On("GetTasksByList", mock.Anything, "board", "list").Return([]models.Task{*before, changedAfter}, nil).Once()
+	mockRepo.On("SetTaskPositions", mock.Anything, rewritten(2, 6)).Return(nil).Once()
+	mockRepo.On("GetTaskByID", mock.Anything, before.ID.Hex()).Return(&models.Task{ID: before.ID, BoardID: "board", ListID: "list", Position: 1024}, nil)
+	mockRepo.On("GetTaskByID", mock.Anything, after.ID.Hex()).Return(&models.Task{ID: after.ID, BoardID: "board", ListID: "list", Position: 2048}, nil)
+	mockRepo.On("UpdateTaskPlacement", mock.Anything, task.ID, int64(1), "board", "list", 1536.0, task.Status, false).Return(nil)
+	mockRepo.On("GetTaskIDsAtPosition", mock.Anything, "board", "list", 1536.0, task.ID).Return([]primitive.ObjectID{}, nil)
+
+	err := service.MoveTask(context.Background(), task, services.TaskPlacement{BeforeID: before.ID.Hex(), AfterID: after.ID.Hex()}, 1)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestMoveTask_RebalanceGivesUpAfterRetries(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task, before, after := crowdedList(mockRepo)
+
+	mockRepo.On("GetTasksByList", mock.Anything, "board", "list").Return([]models.Task{*before, *after}, nil)
+	mockRepo.On("SetTaskPositions", mock.Anything, mock.Anything).Return(repositories.ErrVersionConflict)
+
+	err := service.MoveTask(context.Background(), task, services.TaskPlacement{BeforeID: before.ID.Hex(), AfterID: after.ID.Hex()}, 1)
+
+	assert.ErrorIs(t, err, services.ErrNeighborConflict)
+	mockRepo.AssertNumberOfCalls(t, "SetTaskPositions", 3)
+	mockRepo.AssertNotCalled(t, "UpdateTaskPlacement", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
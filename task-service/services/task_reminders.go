@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/infra/kafka"
+	"github.com/vadgun/gotrelloclone/task-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/task-service/infra/metrics"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidDates indica que la fecha de inicio de la tarea es posterior a su fecha límite
+var ErrInvalidDates = errors.New("la fecha de inicio no puede ser posterior a la fecha límite")
+
+// validateDates verifica que la fecha de inicio no sea posterior a la fecha límite cuando la tarea tiene ambas
+func validateDates(startAt, dueAt *time.Time) error {
+	if startAt != nil && dueAt != nil && startAt.After(*dueAt) {
+		return ErrInvalidDates
+	}
+	return nil
+}
+
+// patchedDate devuelve la fecha que queda en la tarea después del patch, la fecha cero la borra
+func patchedDate(current, patched *time.Time) *time.Time {
+	switch {
+	case patched == nil:
+		return current
+	case patched.IsZero():
+		return nil
+	default:
+		return patched
+	}
+}
+
+// Reminder es uno de los recordatorios de la fecha límite de una tarea
+type Reminder struct {
+	Key    string        // models.TaskDueSoonEvent o models.TaskOverdueEvent
+	Offset time.Duration // Antelación respecto a la fecha límite, cero en task-overdue
+	At     time.Time     // Momento en que se publica
+}
+
+// ReminderSchedule devuelve en orden cronológico los recordatorios de la fecha límite:
+// un task-due-soon por cada antelación y task-overdue al vencer
+func ReminderSchedule(dueAt time.Time, offsets []time.Duration) []Reminder {
+	sorted := slices.Clone(offsets)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+	slices.Reverse(sorted)
+
+	reminders := []Reminder{}
+	for _, offset := range sorted {
+		if offset > 0 {
+			reminders = append(reminders, Reminder{Key: models.TaskDueSoonEvent, Offset: offset, At: dueAt.Add(-offset)})
+		}
+	}
+	return append(reminders, Reminder{Key: models.TaskOverdueEvent, At: dueAt})
+}
+
+// DueReminder devuelve el recordatorio que toca publicar en now, si no se publicó ya uno igual o posterior.
+// Si varios recordatorios quedaron atrasados (por ejemplo con el servicio detenido) solo se publica el más
+// reciente, los avisos anteriores ya no describen el estado de la tarea.
+func DueReminder(dueAt time.Time, remindedAt *time.Time, offsets []time.Duration, now time.Time) (Reminder, bool) {
+	var due Reminder
+	found := false
+	for _, reminder := range ReminderSchedule(dueAt, offsets) {
+		if reminder.At.After(now) {
+			break
+		}
+		due, found = reminder, true
+	}
+	if !found || (remindedAt != nil && !due.At.After(*remindedAt)) {
+		return Reminder{}, false
+	}
+	return due, true
+}
+
+// NextReminderAt devuelve cuándo toca el siguiente recordatorio después de now, false si ya no quedan
+func NextReminderAt(dueAt time.Time, offsets []time.Duration, now time.Time) (time.Time, bool) {
+	for _, reminder := range ReminderSchedule(dueAt, offsets) {
+		if reminder.At.After(now) {
+			return reminder.At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ReminderEventID identifica el recordatorio de forma determinista: si se publica dos veces
+// (una réplica se cayó antes de guardar que ya lo publicó) notification-service lo descarta
+func ReminderEventID(taskID string, dueAt time.Time, reminder Reminder) string {
+	return fmt.Sprintf("%s:%s:%d:%s", taskID, reminder.Key, dueAt.Unix(), reminder.Offset)
+}
+
+// maxOffset devuelve la mayor antelación, las tareas que vencen más allá no tienen recordatorios pendientes
+func maxOffset(offsets []time.Duration) time.Duration {
+	var longest time.Duration
+	for _, offset := range offsets {
+		longest = max(longest, offset)
+	}
+	return longest
+}
+
+// SendReminders publica los recordatorios pendientes y devuelve cuántos se publicaron. Cada tarea se aparta durante lease
+// antes de publicar, así varias réplicas pueden ejecutarlo al mismo tiempo y una tarea de una réplica caída se retoma al vencer.
+func (s *TaskService) SendReminders(ctx context.Context, offsets []time.Duration, lease time.Duration) (int, error) {
+	sent := 0
+	for {
+		now := time.Now()
+		task, err := s.repo.ClaimReminderTask(ctx, now, now.Add(maxOffset(offsets)), lease)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return sent, nil
+		}
+		if err != nil {
+			return sent, err
+		}
+
+		published, err := s.remind(ctx, task, offsets, now)
+		if err != nil {
+			// La tarea queda apartada y se vuelve a intentar al vencer lease
+			logger.Log.Error("❌ Error publicando el recordatorio de la tarea", zap.String("taskID", task.ID.Hex()), zap.Error(err))
+			continue
+		}
+		if published {
+			sent++
+		}
+	}
+}
+
+// remind publica el recordatorio que le toca a la tarea, si alguno, y guarda cuándo revisarla otra vez.
+// El recordatorio se publica antes de guardarse, si la réplica se cae entre ambos pasos se vuelve a publicar con el mismo event_id.
+func (s *TaskService) remind(ctx context.Context, task *models.Task, offsets []time.Duration, now time.Time) (bool, error) {
+	dueAt := *task.DueAt
+	remindedAt := task.RemindedAt
+
+	reminder, ok := DueReminder(dueAt, remindedAt, offsets, now)
+	if ok {
+		event := models.TaskEvent{Task: *prepareTask(task), EventID: ReminderEventID(task.ID.Hex(), dueAt, reminder)}
+		eventJSON, err := json.Marshal(event)
+		if err != nil {
+			return false, err
+		}
+		if err := kafka.ProduceMessageAndWait(string(eventJSON), "task-events", reminder.Key); err != nil {
+			return false, err
+		}
+		metrics.TaskRemindersTotal.WithLabelValues(reminder.Key).Inc()
+		remindedAt = &reminder.At
+	}
+
+	var next *time.Time
+	if nextAt, pending := NextReminderAt(dueAt, offsets, now); pending {
+		next = &nextAt
+	}
+	return ok, s.repo.SaveReminderState(ctx, task.ID, dueAt, remindedAt, next)
+}
+
+// StartReminderScheduler publica los recordatorios pendientes cada interval hasta que se cancele ctx
+func (s *TaskService) StartReminderScheduler(ctx context.Context, offsets []time.Duration, interval, lease time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.SendReminders(ctx, offsets, lease)
+			if err != nil {
+				logger.Log.Error("❌ Error buscando recordatorios de tareas", zap.Error(err))
+			} else if sent > 0 {
+				logger.Log.Info("⏰ Recordatorios de tareas publicados", zap.Int("reminders", sent))
+			}
+		}
+	}
+}
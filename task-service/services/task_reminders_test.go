@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+var reminderOffsets = []time.Duration{time.Hour, 24 * time.Hour}
+
+func TestDueReminder(t *testing.T) {
+	due := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	_, ok := services.DueReminder(due, nil, reminderOffsets, due.Add(-25*time.Hour))
+	assert.False(t, ok)
+
+	reminder, ok := services.DueReminder(due, nil, reminderOffsets, due.Add(-23*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, models.TaskDueSoonEvent, reminder.Key)
+	assert.Equal(t, 24*time.Hour, reminder.Offset)
+
+	// Ya publicado, no se repite
+	_, ok = services.DueReminder(due, &reminder.At, reminderOffsets, due.Add(-2*time.Hour))
+	assert.False(t, ok)
+
+	reminder, _ = services.DueReminder(due, &reminder.At, reminderOffsets, due.Add(-30*time.Minute))
+	assert.Equal(t, time.Hour, reminder.Offset)
+}
+
+func TestDueReminder_OnlyLatestAfterDowntime(t *testing.T) {
+	due := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	reminder, ok := services.DueReminder(due, nil, reminderOffsets, due.Add(time.Minute))
+
+	assert.True(t, ok)
+	assert.Equal(t, models.TaskOverdueEvent, reminder.Key)
+	assert.Equal(t, due, reminder.At)
+}
+
+func TestNextReminderAt(t *testing.T) {
+	due := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	next, ok := services.NextReminderAt(due, reminderOffsets, due.Add(-2*time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, due.Add(-time.Hour), next)
+
+	_, ok = services.NextReminderAt(due, reminderOffsets, due)
+	assert.False(t, ok)
+}
+
+func TestReminderEventID_IsDeterministic(t *testing.T) {
+	due := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	reminder, _ := services.DueReminder(due, nil, reminderOffsets, due.Add(-time.Minute))
+
+	id := services.ReminderEventID("task", due, reminder)
+
+	assert.Equal(t, id, services.ReminderEventID("task", due, reminder))
+	assert.NotEqual(t, id, services.ReminderEventID("task", due.Add(time.Hour), reminder))
+}
+
+func TestParseTaskPatch_Dates(t *testing.T) {
+	patch, errs := services.ParseTaskPatch(parseBody(t, `{"due_at": "2026-03-10T12:00:00-06:00", "start_at": null, "completed": true}`))
+
+	assert.Nil(t, errs)
+	assert.Equal(t, time.Date(2026, 3, 10, 18, 0, 0, 0, time.UTC), *patch.DueAt)
+	assert.True(t, patch.StartAt.IsZero())
+	assert.True(t, *patch.Completed)
+
+	_, errs = services.ParseTaskPatch(parseBody(t, `{"due_at": "mañana", "completed": "si"}`))
+	assert.Contains(t, errs, "due_at")
+	assert.Contains(t, errs, "completed")
+}
@@ -2,48 +2,122 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"github.com/vadgun/gotrelloclone/task-service/infra/kafka"
 	"github.com/vadgun/gotrelloclone/task-service/models"
 	"github.com/vadgun/gotrelloclone/task-service/repositories"
-
-	"go.mongodb.org/mongo-driver/bson"
 )
 
 type TaskService struct {
-	repo *repositories.TaskRepository
+	repo repositories.TaskRepositoryInterface
 }
 
-func NewTaskService(repo *repositories.TaskRepository) *TaskService {
+func NewTaskService(repo repositories.TaskRepositoryInterface) *TaskService {
 	return &TaskService{repo: repo}
 }
 
+// CreateTask crea la tarea al final de su lista, si no se indica lista se usa la primera del board
+// y si no se indica estado se usa el inicial del flujo del board. Las checklists se agregan después con sus endpoints.
+// Una tarea nueva nunca está archivada ni en la papelera aunque el cliente envíe archived, deleted_at o deleted_with_board.
 func (s *TaskService) CreateTask(ctx context.Context, task *models.Task, userID string) (interface{}, error) {
+	task.Checklists = []models.Checklist{}
+	task.Archived = false
+	task.DeletedAt, task.DeletedWithBoard = nil, false
+	if err := validateDates(task.StartAt, task.DueAt); err != nil {
+		return nil, err
+	}
+	if err := validatePlanning(task); err != nil {
+		return nil, err
+	}
+	// Igual que al asignar, solo se puede crear asignada a un miembro del board
+	if task.AssigneeID != "" {
+		isMember, err := s.IsBoardMember(ctx, task.BoardID, task.AssigneeID)
+		if err != nil {
+			return nil, err
+		}
+		if !isMember {
+			return nil, ErrAssigneeNotMember
+		}
+	}
+	task.CompletedAt = nil
+	if task.Completed {
+		now := time.Now()
+		task.CompletedAt = &now
+	}
+	labels, err := s.validateLabels(ctx, task.BoardID, task.Labels)
+	if err != nil {
+		return nil, err
+	}
+	task.Labels = labels
+	if err := s.initialTaskStatus(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := s.placeNewTask(ctx, task); err != nil {
+		return nil, err
+	}
 	return s.repo.CreateTask(ctx, task, userID)
 }
 
-func (s *TaskService) GetTasksByBoardID(ctx context.Context, boardID string, page, limit int64) ([]models.Task, int64, error) {
-	return s.repo.GetTasksByBoardID(ctx, boardID, page, limit)
+// GetTasksByBoardID devuelve una página de tareas del board con el avance de sus checklists
+func (s *TaskService) GetTasksByBoardID(ctx context.Context, boardID string, filter models.TaskFilter, page, limit int64) ([]models.Task, int64, error) {
+	tasks, total, err := s.repo.GetTasksByBoardID(ctx, boardID, filter, page, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range tasks {
+		prepareTask(&tasks[i])
+	}
+	return tasks, total, nil
 }
 
+// GetTaskByID obtiene la tarea con el avance de sus checklists
 func (s *TaskService) GetTaskByID(ctx context.Context, taskID string) (*models.Task, error) {
-	return s.repo.GetTaskByID(ctx, taskID)
+	task, err := s.repo.GetTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return prepareTask(task), nil
 }
 
-func (s *TaskService) UpdateTask(ctx context.Context, taskID string, updatedData bson.M) error {
-	return s.repo.UpdateTask(ctx, taskID, updatedData)
+// UpdateTask aplica el patch si la tarea sigue en la versión indicada, si no devuelve repositories.ErrVersionConflict.
+// Las etiquetas del patch deben existir en el board de la tarea, si no devuelve ErrUnknownLabel,
+// y la fecha de inicio resultante no puede ser posterior a la fecha límite, si no devuelve ErrInvalidDates.
+func (s *TaskService) UpdateTask(ctx context.Context, task *models.Task, patch *models.TaskPatch, version int64) error {
+	if err := validateDates(patchedDate(task.StartAt, patch.StartAt), patchedDate(task.DueAt, patch.DueAt)); err != nil {
+		return err
+	}
+	if patch.Completed != nil && *patch.Completed == task.Completed {
+		// Sin cambio, se conserva la fecha en que se completó
+		patch.Completed = nil
+	}
+	if patch.Labels != nil {
+		labels, err := s.validateLabels(ctx, task.BoardID, *patch.Labels)
+		if err != nil {
+			return err
+		}
+		patch.Labels = &labels
+	}
+	return s.repo.UpdateTask(ctx, task.ID.Hex(), patch, version)
 }
 
-func (s *TaskService) DeleteTask(ctx context.Context, taskID string) error {
-	return s.repo.DeleteTask(ctx, taskID)
+func (s *TaskService) DeleteTask(ctx context.Context, taskID string, version int64) error {
+	return s.repo.DeleteTask(ctx, taskID, version)
 }
 
-func (s *TaskService) MoveTask(ctx context.Context, taskID, newBoardID string) error {
-	return s.repo.UpdateTaskBoard(ctx, taskID, newBoardID)
+// ArchiveTask archiva o desarchiva la tarea si sigue en la versión que editó el cliente y la devuelve actualizada,
+// si no devuelve repositories.ErrVersionConflict
+func (s *TaskService) ArchiveTask(ctx context.Context, taskID string, archived bool, version int64) (*models.Task, error) {
+	if err := s.repo.SetTaskArchived(ctx, taskID, archived, version); err != nil {
+		return nil, err
+	}
+	return s.GetTaskByID(ctx, taskID)
 }
 
-func (s *TaskService) AssignTask(ctx context.Context, taskID, userID string) error {
-	return s.repo.UpdateTaskAssignee(ctx, taskID, userID)
+// AssignTask asigna la tarea al usuario si sigue en la versión que editó el cliente, si no devuelve repositories.ErrVersionConflict
+func (s *TaskService) AssignTask(ctx context.Context, taskID, userID string, version int64) error {
+	return s.repo.UpdateTaskAssignee(ctx, taskID, userID, version)
 }
 
 func (s *TaskService) UserExists(ctx context.Context, userID string) (bool, error) {
@@ -54,26 +128,32 @@ func (s *TaskService) UserExists(ctx context.Context, userID string) (bool, erro
 	return true, nil
 }
 
-func (s *TaskService) UpdateTaskStatus(ctx context.Context, taskID string, status models.TaskStatus) (err error) {
-	if err := s.repo.UpdateTaskStatus(ctx, taskID, status); err != nil {
-		return err
-	}
+func (s *TaskService) SendNotification(userID, message, topic, key string) error {
+	err := kafka.ProduceMessage(userID, message, topic, key)
 	return err
 }
 
-func (s *TaskService) BoardExists(ctx context.Context, boardID string) (bool, error) {
-	_, err := s.repo.GetBoardByID(boardID)
+// PublishTaskEvent publica en task-events el evento de la tarea para notification-service
+func (s *TaskService) PublishTaskEvent(key string, event models.TaskEvent) error {
+	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		return false, err
+		return err
 	}
-	return true, nil
-}
-
-func (s *TaskService) SendNotification(userID, message, topic, key string) error {
-	err := kafka.ProduceMessage(userID, message, topic, key)
-	return err
+	return kafka.ProduceMessage(event.ActorID, string(eventJSON), "task-events", key)
 }
 
 func (s *TaskService) GetAllTasks() ([]models.Task, error) {
 	return s.repo.GetAllTasks()
 }
+
+// prepareTask completa los campos calculados de la tarea y cambia las listas ausentes por listas vacías
+func prepareTask(task *models.Task) *models.Task {
+	if task.Labels == nil {
+		task.Labels = []string{}
+	}
+	if task.Checklists == nil {
+		task.Checklists = []models.Checklist{}
+	}
+	task.Progress = ChecklistProgressOf(task.Checklists)
+	return task
+}
@@ -0,0 +1,75 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+// createTaskRepo prepara el board con el flujo por defecto y sin listas, la tarea nueva queda al inicio
+func createTaskRepo(boardID string) *repomocks.MockTaskRepo {
+	mockRepo := new(repomocks.MockTaskRepo)
+	mockRepo.On("GetBoardByID", boardID).Return(memberBoard(), nil)
+	mockRepo.On("GetFirstList", mock.Anything, boardID).Return(nil, nil)
+	mockRepo.On("GetNeighborTask", mock.Anything, boardID, "", mock.Anything, false, mock.Anything).Return(nil, nil)
+	return mockRepo
+}
+
+func TestTaskService_CreateTask_IgnoresArchived(t *testing.T) {
+	mockRepo := createTaskRepo("board")
+	service := services.NewTaskService(mockRepo)
+	task := &models.Task{Title: "Nueva", BoardID: "board", Archived: true}
+
+	mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return !task.Archived
+	}), "editor").Return("id", nil)
+
+	_, err := service.CreateTask(context.Background(), task, "editor")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_CreateTask_AssigneeMustBeMember(t *testing.T) {
+	board := memberBoard()
+	boardID := board.ID.Hex()
+	mockRepo := createTaskRepo(boardID)
+	service := services.NewTaskService(mockRepo)
+
+	// Igual que en AssignTask, no se puede crear una tarea asignada a alguien fuera del board
+	_, err := service.CreateTask(context.Background(), &models.Task{Title: "Nueva", BoardID: boardID, AssigneeID: "stranger"}, "editor")
+
+	assert.ErrorIs(t, err, services.ErrAssigneeNotMember)
+	mockRepo.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything, mock.Anything)
+
+	mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.AssigneeID == "viewer"
+	}), "editor").Return("id", nil)
+
+	_, err = service.CreateTask(context.Background(), &models.Task{Title: "Nueva", BoardID: boardID, AssigneeID: "viewer"}, "editor")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_CreateTask_IgnoresTrashFields(t *testing.T) {
+	mockRepo := createTaskRepo("board")
+	service := services.NewTaskService(mockRepo)
+	deletedAt := time.Now()
+	task := &models.Task{Title: "Nueva", BoardID: "board", DeletedAt: &deletedAt, DeletedWithBoard: true}
+
+	mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.DeletedAt == nil && !task.DeletedWithBoard
+	}), "editor").Return("id", nil)
+
+	_, err := service.CreateTask(context.Background(), task, "editor")
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+)
+
+// MaxTaskEstimate es la mayor estimación en story points que se acepta
+const MaxTaskEstimate = 1000
+
+var (
+	ErrInvalidPriority = errors.New("la prioridad debe ser P0, P1, P2 o P3")
+	ErrInvalidEstimate = errors.New("la estimación debe ser un número entre 0 y 1000")
+	ErrInvalidSort     = errors.New("sort debe ser priority, due, created, updated o position y order asc o desc")
+)
+
+// validatePlanning verifica la prioridad y la estimación de una tarea nueva y calcula sus claves de orden
+// por prioridad y fecha límite
+func validatePlanning(task *models.Task) error {
+	if !task.Priority.Valid() {
+		return ErrInvalidPriority
+	}
+	if task.Estimate != nil && !validEstimate(*task.Estimate) {
+		return ErrInvalidEstimate
+	}
+	task.PriorityRank = task.Priority.Rank()
+	task.NoPriority = task.Priority == ""
+	task.NoDueAt = task.DueAt == nil
+	return nil
+}
+
+func validEstimate(estimate float64) bool {
+	return estimate >= 0 && estimate <= MaxTaskEstimate
+}
+
+// ParseTaskSort convierte los parámetros sort y order del listado, vacíos ordenan por lista y posición ascendente
+func ParseTaskSort(field, order string) (models.TaskSort, error) {
+	if field == "" {
+		field = models.SortByPosition
+	}
+	if !slices.Contains(models.TaskSortFields, field) || (order != "" && order != "asc" && order != "desc") {
+		return models.TaskSort{}, ErrInvalidSort
+	}
+	return models.TaskSort{Field: field, Descending: order == "desc"}, nil
+}
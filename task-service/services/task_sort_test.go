@@ -0,0 +1,179 @@
+package services_test
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/repositories"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestParseTaskSort(t *testing.T) {
+	sort, err := services.ParseTaskSort("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TaskSort{Field: models.SortByPosition}, sort)
+
+	sort, err = services.ParseTaskSort("due", "desc")
+	assert.NoError(t, err)
+	assert.Equal(t, models.TaskSort{Field: models.SortByDue, Descending: true}, sort)
+
+	_, err = services.ParseTaskSort("title", "asc")
+	assert.ErrorIs(t, err, services.ErrInvalidSort)
+
+	_, err = services.ParseTaskSort("priority", "up")
+	assert.ErrorIs(t, err, services.ErrInvalidSort)
+}
+
+func TestTaskPriorityRank(t *testing.T) {
+	assert.Equal(t, 0, models.PriorityP0.Rank())
+	assert.Equal(t, 3, models.PriorityP3.Rank())
+	assert.Equal(t, 4, models.TaskPriority("").Rank())
+	assert.False(t, models.TaskPriority("P9").Valid())
+}
+
+func TestParseTaskPatch_Planning(t *testing.T) {
+	patch, errs := services.ParseTaskPatch(parseBody(t, `{"priority": "P1", "estimate": 0}`))
+
+	assert.Nil(t, errs)
+	assert.Equal(t, models.PriorityP1, *patch.Priority)
+	assert.Equal(t, 0.0, *patch.Estimate)
+	assert.False(t, patch.ClearEstimate)
+
+	patch, errs = services.ParseTaskPatch(parseBody(t, `{"priority": null, "estimate": null}`))
+	assert.Nil(t, errs)
+	assert.Equal(t, models.TaskPriority(""), *patch.Priority)
+	assert.True(t, patch.ClearEstimate)
+
+	_, errs = services.ParseTaskPatch(parseBody(t, `{"priority": "urgente", "estimate": -3}`))
+	assert.Contains(t, errs, "priority")
+	assert.Contains(t, errs, "estimate")
+}
+
+// compareField compara dos tareas en un campo como lo hace Mongo: false antes que true y null antes que cualquier fecha
+func compareField(a, b models.Task, key string) int {
+	switch key {
+	case "no_priority":
+		return cmp.Compare(boolRank(a.NoPriority), boolRank(b.NoPriority))
+	case "priority_rank":
+		return cmp.Compare(a.PriorityRank, b.PriorityRank)
+	case "no_due_at":
+		return cmp.Compare(boolRank(a.NoDueAt), boolRank(b.NoDueAt))
+	case "due_at":
+		switch {
+		case a.DueAt == nil || b.DueAt == nil:
+			return cmp.Compare(boolRank(a.DueAt != nil), boolRank(b.DueAt != nil))
+		default:
+			return a.DueAt.Compare(*b.DueAt)
+		}
+	case "_id":
+		return cmp.Compare(a.ID.Hex(), b.ID.Hex())
+	}
+	panic("clave de orden sin comparar en el test: " + key)
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// sortTitles ordena en memoria las tareas con las claves que recibe Mongo y devuelve sus títulos
+func sortTitles(tasks []models.Task, sort models.TaskSort) []string {
+	keys := repositories.TaskSortKeys(sort)
+	sorted := slices.Clone(tasks)
+	slices.SortFunc(sorted, func(a, b models.Task) int {
+		for _, key := range keys {
+			if c := compareField(a, b, key.Key); c != 0 {
+				return c * key.Value.(int)
+			}
+		}
+		return 0
+	})
+
+	titles := []string{}
+	for _, task := range sorted {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+// plannedTask arma la tarea con sus claves de orden como las deja CreateTask
+func plannedTask(title string, priority models.TaskPriority, dueAt *time.Time) models.Task {
+	return models.Task{
+		ID:           primitive.NewObjectID(),
+		Title:        title,
+		Priority:     priority,
+		PriorityRank: priority.Rank(),
+		NoPriority:   priority == "",
+		DueAt:        dueAt,
+		NoDueAt:      dueAt == nil,
+	}
+}
+
+func TestTaskSortKeys_MissingPriorityLast(t *testing.T) {
+	tasks := []models.Task{
+		plannedTask("sin prioridad", "", nil),
+		plannedTask("P2", models.PriorityP2, nil),
+		plannedTask("P0", models.PriorityP0, nil),
+	}
+
+	assert.Equal(t, []string{"P0", "P2", "sin prioridad"}, sortTitles(tasks, models.TaskSort{Field: models.SortByPriority}))
+	assert.Equal(t, []string{"P2", "P0", "sin prioridad"}, sortTitles(tasks, models.TaskSort{Field: models.SortByPriority, Descending: true}))
+}
+
+func TestTaskSortKeys_MissingDueLast(t *testing.T) {
+	today := time.Now()
+	tomorrow := today.Add(24 * time.Hour)
+	tasks := []models.Task{
+		plannedTask("mañana", "", &tomorrow),
+		plannedTask("sin fecha", "", nil),
+		plannedTask("hoy", "", &today),
+	}
+
+	assert.Equal(t, []string{"hoy", "mañana", "sin fecha"}, sortTitles(tasks, models.TaskSort{Field: models.SortByDue}))
+	assert.Equal(t, []string{"mañana", "hoy", "sin fecha"}, sortTitles(tasks, models.TaskSort{Field: models.SortByDue, Descending: true}))
+}
+
+func TestTaskSortKeys_TiesByID(t *testing.T) {
+	first, second := plannedTask("primera", models.PriorityP1, nil), plannedTask("segunda", models.PriorityP1, nil)
+	tasks := []models.Task{second, first}
+
+	// El desempate por _id sigue la dirección pedida para que las páginas no se mezclen
+	assert.Equal(t, []string{"primera", "segunda"}, sortTitles(tasks, models.TaskSort{Field: models.SortByPriority}))
+	assert.Equal(t, []string{"segunda", "primera"}, sortTitles(tasks, models.TaskSort{Field: models.SortByPriority, Descending: true}))
+}
+
+func TestTaskService_CreateTask_SetsSortKeys(t *testing.T) {
+	dueAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		task       *models.Task
+		noPriority bool
+		noDueAt    bool
+	}{
+		{task: &models.Task{Title: "sin planear", BoardID: "board"}, noPriority: true, noDueAt: true},
+		{task: &models.Task{Title: "planeada", BoardID: "board", Priority: models.PriorityP1, DueAt: &dueAt}},
+		// El cliente no puede mandar sus propias claves de orden
+		{task: &models.Task{Title: "claves falsas", BoardID: "board", Priority: models.PriorityP0, DueAt: &dueAt, NoPriority: true, NoDueAt: true}},
+	}
+
+	for _, tt := range tests {
+		mockRepo := createTaskRepo("board")
+		service := services.NewTaskService(mockRepo)
+		mockRepo.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+			return task.NoPriority == tt.noPriority && task.NoDueAt == tt.noDueAt
+		}), "editor").Return("id", nil)
+
+		_, err := service.CreateTask(context.Background(), tt.task, "editor")
+
+		assert.NoError(t, err, tt.task.Title)
+		mockRepo.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/vadgun/gotrelloclone/task-service/infra/logger"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"go.uber.org/zap"
+)
+
+// ErrTaskNotTrashed indica que la tarea no existe o ya no está en la papelera
+var ErrTaskNotTrashed = errors.New("la tarea no está en la papelera")
+
+// GetTrash devuelve las tareas del board que están en la papelera
+func (s *TaskService) GetTrash(ctx context.Context, boardID string) ([]models.Task, error) {
+	return s.repo.GetTrashedTasks(ctx, boardID)
+}
+
+// GetTrashedTask obtiene una tarea de la papelera, si no está devuelve ErrTaskNotTrashed
+func (s *TaskService) GetTrashedTask(ctx context.Context, taskID string) (*models.Task, error) {
+	task, err := s.repo.GetTrashedTaskByID(ctx, taskID)
+	if err != nil {
+		return nil, ErrTaskNotTrashed
+	}
+	return task, nil
+}
+
+// RestoreTask saca la tarea de la papelera y la devuelve actualizada, vuelve a su lista y posición
+func (s *TaskService) RestoreTask(ctx context.Context, task *models.Task) (*models.Task, error) {
+	restored, err := s.repo.RestoreTask(ctx, task.ID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !restored {
+		return nil, ErrTaskNotTrashed
+	}
+	return s.GetTaskByID(ctx, task.ID.Hex())
+}
+
+// PurgeTrash elimina definitivamente las tareas que llevan en la papelera más de retention
+func (s *TaskService) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.PurgeTrashedTasks(ctx, time.Now().Add(-retention))
+}
+
+// StartTrashPurge purga la papelera cada interval hasta que se cancele ctx
+func (s *TaskService) StartTrashPurge(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeTrash(ctx, retention)
+			if err != nil {
+				logger.Log.Error("❌ Error purgando la papelera de tareas", zap.Error(err))
+			} else if purged > 0 {
+				logger.Log.Info("🧹 Tareas eliminadas definitivamente de la papelera", zap.Int64("tasks", purged))
+			}
+		}
+	}
+}
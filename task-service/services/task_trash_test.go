@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	repomocks "github.com/vadgun/gotrelloclone/task-service/repositories/mocks"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTaskService_GetTrashedTask_NotTrashed(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+
+	mockRepo.On("GetTrashedTaskByID", mock.Anything, "task").Return(nil, errors.New("mongo: no documents in result"))
+
+	task, err := service.GetTrashedTask(context.Background(), "task")
+
+	assert.Nil(t, task)
+	assert.ErrorIs(t, err, services.ErrTaskNotTrashed)
+}
+
+func TestTaskService_RestoreTask(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task := &models.Task{ID: primitive.NewObjectID(), BoardID: "board"}
+
+	// Una tarea eliminada por separado se restaura sola, no junto con su tablero
+	mockRepo.On("RestoreTask", mock.Anything, task.ID, false).Return(true, nil)
+	mockRepo.On("GetTaskByID", mock.Anything, task.ID.Hex()).Return(&models.Task{ID: task.ID, BoardID: "board", Version: 3}, nil)
+
+	restored, err := service.RestoreTask(context.Background(), task)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), restored.Version)
+	assert.Nil(t, restored.DeletedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_RestoreTask_AlreadyRestored(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	task := &models.Task{ID: primitive.NewObjectID()}
+
+	// Otra petición la restauró o la purga la eliminó entre la lectura y la restauración
+	mockRepo.On("RestoreTask", mock.Anything, task.ID, false).Return(false, nil)
+
+	restored, err := service.RestoreTask(context.Background(), task)
+
+	assert.Nil(t, restored)
+	assert.ErrorIs(t, err, services.ErrTaskNotTrashed)
+	mockRepo.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_PurgeTrash_UsesRetentionCutoff(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	retention := 30 * 24 * time.Hour
+	expected := time.Now().Add(-retention)
+
+	mockRepo.On("PurgeTrashedTasks", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return cutoff.Sub(expected).Abs() < time.Second
+	})).Return(int64(4), nil)
+
+	purged, err := service.PurgeTrash(context.Background(), retention)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskService_StartTrashPurge_StopsWithContext(t *testing.T) {
+	mockRepo := new(repomocks.MockTaskRepo)
+	service := services.NewTaskService(mockRepo)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	purged := make(chan struct{}, 1)
+	mockRepo.On("PurgeTrashedTasks", mock.Anything, mock.Anything).Return(int64(0), nil).Run(func(mock.Arguments) {
+		select {
+		case purged <- struct{}{}:
+		default:
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		service.StartTrashPurge(ctx, time.Hour, time.Millisecond)
+		close(done)
+	}()
+
+	<-purged
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("StartTrashPurge no terminó al cancelar el contexto")
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/vadgun/gotrelloclone/task-service/models"
+)
+
+// ErrUnknownStatus indica que el estado no existe en el flujo del board
+var ErrUnknownStatus = errors.New("el estado no existe en el flujo del board")
+
+// TransitionError indica que el flujo del board no permite pasar de From a To, Allowed son los estados válidos desde From
+type TransitionError struct {
+	From    models.TaskStatus
+	To      models.TaskStatus
+	Allowed []models.TaskStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("no se permite cambiar el estado de %s a %s", e.From, e.To)
+}
+
+// HasStatus indica si el estado pertenece al flujo
+func HasStatus(workflow *models.Workflow, status models.TaskStatus) bool {
+	return slices.ContainsFunc(workflow.Statuses, func(s models.WorkflowStatus) bool {
+		return s.Key == status
+	})
+}
+
+// InitialStatus es el estado con el que se crean las tareas: el primero del flujo
+func InitialStatus(workflow *models.Workflow) models.TaskStatus {
+	if len(workflow.Statuses) == 0 {
+		return models.TODO
+	}
+	return workflow.Statuses[0].Key
+}
+
+// ValidateTransition verifica que el flujo permita pasar la tarea de from a to.
+// Si from ya no existe en el flujo (el owner lo eliminó) la tarea puede pasar a cualquier estado del flujo.
+func ValidateTransition(workflow *models.Workflow, from, to models.TaskStatus) error {
+	if !HasStatus(workflow, to) {
+		return ErrUnknownStatus
+	}
+	if from == to || !HasStatus(workflow, from) {
+		return nil
+	}
+
+	allowed := workflow.Transitions[from]
+	if !slices.Contains(allowed, to) {
+		return &TransitionError{From: from, To: to, Allowed: allowed}
+	}
+	return nil
+}
+
+// BoardWorkflow obtiene el flujo del board, el flujo por defecto si el board no tiene uno propio
+func (s *TaskService) BoardWorkflow(ctx context.Context, boardID string) (*models.Workflow, error) {
+	board, err := s.repo.GetBoardByID(boardID)
+	if err != nil {
+		return nil, err
+	}
+	if board.Workflow == nil {
+		return models.DefaultWorkflow(), nil
+	}
+	return board.Workflow, nil
+}
+
+// UpdateTaskStatus cambia el estado de la tarea si el flujo de su board lo permite.
+// El cambio solo se aplica si la tarea sigue en la versión que editó el cliente, si no devuelve repositories.ErrVersionConflict.
+func (s *TaskService) UpdateTaskStatus(ctx context.Context, task *models.Task, status models.TaskStatus, version int64) error {
+	workflow, err := s.BoardWorkflow(ctx, task.BoardID)
+	if err != nil {
+		return err
+	}
+
+	if err := ValidateTransition(workflow, task.Status, status); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateTaskStatus(ctx, task.ID.Hex(), status, version); err != nil {
+		return err
+	}
+	task.Status = status
+	task.Version = version + 1
+	return nil
+}
+
+// initialTaskStatus valida el estado de una tarea nueva, si no se indica se usa el estado inicial del flujo
+func (s *TaskService) initialTaskStatus(ctx context.Context, task *models.Task) error {
+	workflow, err := s.BoardWorkflow(ctx, task.BoardID)
+	if err != nil {
+		return err
+	}
+
+	if task.Status == "" {
+		task.Status = InitialStatus(workflow)
+		return nil
+	}
+	if !HasStatus(workflow, task.Status) {
+		return ErrUnknownStatus
+	}
+	return nil
+}
+
+// movedTaskStatus conserva el estado de la tarea si existe en el flujo del board destino,
+// si no la pasa al estado inicial de ese flujo
+func (s *TaskService) movedTaskStatus(ctx context.Context, task *models.Task, boardID string) (models.TaskStatus, error) {
+	if boardID == task.BoardID {
+		return task.Status, nil
+	}
+
+	workflow, err := s.BoardWorkflow(ctx, boardID)
+	if err != nil {
+		return "", err
+	}
+	if HasStatus(workflow, task.Status) {
+		return task.Status, nil
+	}
+	return InitialStatus(workflow), nil
+}
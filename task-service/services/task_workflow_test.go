@@ -0,0 +1,49 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vadgun/gotrelloclone/task-service/models"
+	"github.com/vadgun/gotrelloclone/task-service/services"
+)
+
+func reviewWorkflow() *models.Workflow {
+	return &models.Workflow{
+		Statuses: []models.WorkflowStatus{
+			{Key: "BACKLOG", Name: "Backlog"},
+			{Key: "REVIEW", Name: "Review"},
+			{Key: "QA", Name: "QA"},
+			{Key: "DONE", Name: "Done"},
+		},
+		Transitions: map[models.TaskStatus][]models.TaskStatus{
+			"BACKLOG": {"REVIEW"},
+			"REVIEW":  {"QA", "BACKLOG"},
+			"QA":      {"DONE", "REVIEW"},
+		},
+	}
+}
+
+func TestValidateTransition(t *testing.T) {
+	workflow := reviewWorkflow()
+
+	assert.NoError(t, services.ValidateTransition(workflow, "BACKLOG", "REVIEW"))
+	assert.NoError(t, services.ValidateTransition(workflow, "QA", "DONE"))
+	assert.ErrorIs(t, services.ValidateTransition(workflow, "BACKLOG", "IN_PROGRESS"), services.ErrUnknownStatus)
+
+	var transitionErr *services.TransitionError
+	assert.ErrorAs(t, services.ValidateTransition(workflow, "BACKLOG", "DONE"), &transitionErr)
+	assert.Equal(t, []models.TaskStatus{"REVIEW"}, transitionErr.Allowed)
+
+	// DONE no tiene transiciones, es un estado final
+	assert.ErrorAs(t, services.ValidateTransition(workflow, "DONE", "QA"), &transitionErr)
+}
+
+func TestValidateTransition_FromRemovedStatus(t *testing.T) {
+	workflow := reviewWorkflow()
+
+	// Una tarea que quedó en un estado que ya no existe puede pasar a cualquier estado del flujo
+	assert.NoError(t, services.ValidateTransition(workflow, models.INPROGRESS, "QA"))
+	assert.Equal(t, models.TaskStatus("BACKLOG"), services.InitialStatus(workflow))
+	assert.Equal(t, models.TODO, services.InitialStatus(models.DefaultWorkflow()))
+}
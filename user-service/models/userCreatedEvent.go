@@ -1,5 +1,9 @@
 package models
 
+// UserCreatedEvent se publica en user-events con la key new-user.
+// Nombre y email los usa notification-service para el canal de correo.
 type UserCreatedEvent struct {
-	ID string `json:"id" bson:"_id"`
+	ID    string `json:"id" bson:"_id"`
+	Name  string `json:"name" bson:"name"`
+	Email string `json:"email" bson:"email"`
 }
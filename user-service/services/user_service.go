@@ -57,7 +57,9 @@ func (s *UserService) RegisterUser(name, email, password, phone, role string) (s
 	}
 
 	event := &models.UserCreatedEvent{
-		ID: id,
+		ID:    id,
+		Name:  name,
+		Email: email,
 	}
 
 	err = s.Kafka.Publish(context.TODO(), "new-user", event)